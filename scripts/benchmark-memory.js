@@ -0,0 +1,165 @@
+#!/usr/bin/env node
+
+/**
+ * Memory bank benchmark for KaskManager R&D Platform
+ * Compares exact and LSH nearest-neighbor search on build time, query
+ * latency and recall at several memory bank sizes
+ */
+
+import { parseArgs } from 'util';
+import { performance } from 'perf_hooks';
+import { ExactIndex, LSHIndex } from '../src/rnd-module/MemoryIndex.js';
+
+/**
+ * Synthetic feature vectors in [0, 1], grouped around random centers the way
+ * learned experiences cluster around recurring activity patterns
+ */
+function generateVectors(count, dims, clusters, spread) {
+  const centers = Array.from({ length: clusters }, () =>
+    Array.from({ length: dims }, () => Math.random())
+  );
+
+  return Array.from({ length: count }, () => {
+    const center = centers[Math.floor(Math.random() * clusters)];
+    return center.map((value) => Math.min(1, Math.max(0, value + (Math.random() - 0.5) * 2 * spread)));
+  });
+}
+
+function percentile(sorted, p) {
+  return sorted[Math.min(sorted.length - 1, Math.floor(sorted.length * p))];
+}
+
+function timeQueries(index, queries, k) {
+  const latencies = [];
+  const results = queries.map((query) => {
+    const start = performance.now();
+    const result = index.query(query, k);
+    latencies.push(performance.now() - start);
+    return result;
+  });
+
+  latencies.sort((a, b) => a - b);
+  return {
+    results,
+    avgMs: latencies.reduce((acc, value) => acc + value, 0) / latencies.length,
+    p95Ms: percentile(latencies, 0.95)
+  };
+}
+
+function build(index, vectors) {
+  const start = performance.now();
+  vectors.forEach((vector, i) => index.add(`experience_${i}`, vector));
+  return performance.now() - start;
+}
+
+/**
+ * Benchmark one memory bank size; recall is the share of the exact top-k
+ * that the approximate search also returned
+ */
+function runCase(size, options) {
+  // Queries come from the same clusters as the stored memories
+  const vectors = generateVectors(size + options.queries, options.dims, options.clusters, options.spread);
+  const queries = vectors.splice(size);
+
+  const exact = new ExactIndex();
+  const lsh = new LSHIndex(options.lsh);
+  const exactBuildMs = build(exact, vectors);
+  const lshBuildMs = build(lsh, vectors);
+
+  const exactRun = timeQueries(exact, queries, options.k);
+  const lshRun = timeQueries(lsh, queries, options.k);
+
+  const recall =
+    exactRun.results.reduce((acc, truth, i) => {
+      const found = new Set(lshRun.results[i].map((match) => match.key));
+      const hits = truth.filter((match) => found.has(match.key)).length;
+      return acc + hits / Math.max(1, truth.length);
+    }, 0) / queries.length;
+
+  return {
+    size,
+    exact: { buildMs: exactBuildMs, avgQueryMs: exactRun.avgMs, p95QueryMs: exactRun.p95Ms },
+    lsh: { buildMs: lshBuildMs, avgQueryMs: lshRun.avgMs, p95QueryMs: lshRun.p95Ms },
+    recall,
+    speedup: exactRun.avgMs / lshRun.avgMs
+  };
+}
+
+function printReport(report) {
+  console.log(
+    `\n📊 Memory index benchmark (k=${report.options.k}, ${report.options.queries} queries, ${report.options.dims} dims)\n`
+  );
+  console.table(
+    report.cases.map((result) => ({
+      size: result.size,
+      'exact build ms': result.exact.buildMs.toFixed(0),
+      'lsh build ms': result.lsh.buildMs.toFixed(0),
+      'exact avg ms': result.exact.avgQueryMs.toFixed(3),
+      'exact p95 ms': result.exact.p95QueryMs.toFixed(3),
+      'lsh avg ms': result.lsh.avgQueryMs.toFixed(3),
+      'lsh p95 ms': result.lsh.p95QueryMs.toFixed(3),
+      [`recall@${report.options.k}`]: result.recall.toFixed(3),
+      speedup: `${result.speedup.toFixed(1)}x`
+    }))
+  );
+}
+
+/**
+ * Main benchmark function
+ */
+async function main() {
+  const { values } = parseArgs({
+    options: {
+      sizes: { type: 'string', default: '10000,100000,1000000' },
+      queries: { type: 'string', default: '100' },
+      k: { type: 'string', default: '10' },
+      dims: { type: 'string', default: '16' },
+      clusters: { type: 'string', default: '50' },
+      spread: { type: 'string', default: '0.1' },
+      tables: { type: 'string', default: '8' },
+      hashes: { type: 'string', default: '4' },
+      'bucket-width': { type: 'string', default: '1.0' },
+      json: { type: 'boolean', default: false }
+    }
+  });
+
+  const options = {
+    queries: parseInt(values.queries, 10),
+    k: parseInt(values.k, 10),
+    dims: parseInt(values.dims, 10),
+    clusters: parseInt(values.clusters, 10),
+    spread: parseFloat(values.spread),
+    lsh: {
+      tables: parseInt(values.tables, 10),
+      hashes: parseInt(values.hashes, 10),
+      bucketWidth: parseFloat(values['bucket-width'])
+    }
+  };
+
+  const sizes = values.sizes.split(',').map((size) => parseInt(size, 10));
+  if (sizes.some((size) => !Number.isFinite(size) || size <= 0)) {
+    console.error(`❌ Invalid --sizes: ${values.sizes}`);
+    process.exit(1);
+  }
+
+  const report = { options, cases: [], timestamp: new Date().toISOString() };
+  for (const size of sizes) {
+    if (!values.json) {
+      console.log(`⏱️  Benchmarking ${size} memories...`);
+    }
+    report.cases.push(runCase(size, options));
+  }
+
+  if (values.json) {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    printReport(report);
+  }
+}
+
+// Run benchmark if called directly
+if (import.meta.url === `file://${process.argv[1]}`) {
+  main().catch(console.error);
+}
+
+export { generateVectors, runCase };
@@ -2,36 +2,27 @@
 
 /**
  * Restore script for KaskManager R&D Platform
- * Restores data from backup files
+ * Restores data from backup archives created by scripts/backup.js or
+ * `rd-platform backup create`
  */
 
-import fs from 'fs';
 import path from 'path';
 import { fileURLToPath } from 'url';
-import { execSync } from 'child_process';
+import { BackupManager } from '../src/interfaces/core/backup-manager.js';
 
 const __filename = fileURLToPath(import.meta.url);
 const __dirname = path.dirname(__filename);
 
-const BACKUP_DIR = process.env.BACKUP_DIR || path.join(__dirname, '../backups');
-const DATA_DIR = process.env.RND_DATA_DIR || path.join(__dirname, '../data');
+const BACKUP_TARGET = process.env.BACKUP_TARGET || process.env.BACKUP_DIR || path.join(__dirname, '../backups');
+
+const backupManager = new BackupManager({ target: BACKUP_TARGET });
 
 /**
  * List available backups
  */
 async function listBackups() {
   try {
-    const files = await fs.promises.readdir(BACKUP_DIR);
-    const backupFiles = files
-      .filter(file => file.endsWith('.tar.gz'))
-      .map(file => ({
-        name: file,
-        path: path.join(BACKUP_DIR, file),
-        mtime: fs.statSync(path.join(BACKUP_DIR, file)).mtime
-      }))
-      .sort((a, b) => b.mtime - a.mtime);
-
-    return backupFiles;
+    return await backupManager.listBackups();
   } catch (error) {
     console.error('❌ Failed to list backups:', error);
     return [];
@@ -39,21 +30,25 @@ async function listBackups() {
 }
 
 /**
- * Restore from backup file
+ * Restore from backup archive; with dryRun only the plan is printed
  */
-async function restoreFromBackup(backupFile, targetDir) {
-  console.log(`🔄 Restoring from backup: ${backupFile}`);
+async function restoreFromBackup(backupName, { dryRun = false } = {}) {
+  console.log(`🔄 Restoring from backup: ${backupName}${dryRun ? ' (dry run)' : ''}`);
   
   try {
-    // Create target directory if it doesn't exist
-    await fs.promises.mkdir(targetDir, { recursive: true });
+    const result = await backupManager.restoreBackup(backupName, { dryRun });
     
-    // Extract backup
-    execSync(`tar -xzf "${backupFile}" -C "${path.dirname(targetDir)}"`, {
-      stdio: 'inherit'
+    result.plan.forEach(item => {
+      console.log(`  ${item.action.padEnd(10)} ${item.path}`);
     });
     
-    console.log(`✅ Restore completed: ${targetDir}`);
+    if (dryRun) {
+      console.log(`📋 ${result.changes} of ${result.plan.length} files would change`);
+    } else {
+      console.log(`✅ Restore completed: ${result.changes} files changed`);
+      console.log(`🛟 Safety backup: ${result.safetyBackup}`);
+      console.log('⚠️  Restart the server to load the restored data');
+    }
     return true;
   } catch (error) {
     console.error(`❌ Failed to restore: ${error.message}`);
@@ -68,27 +63,30 @@ async function interactiveRestore() {
   const backups = await listBackups();
   
   if (backups.length === 0) {
-    console.log('⚠️  No backups found in:', BACKUP_DIR);
+    console.log('⚠️  No backups found in:', backupManager.store.describe());
     return;
   }
   
-  console.log('📋 Available backups:');
-  backups.forEach((backup, index) => {
-    console.log(`${index + 1}. ${backup.name} (${backup.mtime.toLocaleString()})`);
-  });
-  
   const { default: inquirer } = await import('inquirer');
   
-  const answers = await inquirer.prompt([
+  const { backupName } = await inquirer.prompt([
     {
       type: 'list',
-      name: 'backupIndex',
+      name: 'backupName',
       message: 'Select backup to restore:',
-      choices: backups.map((backup, index) => ({
-        name: `${backup.name} (${backup.mtime.toLocaleString()})`,
-        value: index
+      choices: backups.map(backup => ({
+        name: `${backup.name} (${new Date(backup.modifiedAt).toLocaleString()})`,
+        value: backup.name
       }))
-    },
+    }
+  ]);
+  
+  // Show what would change before asking for confirmation
+  if (!(await restoreFromBackup(backupName, { dryRun: true }))) {
+    return false;
+  }
+  
+  const { confirm } = await inquirer.prompt([
     {
       type: 'confirm',
       name: 'confirm',
@@ -97,28 +95,12 @@ async function interactiveRestore() {
     }
   ]);
   
-  if (!answers.confirm) {
+  if (!confirm) {
     console.log('🚫 Restore cancelled');
     return;
   }
   
-  const selectedBackup = backups[answers.backupIndex];
-  return await restoreFromBackup(selectedBackup.path, DATA_DIR);
-}
-
-/**
- * Restore specific backup file
- */
-async function restoreSpecific(backupName) {
-  const backupPath = path.join(BACKUP_DIR, backupName);
-  
-  if (!fs.existsSync(backupPath)) {
-    console.error(`❌ Backup file not found: ${backupPath}`);
-    return false;
-  }
-  
-  console.log(`🔄 Restoring specific backup: ${backupName}`);
-  return await restoreFromBackup(backupPath, DATA_DIR);
+  return await restoreFromBackup(backupName);
 }
 
 /**
@@ -142,21 +124,23 @@ async function main() {
         } else {
           console.log('📋 Available backups:');
           backups.forEach(backup => {
-            console.log(`  ${backup.name} (${backup.mtime.toLocaleString()})`);
+            console.log(`  ${backup.name} (${new Date(backup.modifiedAt).toLocaleString()})`);
           });
         }
         break;
         
       case 'restore':
         if (args.length < 2) {
-          console.error('❌ Usage: npm run restore restore <backup-filename>');
+          console.error('❌ Usage: npm run restore restore <backup-name> [--dry-run]');
+          process.exit(1);
+        }
+        if (!(await restoreFromBackup(args[1], { dryRun: args.includes('--dry-run') }))) {
           process.exit(1);
         }
-        await restoreSpecific(args[1]);
         break;
         
       default:
-        console.error('❌ Unknown command. Use: list, restore <filename>, or run without arguments for interactive mode');
+        console.error('❌ Unknown command. Use: list, restore <name> [--dry-run], or run without arguments for interactive mode');
         process.exit(1);
     }
   }
@@ -167,4 +151,4 @@ if (import.meta.url === `file://${process.argv[1]}`) {
   main().catch(console.error);
 }
 
-export { main as restore, listBackups, restoreFromBackup };
\ No newline at end of file
+export { main as restore, listBackups, restoreFromBackup };
@@ -0,0 +1,233 @@
+#!/usr/bin/env node
+
+/**
+ * Seed script for KaskManager R&D Platform
+ * Populates an instance with a reproducible demo dataset, and removes it again
+ */
+
+import { parseArgs } from 'util';
+import { AuthManager } from '../src/interfaces/core/auth-manager.js';
+import { ProjectManager } from '../src/interfaces/core/project-manager.js';
+import { IncidentManager } from '../src/interfaces/core/incident-manager.js';
+import { AnnouncementManager } from '../src/interfaces/core/announcement-manager.js';
+
+// Record counts per profile, multiplied by --scale
+const PROFILES = {
+  minimal: { users: 2, projects: 3, incidents: 1, announcements: 1 },
+  demo: { users: 6, projects: 12, incidents: 4, announcements: 2 },
+  large: { users: 40, projects: 150, incidents: 25, announcements: 5 }
+};
+
+const SEED_TAG = 'seed';
+const SEED_PASSWORD = 'demo-password';
+
+const FIRST_NAMES = ['ada', 'grace', 'linus', 'margaret', 'ken', 'barbara', 'dennis', 'frances'];
+const PROJECT_WORDS = ['atlas', 'beacon', 'cobalt', 'delta', 'ember', 'falcon', 'granite', 'harbor', 'ion', 'juniper'];
+const PROJECT_KINDS = ['api', 'worker', 'dashboard', 'pipeline', 'gateway', 'sdk'];
+const INCIDENT_TITLES = [
+  'Elevated error rate on API',
+  'Worker process crashed repeatedly',
+  'Disk usage above threshold',
+  'Slow responses from project dashboard'
+];
+
+/**
+ * Deterministic PRNG (mulberry32) so the same seed yields the same dataset
+ */
+function createRandom(seed) {
+  let state = seed >>> 0;
+  const next = () => {
+    state = (state + 0x6d2b79f5) >>> 0;
+    let t = state;
+    t = Math.imul(t ^ (t >>> 15), t | 1);
+    t ^= t + Math.imul(t ^ (t >>> 7), t | 61);
+    return ((t ^ (t >>> 14)) >>> 0) / 4294967296;
+  };
+
+  return {
+    next,
+    pick: items => items[Math.floor(next() * items.length)],
+    int: (min, max) => min + Math.floor(next() * (max - min + 1))
+  };
+}
+
+async function createManagers() {
+  const managers = {
+    auth: new AuthManager(),
+    projects: new ProjectManager(),
+    incidents: new IncidentManager(),
+    announcements: new AnnouncementManager()
+  };
+
+  await managers.auth.initialize();
+  await managers.projects.initialize();
+  await managers.incidents.loadIncidents();
+  await managers.announcements.initialize();
+
+  return managers;
+}
+
+/**
+ * Generate the dataset for a profile
+ */
+async function seed(managers, { profile, seed: seedValue, scale }) {
+  const counts = PROFILES[profile];
+  const random = createRandom(seedValue);
+  const count = key => Math.max(1, Math.round(counts[key] * scale));
+
+  const users = [];
+  for (let i = 0; i < count('users'); i++) {
+    const username = `demo-${random.pick(FIRST_NAMES)}-${i + 1}`;
+    users.push(
+      await managers.auth.createUser({
+        username,
+        email: `${username}@example.com`,
+        password: SEED_PASSWORD,
+        role: i === 0 ? 'admin' : 'user',
+        permissions: i === 0 ? ['read', 'write', 'admin'] : ['read', 'write'],
+        profile: { seed: profile }
+      })
+    );
+  }
+  console.log(`✅ Created ${users.length} users`);
+
+  const projects = [];
+  for (let i = 0; i < count('projects'); i++) {
+    const name = `demo-${random.pick(PROJECT_WORDS)}-${random.pick(PROJECT_KINDS)}-${i + 1}`;
+    const project = await managers.projects.createProject({
+      name,
+      description: `Demo ${name.split('-')[2]} seeded for evaluation`,
+      tags: [SEED_TAG, `${SEED_TAG}:${profile}`],
+      metadata: {
+        owner: random.pick(users).username,
+        cost_center: `CC-${random.int(100, 999)}`,
+        environment: random.pick(['development', 'staging', 'production']),
+        compliance_tier: random.pick(['none', 'internal', 'confidential'])
+      }
+    });
+    projects.push(project);
+  }
+  console.log(`✅ Created ${projects.length} projects`);
+
+  for (let i = 0; i < count('incidents'); i++) {
+    const incident = await managers.incidents.openIncident({
+      title: random.pick(INCIDENT_TITLES),
+      severity: random.pick(['critical', 'major', 'minor']),
+      source: SEED_TAG,
+      projectId: random.pick(projects).id
+    });
+
+    // Leave a mix of open, acknowledged, and resolved incidents
+    const outcome = random.next();
+    if (outcome > 0.66) {
+      await managers.incidents.resolve(incident.id, users[0].id, 'Seeded resolution');
+    } else if (outcome > 0.33) {
+      await managers.incidents.acknowledge(incident.id, users[0].id);
+    }
+  }
+  console.log(`✅ Created ${count('incidents')} incidents`);
+
+  for (let i = 0; i < count('announcements'); i++) {
+    await managers.announcements.createAnnouncement(
+      {
+        title: `Demo announcement ${i + 1}`,
+        message: 'This instance contains generated demo data.',
+        severity: i === 0 ? 'info' : random.pick(['info', 'warning']),
+        audience: { type: 'all' }
+      },
+      SEED_TAG
+    );
+  }
+  console.log(`✅ Created ${count('announcements')} announcements`);
+
+  console.log(`\nDemo users share the password "${SEED_PASSWORD}"`);
+}
+
+/**
+ * Remove everything previously created by this script
+ */
+async function teardown(managers) {
+  let removed = 0;
+
+  for (const project of Array.from(managers.projects.projects.values())) {
+    if (project.tags?.includes(SEED_TAG)) {
+      await managers.projects.deleteProject(project.id, true);
+      removed++;
+    }
+  }
+
+  for (const incident of Array.from(managers.incidents.incidents.values())) {
+    if (incident.source === SEED_TAG) {
+      managers.incidents.incidents.delete(incident.id);
+      removed++;
+    }
+  }
+  await managers.incidents.saveIncidents();
+
+  for (const announcement of await managers.announcements.listAnnouncements()) {
+    if (announcement.createdBy === SEED_TAG) {
+      await managers.announcements.deleteAnnouncement(announcement.id);
+      removed++;
+    }
+  }
+
+  // Regular users first so the last seeded admin is never the only admin
+  const seededUsers = Array.from(managers.auth.users.values())
+    .filter(user => user.profile?.seed)
+    .sort((a, b) => (a.role === 'admin') - (b.role === 'admin'));
+  for (const user of seededUsers) {
+    await managers.auth.deleteUser(user.id);
+    removed++;
+  }
+
+  console.log(`🗑️  Removed ${removed} seeded records`);
+}
+
+/**
+ * Main seed function
+ */
+async function main() {
+  const { values } = parseArgs({
+    options: {
+      profile: { type: 'string', default: 'demo' },
+      seed: { type: 'string', default: '42' },
+      scale: { type: 'string', default: '1' },
+      teardown: { type: 'boolean', default: false }
+    }
+  });
+
+  if (!PROFILES[values.profile]) {
+    console.error(`❌ Unknown profile: ${values.profile} (use ${Object.keys(PROFILES).join(', ')})`);
+    process.exit(1);
+  }
+
+  const managers = await createManagers();
+
+  try {
+    if (values.teardown) {
+      await teardown(managers);
+    } else {
+      console.log(`🌱 Seeding ${values.profile} dataset (seed ${values.seed})...`);
+      await seed(managers, {
+        profile: values.profile,
+        seed: parseInt(values.seed, 10),
+        scale: parseFloat(values.scale)
+      });
+    }
+  } catch (error) {
+    console.error('❌ Seeding failed:', error.message);
+    process.exit(1);
+  } finally {
+    await managers.auth.stop();
+  }
+
+  // AuthManager's session cleanup timer would otherwise keep us alive
+  process.exit(0);
+}
+
+// Run seed if called directly
+if (import.meta.url === `file://${process.argv[1]}`) {
+  main().catch(console.error);
+}
+
+export { PROFILES, createRandom, seed, teardown };
@@ -0,0 +1,179 @@
+/**
+ * OpenAPI
+ * Builds an OpenAPI 3.0 document from the endpoint map served at /api/docs,
+ * and the Swagger UI page that renders it
+ */
+
+const HTTP_METHODS = ['get', 'post', 'put', 'patch', 'delete'];
+
+// "DELETE /api/auth/tokens/:id" -> deleteAuthTokensById
+function operationId(method, path) {
+  const words = path
+    .replace(/^\/api\//, '/')
+    .split('/')
+    .filter(Boolean)
+    .map((segment) =>
+      segment.startsWith(':') ? `by-${segment.slice(1)}` : segment
+    )
+    .join('-')
+    .split(/[^a-zA-Z0-9]+/)
+    .filter(Boolean);
+
+  return (
+    method +
+    words.map((word) => word[0].toUpperCase() + word.slice(1)).join('')
+  );
+}
+
+// Field names from a "{ name, scopes }" hint in the description
+function bodyFields(summary) {
+  const match = /\{\s*([\w\s,?:]+?)\s*\}/.exec(summary);
+  if (!match) return null;
+  return match[1]
+    .split(',')
+    .map((field) => field.split(':')[0].replace('?', '').trim())
+    .filter(Boolean);
+}
+
+function buildOperation(method, path, query, summary, tag, isPublic) {
+  const parameters = [
+    ...Array.from(path.matchAll(/:(\w+)/g), ([, name]) => ({
+      name,
+      in: 'path',
+      required: true,
+      schema: { type: 'string' },
+    })),
+    ...query.map((name) => ({
+      name,
+      in: 'query',
+      required: true,
+      schema: { type: 'string' },
+    })),
+  ];
+
+  const operation = {
+    tags: [tag],
+    summary,
+    operationId: operationId(method, path),
+    ...(parameters.length > 0 && { parameters }),
+    responses: {
+      200: { description: 'Success' },
+      400: { $ref: '#/components/responses/Error' },
+    },
+  };
+
+  const fields = bodyFields(summary);
+  if (fields && method !== 'get') {
+    operation.requestBody = {
+      content: {
+        'application/json': {
+          schema: {
+            type: 'object',
+            properties: Object.fromEntries(fields.map((field) => [field, {}])),
+          },
+        },
+      },
+    };
+  }
+
+  if (isPublic) {
+    operation.security = [];
+  } else {
+    operation.responses[401] = { $ref: '#/components/responses/Error' };
+  }
+
+  return operation;
+}
+
+/**
+ * `endpoints` is the /api/docs map: { group: { "METHOD /path?query=": text } }.
+ * Each group becomes a tag. Endpoints listed in `publicEndpoints` skip the
+ * bearer token requirement.
+ */
+function buildOpenAPISpec({
+  title,
+  version,
+  description,
+  endpoints,
+  publicEndpoints = [],
+  serverUrl = '/',
+}) {
+  const paths = {};
+
+  for (const [tag, routes] of Object.entries(endpoints)) {
+    for (const [route, summary] of Object.entries(routes)) {
+      const [verb, target] = route.split(' ');
+      const method = verb.toLowerCase();
+      if (!HTTP_METHODS.includes(method) || !target) continue;
+
+      const [path, search = ''] = target.split('?');
+      const query = Array.from(new URLSearchParams(search).keys());
+      const openapiPath = path.replace(/:(\w+)/g, '{$1}');
+
+      paths[openapiPath] = paths[openapiPath] || {};
+      paths[openapiPath][method] = buildOperation(
+        method,
+        path,
+        query,
+        summary,
+        tag,
+        publicEndpoints.includes(`${verb} ${path}`)
+      );
+    }
+  }
+
+  return {
+    openapi: '3.0.3',
+    info: { title, version, description },
+    servers: [{ url: serverUrl }],
+    tags: Object.keys(endpoints).map((name) => ({ name })),
+    paths,
+    components: {
+      securitySchemes: {
+        bearerAuth: { type: 'http', scheme: 'bearer', bearerFormat: 'JWT' },
+      },
+      responses: {
+        Error: {
+          description: 'Error',
+          content: {
+            'application/json': {
+              schema: {
+                type: 'object',
+                properties: { error: { type: 'string' } },
+              },
+            },
+          },
+        },
+      },
+    },
+    security: [{ bearerAuth: [] }],
+  };
+}
+
+// Scripts are served by the API rather than inlined, so the page works under
+// the script-src 'self' policy
+function renderSwaggerUI({ title, specUrl, assetsPath, initScriptUrl }) {
+  return `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>${title}</title>
+<link rel="stylesheet" href="${assetsPath}/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui" data-spec-url="${specUrl}"></div>
+<script src="${assetsPath}/swagger-ui-bundle.js"></script>
+<script src="${initScriptUrl}"></script>
+</body>
+</html>`;
+}
+
+const SWAGGER_INIT_SCRIPT = `const root = document.getElementById('swagger-ui');
+window.ui = SwaggerUIBundle({
+  url: root.dataset.specUrl,
+  domNode: root,
+  persistAuthorization: true,
+});
+`;
+
+export { buildOpenAPISpec, renderSwaggerUI, SWAGGER_INIT_SCRIPT, operationId };
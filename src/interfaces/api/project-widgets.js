@@ -0,0 +1,87 @@
+/**
+ * Project Widgets
+ * Rendering for embeddable project status: an iframe-friendly HTML card
+ * and the matching oEmbed response
+ */
+
+const WIDGET_SIZE = { width: 320, height: 140 };
+
+const HEALTH_COLORS = {
+  ok: '#2e7d32',
+  degraded: '#f9a825',
+  critical: '#c62828',
+};
+
+// Matches the embed URL so oEmbed consumers can resolve it back to a project
+const EMBED_PATH = /^\/api\/widgets\/projects\/([^/]+)\/embed$/;
+
+function escapeHTML(value) {
+  return String(value ?? '').replace(
+    /[&<>"']/g,
+    (char) =>
+      ({
+        '&': '&amp;',
+        '<': '&lt;',
+        '>': '&gt;',
+        '"': '&quot;',
+        "'": '&#39;',
+      })[char]
+  );
+}
+
+function renderWidgetHTML(widget) {
+  const color = HEALTH_COLORS[widget.health] || '#616161';
+  const progress =
+    widget.progress === null ? 'n/a' : `${Math.round(widget.progress)}%`;
+
+  return `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>${escapeHTML(widget.project.name)} status</title>
+<style>
+  body { margin: 0; font: 14px/1.4 system-ui, sans-serif; color: #212121; }
+  .card { border: 1px solid #e0e0e0; border-radius: 6px; padding: 12px; }
+  .name { font-weight: 600; font-size: 16px; }
+  .health { color: ${color}; font-weight: 600; text-transform: uppercase; }
+  .bar { height: 6px; background: #eeeeee; border-radius: 3px; margin: 8px 0; }
+  .fill { height: 6px; background: ${color}; border-radius: 3px; }
+  .meta { color: #757575; font-size: 12px; }
+</style>
+</head>
+<body>
+<div class="card">
+  <div class="name">${escapeHTML(widget.project.name)}</div>
+  <div><span class="health">${escapeHTML(widget.health)}</span> &middot; ${escapeHTML(widget.project.status)}</div>
+  <div class="bar"><div class="fill" style="width: ${widget.progress ?? 0}%"></div></div>
+  <div class="meta">Progress ${progress} &middot; ${widget.openIncidents} open incidents</div>
+  <div class="meta">Updated ${escapeHTML(widget.updatedAt)}</div>
+</div>
+</body>
+</html>`;
+}
+
+// oEmbed "rich" response wrapping the embed page in an iframe
+function buildOEmbed(widget, embedURL, { maxwidth, maxheight, cacheAge }) {
+  const width = Math.min(WIDGET_SIZE.width, Number(maxwidth) || Infinity);
+  const height = Math.min(WIDGET_SIZE.height, Number(maxheight) || Infinity);
+
+  return {
+    version: '1.0',
+    type: 'rich',
+    provider_name: 'R&D Platform',
+    title: `${widget.project.name} status`,
+    width,
+    height,
+    cache_age: cacheAge,
+    html: `<iframe src="${escapeHTML(embedURL)}" width="${width}" height="${height}" frameborder="0" title="${escapeHTML(widget.project.name)} status"></iframe>`,
+  };
+}
+
+export {
+  WIDGET_SIZE,
+  EMBED_PATH,
+  escapeHTML,
+  renderWidgetHTML,
+  buildOEmbed,
+};
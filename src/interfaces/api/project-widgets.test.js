@@ -0,0 +1,111 @@
+/**
+ * Tests for Project Widgets
+ */
+
+import {
+  WIDGET_SIZE,
+  EMBED_PATH,
+  escapeHTML,
+  renderWidgetHTML,
+  buildOEmbed,
+} from './project-widgets.js';
+
+describe('project widgets', () => {
+  const widget = (overrides = {}) => ({
+    project: { id: 'p1', name: 'Apollo', status: 'running' },
+    health: 'ok',
+    progress: 42.4,
+    openIncidents: 1,
+    updatedAt: '2026-10-17T00:00:00.000Z',
+    ...overrides,
+  });
+
+  describe('escapeHTML', () => {
+    it('should escape markup and quotes', () => {
+      expect(escapeHTML(`<a href="x">'&'</a>`)).toBe(
+        '&lt;a href=&quot;x&quot;&gt;&#39;&amp;&#39;&lt;/a&gt;'
+      );
+      expect(escapeHTML(null)).toBe('');
+      expect(escapeHTML(3)).toBe('3');
+    });
+  });
+
+  describe('EMBED_PATH', () => {
+    it('should resolve embed URLs back to a project id', () => {
+      expect(EMBED_PATH.exec('/api/widgets/projects/p1/embed')[1]).toBe('p1');
+      expect(EMBED_PATH.test('/api/widgets/projects/p1/embed/x')).toBe(false);
+      expect(EMBED_PATH.test('/api/projects/p1')).toBe(false);
+    });
+  });
+
+  describe('renderWidgetHTML', () => {
+    it('should render health, progress and incidents', () => {
+      const html = renderWidgetHTML(widget());
+
+      expect(html).toContain('<div class="name">Apollo</div>');
+      expect(html).toContain('Progress 42% &middot; 1 open incidents');
+      expect(html).toContain('color: #2e7d32');
+    });
+
+    it('should escape project fields', () => {
+      const html = renderWidgetHTML(
+        widget({
+          project: { name: '<img src=x onerror=alert(1)>', status: '"x"' },
+        })
+      );
+
+      expect(html).not.toContain('<img');
+      expect(html).toContain('&lt;img src=x onerror=alert(1)&gt;');
+      expect(html).toContain('&quot;x&quot;');
+    });
+
+    it('should handle unknown health and missing progress', () => {
+      const html = renderWidgetHTML(
+        widget({ health: 'mystery', progress: null })
+      );
+
+      expect(html).toContain('color: #616161');
+      expect(html).toContain('Progress n/a');
+      expect(html).toContain('width: 0%');
+    });
+  });
+
+  describe('buildOEmbed', () => {
+    const embedURL = 'https://rnd.example.com/api/widgets/projects/p1/embed';
+
+    it('should wrap the embed page in an iframe', () => {
+      const oembed = buildOEmbed(widget(), embedURL, { cacheAge: 60 });
+
+      expect(oembed).toMatchObject({
+        version: '1.0',
+        type: 'rich',
+        title: 'Apollo status',
+        cache_age: 60,
+        ...WIDGET_SIZE,
+      });
+      expect(oembed.html).toContain(`src="${embedURL}"`);
+    });
+
+    it('should respect maxwidth and maxheight', () => {
+      const oembed = buildOEmbed(widget(), embedURL, {
+        maxwidth: '200',
+        maxheight: '999',
+      });
+
+      expect(oembed.width).toBe(200);
+      expect(oembed.height).toBe(WIDGET_SIZE.height);
+      expect(oembed.html).toContain('width="200"');
+    });
+
+    it('should escape the iframe attributes', () => {
+      const oembed = buildOEmbed(
+        widget({ project: { name: '" onload="alert(1)' } }),
+        `${embedURL}?a="b"`,
+        {}
+      );
+
+      expect(oembed.html).not.toContain('" onload="');
+      expect(oembed.html).toContain('?a=&quot;b&quot;');
+    });
+  });
+});
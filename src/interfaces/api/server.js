@@ -4,16 +4,58 @@
  */
 
 import express from 'express';
+import swaggerUiDist from 'swagger-ui-dist';
 import cors from 'cors';
 import helmet from 'helmet';
 import rateLimit from 'express-rate-limit';
 import compression from 'compression';
 import { createServer } from 'http';
+import crypto from 'crypto';
 import { Server as SocketServer } from 'socket.io';
 import { ProjectManager } from '../core/project-manager.js';
 import { StatusMonitor } from '../core/status-monitor.js';
-import { AuthManager } from '../core/auth-manager.js';
+import { AuthManager, hasPermission } from '../core/auth-manager.js';
+import { AnalyticsCollector } from '../core/analytics-collector.js';
+import { IncidentManager } from '../core/incident-manager.js';
+import { AnnouncementManager } from '../core/announcement-manager.js';
+import { OnboardingManager } from '../core/onboarding-manager.js';
+import { WatchManager } from '../core/watch-manager.js';
+import {
+  PromptTemplateManager,
+  PromptTemplateError,
+} from '../core/prompt-template-manager.js';
+import { ConfigManager } from '../core/config-manager.js';
 import { Logger } from '../core/logger.js';
+import { StartupManager } from '../core/startup-manager.js';
+import { SetupManager, SETUP_ERRORS } from '../core/setup-manager.js';
+import { VersionAdvisor } from '../core/version-advisor.js';
+import { CorsPolicyManager } from '../core/cors-policy.js';
+import { PrivacyGuard } from '../core/privacy-guard.js';
+import { BackupManager } from '../core/backup-manager.js';
+import { fanOut } from '../core/fan-out.js';
+import {
+  PROTOCOL_VERSION,
+  SUPPORTED_VERSIONS,
+  ENCODINGS,
+  EVENT_PERMISSIONS,
+  defaultSession,
+  negotiate,
+  accepts,
+  requiredPermission,
+  encodeMessage,
+  deserialize,
+  EncodingStats,
+} from './socket-protocol.js';
+import {
+  EMBED_PATH,
+  renderWidgetHTML,
+  buildOEmbed,
+} from './project-widgets.js';
+import {
+  buildOpenAPISpec,
+  renderSwaggerUI,
+  SWAGGER_INIT_SCRIPT,
+} from './openapi.js';
 import { errorHandler, notFoundHandler } from './middleware/error-handler.js';
 import { authMiddleware } from './middleware/auth-middleware.js';
 import { validateRequest } from './middleware/validation.js';
@@ -22,147 +64,1723 @@ import { systemRoutes } from './routes/system.js';
 import { authRoutes } from './routes/auth.js';
 import { webhookRoutes } from './routes/webhooks.js';
 
+// Optional modules and the route prefix each one owns
+const OPTIONAL_MODULES = {
+  analytics: '/api/analytics',
+  incidents: '/api/incidents',
+  announcements: '/api/announcements',
+  onboarding: '/api/onboarding',
+  prompts: '/api/prompts',
+  alerts: '/api/alerts',
+};
+
+const API_INFO = {
+  title: 'R&D Platform API',
+  version: '1.0.0',
+  description:
+    'REST API for R&D Platform project management and system control',
+};
+
+// Documented endpoints that work without a bearer token
+const PUBLIC_ENDPOINTS = [
+  'GET /health',
+  'GET /readyz',
+  'GET /api/version',
+  'GET /api/status/public',
+  'POST /api/auth/login',
+  'POST /api/auth/refresh',
+  'POST /api/auth/mfa/verify',
+  'POST /api/auth/mfa/setup',
+  'POST /api/auth/mfa/setup/confirm',
+  'GET /api/auth/sso',
+  'GET /api/auth/sso/login',
+  'GET /api/auth/sso/callback',
+  'GET /api/setup',
+  'POST /api/setup',
+  'GET /api/widgets/projects/:id',
+  'GET /api/widgets/projects/:id/embed',
+  'GET /api/oembed',
+  'POST /api/webhooks/github',
+  'POST /api/webhooks/deploy',
+];
+
 class APIServer {
   constructor(config = {}) {
     this.config = {
-      port: config.port || process.env.PORT || 8080,
-      host: config.host || process.env.HOST || '0.0.0.0',
+      // Unset port/host fall back to server.port/server.host at start()
+      port: config.port || process.env.PORT,
+      host: config.host || process.env.HOST,
       cors: config.cors || { origin: true },
       rateLimit: config.rateLimit || { windowMs: 15 * 60 * 1000, max: 100 },
+      widgetCacheAge: config.widgetCacheAge || 60, // seconds
       ...config,
     };
 
-    this.app = express();
-    this.httpServer = createServer(this.app);
-    this.io = new SocketServer(this.httpServer, {
-      cors: this.config.cors,
-    });
+    // DISABLED_MODULES=analytics,prompts; explicit config.modules wins
+    const disabled = (process.env.DISABLED_MODULES || '')
+      .split(',')
+      .map((name) => name.trim());
+    this.modules = Object.fromEntries(
+      Object.keys(OPTIONAL_MODULES).map((name) => [
+        name,
+        this.config.modules?.[name] ?? !disabled.includes(name),
+      ])
+    );
+
+    this.app = express();
+    this.httpServer = createServer(this.app);
+    this.io = new SocketServer(this.httpServer, {
+      cors: this.config.cors,
+    });
+
+    this.configManager = new ConfigManager(this.config.configuration);
+    this.projectManager = new ProjectManager({
+      configManager: this.configManager,
+    });
+    this.statusMonitor = new StatusMonitor({
+      ...this.config.monitoring,
+      alertRulesEnabled: this.modules.alerts,
+    });
+    this.authManager = new AuthManager({ configManager: this.configManager });
+    this.analyticsCollector = new AnalyticsCollector(this.config.analytics);
+    this.incidentManager = new IncidentManager(this.config.incidents);
+    this.announcementManager = new AnnouncementManager(
+      this.config.announcements
+    );
+    this.onboarding = new OnboardingManager(this.config.onboarding);
+    this.watches = new WatchManager(this.config.watches);
+    this.promptTemplates = new PromptTemplateManager(this.config.prompts);
+    this.logger = new Logger('APIServer');
+    this.startup = new StartupManager(this.config.startup);
+    this.setup = new SetupManager({
+      moduleNames: Object.keys(OPTIONAL_MODULES),
+      ...this.config.setup,
+    });
+    if (this.config.logging) {
+      Logger.configurePolicy(this.config.logging);
+    }
+    this.encodingStats = new EncodingStats();
+    this.versionAdvisor = new VersionAdvisor(this.config.versioning);
+    this.corsPolicy = new CorsPolicyManager(this.config.corsGrants);
+    this.privacyGuard = new PrivacyGuard(this.config.privacy);
+    this.backupManager = new BackupManager(this.config.backups);
+
+    this.statusMonitor.registerComponent(
+      'api',
+      () => this.httpServer.listening
+    );
+
+    this.setupMiddleware();
+    this.setupRoutes();
+    this.setupWebSocket();
+    this.setupErrorHandling();
+  }
+
+  setupMiddleware() {
+    // Security middleware
+    this.app.use(
+      helmet({
+        contentSecurityPolicy: {
+          directives: {
+            defaultSrc: ["'self'"],
+            styleSrc: ["'self'", "'unsafe-inline'"],
+            scriptSrc: ["'self'"],
+            imgSrc: ["'self'", 'data:', 'https:'],
+          },
+        },
+      })
+    );
+
+    // CORS; origins with a capability grant are restricted to it
+    this.app.use(this.corsPolicy.middleware(cors(this.config.cors)));
+
+    // Rate limiting
+    const limiter = rateLimit(this.config.rateLimit);
+    this.app.use(limiter);
+
+    // Compression
+    this.app.use(compression());
+
+    // Body parsing
+    this.app.use(express.json({ limit: '10mb' }));
+    this.app.use(express.urlencoded({ extended: true, limit: '10mb' }));
+
+    // Anonymized usage analytics (honors DNT / Sec-GPC)
+    const analytics = this.analyticsCollector.middleware();
+    this.app.use((req, res, next) =>
+      this.modules.analytics ? analytics(req, res, next) : next()
+    );
+
+    // Request ids, echoed back so a request can be targeted for capture
+    this.app.use((req, res, next) => {
+      req.id = req.get('X-Request-ID') || crypto.randomUUID();
+      res.set('X-Request-ID', req.id);
+      next();
+    });
+
+    // Deprecation headers, and per-caller usage for the upgrade advisor
+    this.app.use(this.versionAdvisor.middleware());
+
+    // Logging, sampled per route by the logging policy
+    this.app.use((req, res, next) => {
+      if (Logger.shouldSample(req.path, req.id)) {
+        this.logger.info(`${req.method} ${req.path}`, {
+          ip: req.ip,
+          userAgent: req.get('User-Agent'),
+          requestId: req.id,
+        });
+      }
+      next();
+    });
+
+    // Request validation
+    this.app.use(validateRequest);
+  }
+
+  setupRoutes() {
+    // Disabled modules answer 404 with a code that says why; checked per
+    // request because first-run setup settings apply at start()
+    for (const [name, prefix] of Object.entries(OPTIONAL_MODULES)) {
+      this.app.use(prefix, (req, res, next) => {
+        if (this.modules[name]) return next();
+        res.status(404).json({
+          error: `The ${name} module is disabled`,
+          code: 'MODULE_DISABLED',
+        });
+      });
+    }
+
+    // Health check endpoint
+    this.app.get('/health', (req, res) => {
+      res.json({
+        status: 'healthy',
+        timestamp: new Date().toISOString(),
+        version: process.env.npm_package_version || '1.0.0',
+        uptime: process.uptime(),
+      });
+    });
+
+    // Readiness; public, so it only says yes or no. Service and probe
+    // details are on the authenticated /api/system/health.
+    this.app.get('/readyz', (req, res) => {
+      const ready = this.startup.isReady() && this.statusMonitor.isReady();
+      res.status(ready ? 200 : 503).json({ ready });
+    });
+
+    // Version negotiation: minimum client versions and deprecated
+    // endpoints this caller has used recently
+    this.app.get('/api/version', (req, res) => {
+      res.json(
+        this.versionAdvisor.advise({
+          client: req.get('X-Client-Name'),
+          version: req.get('X-Client-Version'),
+          callerKey: VersionAdvisor.callerKey(req),
+        })
+      );
+    });
+
+    // Status page (public variant omits history and incident details)
+    this.app.get('/api/status/public', async (req, res, next) => {
+      try {
+        const page = await this.getStatusPage();
+
+        res.set('Cache-Control', 'public, max-age=30');
+        res.json({
+          timestamp: page.timestamp,
+          status: page.status,
+          components: Object.fromEntries(
+            Object.entries(page.components).map(([name, component]) => [
+              name,
+              { status: component.status, uptime: component.uptime },
+            ])
+          ),
+          incidents: page.incidents.map((incident) =>
+            this.incidentManager.toPublic(incident)
+          ),
+        });
+      } catch (error) {
+        next(error);
+      }
+    });
+
+    this.app.get('/api/status', authMiddleware, async (req, res, next) => {
+      try {
+        res.json(await this.getStatusPage());
+      } catch (error) {
+        next(error);
+      }
+    });
+
+    // API documentation; the same endpoint map feeds the OpenAPI spec
+    const documentedEndpoints = () => {
+      const endpoints = {
+        auth: {
+          'POST /api/auth/login': 'Authenticate user',
+          'POST /api/auth/logout': 'Logout user',
+          'GET /api/auth/me': 'Get current user info',
+          'POST /api/auth/refresh': 'Refresh access token',
+          'GET /api/auth/sessions/activity':
+            'Your active sessions and login timeline with device and location',
+          'DELETE /api/auth/sessions/:id': 'Revoke one of your sessions',
+          'GET /api/auth/tokens': 'Your personal access tokens and their usage',
+          'POST /api/auth/tokens':
+            'Create a personal access token { name, scopes, expiresInDays }',
+          'DELETE /api/auth/tokens/:id': 'Revoke a personal access token',
+          'POST /api/auth/mfa/verify':
+            'Finish a login that returned mfaRequired { mfaToken, code }',
+          'POST /api/auth/mfa/setup':
+            'Start required MFA enrollment from a login challenge { mfaToken }',
+          'POST /api/auth/mfa/setup/confirm':
+            'Confirm required enrollment and sign in { mfaToken, code }',
+          'GET /api/auth/mfa': 'Your MFA status',
+          'POST /api/auth/mfa/enroll':
+            'Start MFA enrollment; returns the otpauth:// URI for a QR code',
+          'POST /api/auth/mfa/confirm':
+            'Enable MFA with a code from the app; returns backup codes',
+          'POST /api/auth/mfa/backup-codes': 'Replace backup codes { code }',
+          'DELETE /api/auth/mfa': 'Disable MFA { code }',
+          'DELETE /api/auth/mfa/users/:id': "Reset a user's MFA (admin)",
+          'GET /api/auth/sso': 'Whether single sign-on is configured',
+          'GET /api/auth/sso/login':
+            'Sign in at the identity provider; optional ?returnTo=/path',
+          'GET /api/auth/sso/callback?code=&state=':
+            'Finish single sign-on; returns a session or MFA challenge like login',
+        },
+        widgets: {
+          'POST /api/widgets/projects/:id/token':
+            'Issue a widget token (project owner or admin)',
+          'DELETE /api/widgets/projects/:id/token':
+            'Revoke the widget token (project owner or admin)',
+          'GET /api/widgets/projects/:id?token=': 'Project headline metrics',
+          'GET /api/widgets/projects/:id/embed?token=': 'Embeddable HTML card',
+          'GET /api/oembed?url=': 'oEmbed response for a widget embed URL',
+        },
+        cors: {
+          'GET /api/cors/grants': 'List per-origin CORS grants (admin)',
+          'POST /api/cors/grants':
+            'Grant an origin methods and route prefixes (admin)',
+          'PUT /api/cors/grants/:id': 'Update a CORS grant (admin)',
+          'DELETE /api/cors/grants/:id': 'Revoke a CORS grant (admin)',
+        },
+        privacy: {
+          'GET /api/privacy': 'Org and project privacy modes (admin)',
+          'PUT /api/privacy': 'Set the org privacy mode (admin)',
+          'GET /api/privacy/audit':
+            'Data classes consumed or blocked per subsystem (admin)',
+          'GET /api/projects/:id/privacy': 'Effective project privacy mode',
+          'PUT /api/projects/:id/privacy':
+            'Override the project privacy mode; null clears it (admin)',
+        },
+        backups: {
+          'GET /api/backups': 'List backup archives (admin)',
+          'POST /api/backups': 'Create a backup archive (admin)',
+          'POST /api/backups/:name/restore':
+            'Restore a backup; { dryRun: true } previews the changes (admin)',
+        },
+        docs: {
+          'GET /api/docs': 'Swagger UI in a browser, otherwise this summary',
+          'GET /api/openapi.json': 'OpenAPI 3.0 description of this API',
+        },
+        version: {
+          'GET /api/version':
+            'Server version, client minimums, deprecated endpoints used',
+        },
+        setup: {
+          'GET /api/setup': 'First-run setup status',
+          'POST /api/setup':
+            'Create the initial admin and org settings (X-Setup-Token)',
+          'GET /api/setup/settings': 'Setup settings, secrets masked (admin)',
+        },
+        projects: {
+          'GET /api/projects': 'List projects',
+          'POST /api/projects': 'Create project',
+          'GET /api/projects/:id': 'Get project details',
+          'PUT /api/projects/:id': 'Update project',
+          'DELETE /api/projects/:id': 'Delete project',
+          'POST /api/projects/:id/start': 'Start project',
+          'POST /api/projects/:id/stop': 'Stop project',
+          'GET /api/projects/:id/status': 'Get project status',
+          'GET /api/projects/:id/logs': 'Get project logs',
+          'POST /api/projects/:id/clone':
+            'Clone a project in the background; returns the job (202)',
+          'GET /api/projects/clone-jobs/:jobId': 'Clone job progress',
+        },
+        health: {
+          'GET /health': 'Liveness check',
+          'GET /readyz': 'Readiness { ready }; 503 when not ready',
+        },
+        status: {
+          'GET /api/status': 'Component status, uptime history, incidents',
+          'GET /api/status/public': 'Unauthenticated status summary',
+        },
+        system: {
+          'GET /api/system/status': 'Get system status',
+          'GET /api/system/health': 'Health check',
+          'GET /api/system/metrics': 'Get system metrics',
+          'POST /api/system/maintenance': 'Trigger maintenance',
+        },
+        socket: {
+          'GET /api/socket/status': 'Connected clients, rooms and encodings',
+          'GET /api/socket/connections':
+            'Connected sockets with per-event denied counters (admin)',
+        },
+        analytics: {
+          'GET /api/analytics': 'Aggregated usage analytics (admin only)',
+        },
+        incidents: {
+          'GET /api/incidents': 'List incidents',
+          'GET /api/incidents/:id': 'Get incident details',
+          'POST /api/incidents/:id/acknowledge': 'Acknowledge incident',
+          'POST /api/incidents/:id/resolve': 'Resolve incident',
+          'PUT /api/incidents/:id/public-title':
+            'Set the status page title { publicTitle } (admin)',
+        },
+        announcements: {
+          'GET /api/announcements': 'Active announcements for current user',
+          'GET /api/announcements/all': 'List all announcements (admin)',
+          'POST /api/announcements': 'Create announcement (admin)',
+          'PUT /api/announcements/:id': 'Update announcement (admin)',
+          'DELETE /api/announcements/:id': 'Delete announcement (admin)',
+          'POST /api/announcements/:id/dismiss': 'Dismiss announcement',
+        },
+        onboarding: {
+          'GET /api/onboarding': 'Checklist progress and dashboard views',
+          'POST /api/onboarding/items/:id/complete': 'Mark checklist item done',
+          'POST /api/onboarding/items/:id/dismiss': 'Dismiss checklist item',
+          'DELETE /api/onboarding/items/:id/dismiss':
+            'Bring back a dismissed checklist item',
+          'GET /api/onboarding/views': 'Saved views, or the role defaults',
+          'PUT /api/onboarding/views': 'Save your own views',
+          'DELETE /api/onboarding/views': 'Go back to the role default views',
+          'GET /api/onboarding/default-views': 'Default views by role (admin)',
+          'PUT /api/onboarding/default-views/:role':
+            'Set default views for a role (admin)',
+        },
+        watching: {
+          'POST /api/projects/:id/watch': 'Watch a project',
+          'DELETE /api/projects/:id/watch': 'Stop watching a project',
+          'GET /api/projects/:id/watchers': 'Watcher count for a project',
+          'GET /api/watching': 'Items you are watching',
+          'GET /api/watching/feed': 'Activity on watched items',
+        },
+        alerts: {
+          'GET /api/alerts': 'Pending and firing rule alerts',
+          'GET /api/alerts/rules': 'List alert rules',
+          'POST /api/alerts/rules': 'Create alert rule (admin)',
+          'PUT /api/alerts/rules/:id': 'Update alert rule (admin)',
+          'DELETE /api/alerts/rules/:id': 'Delete alert rule (admin)',
+          'GET /api/alerts/silences': 'List silences',
+          'POST /api/alerts/silences': 'Create silence (admin)',
+          'DELETE /api/alerts/silences/:id': 'Delete silence (admin)',
+        },
+        logging: {
+          'GET /api/logging/policy': 'Redaction and sampling policy (admin)',
+          'PUT /api/logging/policy': 'Update redaction and sampling (admin)',
+          'POST /api/logging/capture':
+            'Log everything for a request id temporarily (admin)',
+        },
+        prompts: {
+          'GET /api/prompts': 'List prompt templates (admin)',
+          'GET /api/prompts/:name': 'Get prompt template versions (admin)',
+          'POST /api/prompts/:name/versions': 'Add template version (admin)',
+          'PUT /api/prompts/:name':
+            'Set active version, environment overrides, variants (admin)',
+          'POST /api/prompts/:name/render': 'Preview rendered prompt (admin)',
+        },
+        webhooks: {
+          'POST /api/webhooks/github': 'GitHub webhook',
+          'POST /api/webhooks/deploy': 'Deployment webhook',
+          'GET /api/webhooks': 'List webhooks',
+        },
+      };
+
+      for (const [name, enabled] of Object.entries(this.modules)) {
+        if (!enabled) delete endpoints[name];
+      }
+      return endpoints;
+    };
+
+    this.app.get('/api/openapi.json', (req, res) => {
+      res.json(
+        buildOpenAPISpec({
+          ...API_INFO,
+          endpoints: documentedEndpoints(),
+          publicEndpoints: PUBLIC_ENDPOINTS,
+        })
+      );
+    });
+
+    this.app.use(
+      '/api/docs/assets',
+      express.static(swaggerUiDist.getAbsoluteFSPath())
+    );
+    this.app.get('/api/docs/init.js', (req, res) => {
+      res.type('js').send(SWAGGER_INIT_SCRIPT);
+    });
+
+    // Browsers get Swagger UI; API clients keep getting the JSON summary
+    this.app.get('/api/docs', (req, res) => {
+      if (req.accepts(['json', 'html']) === 'html') {
+        return res.type('html').send(
+          renderSwaggerUI({
+            title: API_INFO.title,
+            specUrl: '/api/openapi.json',
+            assetsPath: '/api/docs/assets',
+            initScriptUrl: '/api/docs/init.js',
+          })
+        );
+      }
+
+      res.json({
+        ...API_INFO,
+        modules: this.modules,
+        endpoints: documentedEndpoints(),
+        websocket: {
+          protocolVersion: PROTOCOL_VERSION,
+          supportedVersions: SUPPORTED_VERSIONS,
+          handshake:
+            "emit 'hello' with { version, messageTypes?, encoding? }; expect 'hello:ack' or 'hello:error'",
+          encodings: ENCODINGS,
+          // Events the caller lacks the permission for are never delivered
+          eventPermissions: EVENT_PERMISSIONS,
+        },
+      });
+    });
+
+    // Sessions, MFA and token revocation change how the account is
+    // secured, so a personal access token cannot be used for them
+    const requireSession = (req, res, next) => {
+      if (req.user?.personalTokenId) {
+        return res.status(403).json({
+          error: 'Sign in interactively; access tokens cannot manage this',
+        });
+      }
+      next();
+    };
+
+    // Session activity; registered ahead of the auth router
+    this.app.get(
+      '/api/auth/sessions/activity',
+      authMiddleware,
+      requireSession,
+      async (req, res) => {
+        res.json(
+          await this.authManager.getSessionActivity(req.user.id, {
+            limit: parseInt(req.query.limit, 10) || 50,
+          })
+        );
+      }
+    );
+
+    this.app.delete(
+      '/api/auth/sessions/:id',
+      authMiddleware,
+      requireSession,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.authManager.revokeSession(req.user.id, req.params.id)
+          );
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
+
+    // Single sign-on; registered ahead of the auth router
+    this.app.get('/api/auth/sso', (req, res) => {
+      res.json(this.authManager.getSsoStatus());
+    });
+
+    this.app.get('/api/auth/sso/login', async (req, res) => {
+      try {
+        // Only same-site paths, so the callback cannot bounce tokens away
+        const { returnTo } = req.query;
+        const safeReturnTo =
+          typeof returnTo === 'string' && /^\/(?![/\\])/.test(returnTo)
+            ? returnTo
+            : null;
+
+        const { url } = await this.authManager.startSsoLogin({
+          returnTo: safeReturnTo,
+        });
+        if (req.accepts(['html', 'json']) === 'json') {
+          return res.json({ url });
+        }
+        res.redirect(url);
+      } catch (error) {
+        res.status(400).json({ error: error.message });
+      }
+    });
+
+    this.app.get('/api/auth/sso/callback', async (req, res) => {
+      try {
+        const { returnTo, ...result } =
+          await this.authManager.completeSsoLogin(
+            req.query,
+            this.loginContext(req)
+          );
+        if (!returnTo) {
+          return res.json(result);
+        }
+
+        // Tokens ride in the fragment so they stay out of server logs; an
+        // MFA challenge is handed back the same way
+        const fragment = new URLSearchParams(
+          result.mfaToken
+            ? {
+                mfaToken: result.mfaToken,
+                mfa: result.mfaRequired ? 'verify' : 'setup',
+              }
+            : {
+                token: result.token,
+                refreshToken: result.session.refreshToken,
+              }
+        );
+        res.redirect(`${returnTo}#${fragment}`);
+      } catch (error) {
+        res.status(401).json({ error: error.message });
+      }
+    });
+
+    // MFA; login answers { mfaRequired | mfaSetupRequired, mfaToken } when a
+    // second factor is needed, and these finish it
+    this.app.post('/api/auth/mfa/verify', async (req, res) => {
+      try {
+        res.json(
+          await this.authManager.completeMfaLogin(
+            req.body.mfaToken,
+            req.body.code,
+            this.loginContext(req)
+          )
+        );
+      } catch (error) {
+        res.status(401).json({ error: error.message });
+      }
+    });
+
+    this.app.post('/api/auth/mfa/setup', async (req, res) => {
+      try {
+        const user = this.authManager.verifyMfaChallenge(
+          req.body.mfaToken,
+          'mfa-setup'
+        );
+        res.json(await this.authManager.enrollMfa(user.id));
+      } catch (error) {
+        res.status(401).json({ error: error.message });
+      }
+    });
+
+    this.app.post('/api/auth/mfa/setup/confirm', async (req, res) => {
+      try {
+        res.json(
+          await this.authManager.completeMfaSetup(
+            req.body.mfaToken,
+            req.body.code,
+            this.loginContext(req)
+          )
+        );
+      } catch (error) {
+        res.status(401).json({ error: error.message });
+      }
+    });
+
+    this.app.get(
+      '/api/auth/mfa',
+      authMiddleware,
+      requireSession,
+      (req, res) => {
+        res.json(this.authManager.getMfaStatus(req.user.id));
+      }
+    );
+
+    this.app.post(
+      '/api/auth/mfa/enroll',
+      authMiddleware,
+      requireSession,
+      async (req, res) => {
+        try {
+          res.json(await this.authManager.enrollMfa(req.user.id));
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.post(
+      '/api/auth/mfa/confirm',
+      authMiddleware,
+      requireSession,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.authManager.confirmMfa(req.user.id, req.body.code)
+          );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.post(
+      '/api/auth/mfa/backup-codes',
+      authMiddleware,
+      requireSession,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.authManager.regenerateBackupCodes(
+              req.user.id,
+              req.body.code
+            )
+          );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.delete(
+      '/api/auth/mfa',
+      authMiddleware,
+      requireSession,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.authManager.disableMfa(req.user.id, req.body.code)
+          );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    // Personal access tokens for scripts and CI; the token is returned once
+    this.app.get('/api/auth/tokens', authMiddleware, (req, res) => {
+      res.json(this.authManager.listPersonalTokens(req.user.id));
+    });
+
+    // A token that could mint tokens would outlive its own expiry
+    this.app.post(
+      '/api/auth/tokens',
+      authMiddleware,
+      requireSession,
+      async (req, res) => {
+        try {
+          res
+            .status(201)
+            .json(
+              await this.authManager.createPersonalToken(
+                req.user.id,
+                req.body,
+                req.user
+              )
+            );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.delete(
+      '/api/auth/tokens/:id',
+      authMiddleware,
+      requireSession,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.authManager.revokePersonalToken(
+              req.user.id,
+              req.params.id
+            )
+          );
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
+
+    // Project cloning; registered ahead of the projects router
+    this.app.post(
+      '/api/projects/:id/clone',
+      authMiddleware,
+      async (req, res) => {
+        try {
+          const {
+            name,
+            includeFiles,
+            includeHistory,
+            includeTags,
+            includeMetadata,
+          } = req.body;
+          res.status(202).json(
+            await this.projectManager.cloneProject(req.params.id, {
+              name,
+              includeFiles,
+              includeHistory,
+              includeTags,
+              includeMetadata,
+              createdBy: req.user.id,
+            })
+          );
+        } catch (error) {
+          const status = error.message.startsWith('Project not found')
+            ? 404
+            : 400;
+          res.status(status).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.get(
+      '/api/projects/clone-jobs/:jobId',
+      authMiddleware,
+      (req, res) => {
+        try {
+          res.json(this.projectManager.getCloneJob(req.params.jobId));
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
+
+    // Watching; registered ahead of the projects router
+    this.app.post(
+      '/api/projects/:id/watch',
+      authMiddleware,
+      async (req, res) => {
+        try {
+          const project = await this.projectManager.getProject(req.params.id);
+          res.json(
+            await this.watches.watch(req.user.id, 'project', project.id)
+          );
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.delete(
+      '/api/projects/:id/watch',
+      authMiddleware,
+      async (req, res) => {
+        try {
+          const project = await this.projectManager.getProject(req.params.id);
+          res.json(
+            await this.watches.unwatch(req.user.id, 'project', project.id)
+          );
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.get(
+      '/api/projects/:id/watchers',
+      authMiddleware,
+      async (req, res) => {
+        try {
+          const project = await this.projectManager.getProject(req.params.id);
+          res.json({
+            count: this.watches.getWatchers('project', project.id).length,
+            watching: this.watches.isWatching(
+              req.user.id,
+              'project',
+              project.id
+            ),
+          });
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.get('/api/watching', authMiddleware, (req, res) => {
+      res.json({ watched: this.watches.listWatched(req.user.id) });
+    });
+
+    this.app.get('/api/watching/feed', authMiddleware, (req, res) => {
+      const limit = parseInt(req.query.limit, 10) || 50;
+      res.json({
+        entries: this.watches.getFeed(req.user.id, {
+          limit,
+          before: req.query.before,
+        }),
+      });
+    });
+
+    // API routes
+    this.app.use('/api/auth', authRoutes);
+    this.app.use(
+      '/api/projects',
+      authMiddleware,
+      this.afterCreate((req, project) => {
+        if (this.modules.onboarding) {
+          this.completeOnboarding(req.user.id, 'create-project');
+        }
+        if (project?.id) {
+          this.watchItem(req.user.id, 'project', project.id, 'created');
+        }
+      }),
+      projectRoutes
+    );
+    this.app.use('/api/system', authMiddleware, systemRoutes);
+    this.app.use('/api/webhooks', webhookRoutes);
+
+    // First-run setup; locks itself once the initial admin exists
+    this.app.get('/api/setup', (req, res) => {
+      res.json(this.setup.getStatus());
+    });
+
+    this.app.post('/api/setup', async (req, res, next) => {
+      try {
+        const result = await this.setup.complete(
+          req.body || {},
+          req.get('X-Setup-Token') || req.body?.bootstrapToken
+        );
+        res.status(201).json({ ...result, restartRequired: true });
+      } catch (error) {
+        const status = {
+          [SETUP_ERRORS.LOCKED]: 409,
+          [SETUP_ERRORS.INVALID_TOKEN]: 403,
+          [SETUP_ERRORS.INVALID_REQUEST]: 400,
+        }[error.code];
+        if (!status) return next(error);
+        res.status(status).json({ error: error.message, code: error.code });
+      }
+    });
+
+    this.app.get('/api/setup/settings', authMiddleware, (req, res) => {
+      if (req.user?.role !== 'admin') {
+        return res.status(403).json({ error: 'Admin access required' });
+      }
+      res.json(this.setup.getSettings());
+    });
+
+    // Embeddable project status widgets, protected by a per-project token
+    // that only the project's owner or an admin can issue or revoke
+    const requireProjectOwner = async (req, res, next) => {
+      let project;
+      try {
+        project = await this.projectManager.getProject(req.params.id);
+      } catch (error) {
+        return res.status(404).json({ error: error.message });
+      }
+
+      const owner = project.metadata?.owner;
+      const isOwner =
+        owner != null && [req.user?.id, req.user?.username].includes(owner);
+      if (req.user?.role !== 'admin' && !isOwner) {
+        return res
+          .status(403)
+          .json({ error: 'Project owner or admin access required' });
+      }
+      next();
+    };
+
+    this.app.post(
+      '/api/widgets/projects/:id/token',
+      authMiddleware,
+      requireProjectOwner,
+      async (req, res) => {
+        try {
+          const token = await this.projectManager.createWidgetToken(
+            req.params.id
+          );
+          res.status(201).json({ token });
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.delete(
+      '/api/widgets/projects/:id/token',
+      authMiddleware,
+      requireProjectOwner,
+      async (req, res) => {
+        try {
+          await this.projectManager.revokeWidgetToken(req.params.id);
+          res.json({ revoked: true });
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
+
+    const widgetCache = `public, max-age=${this.config.widgetCacheAge}`;
+
+    this.app.get('/api/widgets/projects/:id', async (req, res, next) => {
+      try {
+        const widget = await this.getProjectWidget(
+          req.params.id,
+          req.query.token || req.get('X-Widget-Token')
+        );
+        if (!widget) {
+          return res.status(404).json({ error: 'Widget not found' });
+        }
+
+        res.set('Cache-Control', widgetCache);
+        res.json(widget);
+      } catch (error) {
+        next(error);
+      }
+    });
+
+    this.app.get('/api/widgets/projects/:id/embed', async (req, res, next) => {
+      try {
+        const widget = await this.getProjectWidget(
+          req.params.id,
+          req.query.token
+        );
+        if (!widget) {
+          return res.status(404).send('Widget not found');
+        }
+
+        // Meant to be framed by wikis and docs on other origins
+        res.removeHeader('X-Frame-Options');
+        res.set(
+          'Content-Security-Policy',
+          "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors *"
+        );
+        res.set('Cache-Control', widgetCache);
+        res.type('html').send(renderWidgetHTML(widget));
+      } catch (error) {
+        next(error);
+      }
+    });
+
+    this.app.get('/api/oembed', async (req, res, next) => {
+      try {
+        if (req.query.format && req.query.format !== 'json') {
+          return res.status(501).json({ error: 'Only json is supported' });
+        }
+
+        let embedURL;
+        try {
+          embedURL = new URL(req.query.url);
+        } catch (error) {
+          return res.status(400).json({ error: 'url is required' });
+        }
+
+        const match = EMBED_PATH.exec(embedURL.pathname);
+        const widget =
+          match &&
+          (await this.getProjectWidget(
+            decodeURIComponent(match[1]),
+            embedURL.searchParams.get('token')
+          ));
+        if (!widget) {
+          return res.status(404).json({ error: 'Widget not found' });
+        }
+
+        res.set('Cache-Control', widgetCache);
+        res.json(
+          buildOEmbed(widget, embedURL.href, {
+            maxwidth: req.query.maxwidth,
+            maxheight: req.query.maxheight,
+            cacheAge: this.config.widgetCacheAge,
+          })
+        );
+      } catch (error) {
+        next(error);
+      }
+    });
+
+    // Usage analytics aggregates
+    this.app.get('/api/analytics', authMiddleware, async (req, res, next) => {
+      try {
+        if (req.user?.role !== 'admin') {
+          return res.status(403).json({ error: 'Admin access required' });
+        }
+
+        res.json(
+          await this.analyticsCollector.getAggregates(req.query.timeRange)
+        );
+      } catch (error) {
+        next(error);
+      }
+    });
+
+    // Incident management
+    const requireAdmin = (req, res, next) => {
+      if (req.user?.role !== 'admin') {
+        return res.status(403).json({ error: 'Admin access required' });
+      }
+      next();
+    };
+
+    this.app.get('/api/incidents', authMiddleware, async (req, res, next) => {
+      try {
+        res.json(
+          await this.incidentManager.listIncidents({
+            status: req.query.status,
+            severity: req.query.severity,
+            projectId: req.query.projectId,
+            includeResolved: req.query.includeResolved === 'true',
+          })
+        );
+      } catch (error) {
+        next(error);
+      }
+    });
+
+    this.app.get('/api/incidents/:id', authMiddleware, (req, res) => {
+      try {
+        res.json(this.incidentManager.getIncident(req.params.id));
+      } catch (error) {
+        res.status(404).json({ error: error.message });
+      }
+    });
+
+    this.app.post(
+      '/api/incidents/:id/acknowledge',
+      authMiddleware,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.incidentManager.acknowledge(
+              req.params.id,
+              req.user?.id,
+              req.body.note
+            )
+          );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.post(
+      '/api/incidents/:id/resolve',
+      authMiddleware,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.incidentManager.resolve(
+              req.params.id,
+              req.user?.id,
+              req.body.resolution
+            )
+          );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    // Only operator-written titles reach the public status page
+    this.app.put(
+      '/api/incidents/:id/public-title',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.incidentManager.setPublicTitle(
+              req.params.id,
+              req.body.publicTitle,
+              req.user?.id
+            )
+          );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    // Announcements
+
+    this.app.get('/api/announcements', authMiddleware, async (req, res) => {
+      res.json(
+        await this.announcementManager.getActiveFor(req.user, {
+          projectIds: req.query.projectId ? [req.query.projectId] : [],
+        })
+      );
+    });
+
+    this.app.get(
+      '/api/announcements/all',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        res.json(await this.announcementManager.listAnnouncements());
+      }
+    );
+
+    this.app.post(
+      '/api/announcements',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res
+            .status(201)
+            .json(
+              await this.announcementManager.createAnnouncement(
+                req.body,
+                req.user?.id
+              )
+            );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.put(
+      '/api/announcements/:id',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.announcementManager.updateAnnouncement(
+              req.params.id,
+              req.body
+            )
+          );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.delete(
+      '/api/announcements/:id',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.announcementManager.deleteAnnouncement(req.params.id)
+          );
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.post(
+      '/api/announcements/:id/dismiss',
+      authMiddleware,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.announcementManager.dismiss(req.params.id, req.user?.id)
+          );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    // Onboarding checklist and dashboard views
+    this.app.get('/api/onboarding', authMiddleware, (req, res) => {
+      res.json({
+        checklist: this.onboarding.getProgress(req.user.id),
+        ...this.onboarding.getViews(req.user),
+      });
+    });
+
+    this.app.post(
+      '/api/onboarding/items/:id/complete',
+      authMiddleware,
+      async (req, res) => {
+        try {
+          res.json(await this.onboarding.complete(req.user.id, req.params.id));
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.post(
+      '/api/onboarding/items/:id/dismiss',
+      authMiddleware,
+      async (req, res) => {
+        try {
+          res.json(await this.onboarding.dismiss(req.user.id, req.params.id));
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.delete(
+      '/api/onboarding/items/:id/dismiss',
+      authMiddleware,
+      async (req, res) => {
+        try {
+          res.json(await this.onboarding.restore(req.user.id, req.params.id));
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.get('/api/onboarding/views', authMiddleware, (req, res) => {
+      res.json(this.onboarding.getViews(req.user));
+    });
+
+    this.app.put('/api/onboarding/views', authMiddleware, async (req, res) => {
+      try {
+        res.json(await this.onboarding.saveViews(req.user, req.body.views));
+      } catch (error) {
+        res.status(400).json({ error: error.message });
+      }
+    });
+
+    this.app.delete(
+      '/api/onboarding/views',
+      authMiddleware,
+      async (req, res) => {
+        res.json(await this.onboarding.resetViews(req.user));
+      }
+    );
+
+    this.app.get(
+      '/api/onboarding/default-views',
+      authMiddleware,
+      requireAdmin,
+      (req, res) => {
+        res.json(this.onboarding.listDefaultViews());
+      }
+    );
+
+    this.app.put(
+      '/api/onboarding/default-views/:role',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.onboarding.setDefaultViews(
+              req.params.role,
+              req.body.views
+            )
+          );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    // Alert rules and silences
+    const alertRules = this.statusMonitor.alertRules;
+
+    this.app.get('/api/alerts', authMiddleware, (req, res) => {
+      res.json(alertRules.getActiveAlerts());
+    });
+
+    this.app.get('/api/alerts/rules', authMiddleware, (req, res) => {
+      res.json(alertRules.listRules());
+    });
+
+    this.app.post(
+      '/api/alerts/rules',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res
+            .status(201)
+            .json(await alertRules.createRule(req.body, req.user?.id));
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.put(
+      '/api/alerts/rules/:id',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res.json(await alertRules.updateRule(req.params.id, req.body));
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.delete(
+      '/api/alerts/rules/:id',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res.json(await alertRules.deleteRule(req.params.id));
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.get('/api/alerts/silences', authMiddleware, (req, res) => {
+      res.json(alertRules.listSilences());
+    });
+
+    this.app.post(
+      '/api/alerts/silences',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res
+            .status(201)
+            .json(await alertRules.createSilence(req.body, req.user?.id));
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.delete(
+      '/api/alerts/silences/:id',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res.json(await alertRules.deleteSilence(req.params.id));
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
+
+    // Per-origin CORS capability grants
+    this.app.get(
+      '/api/cors/grants',
+      authMiddleware,
+      requireAdmin,
+      (req, res) => {
+        res.json(this.corsPolicy.listGrants());
+      }
+    );
+
+    this.app.post(
+      '/api/cors/grants',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res
+            .status(201)
+            .json(await this.corsPolicy.createGrant(req.body, req.user?.id));
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.put(
+      '/api/cors/grants/:id',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res.json(await this.corsPolicy.updateGrant(req.params.id, req.body));
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.delete(
+      '/api/cors/grants/:id',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res.json(await this.corsPolicy.deleteGrant(req.params.id));
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
+
+    // Privacy modes: keep user content away from learning, embeddings and LLMs
+    this.app.get('/api/privacy', authMiddleware, requireAdmin, (req, res) => {
+      res.json(this.privacyGuard.getSettings());
+    });
+
+    this.app.put(
+      '/api/privacy',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.privacyGuard.setOrgMode(req.body.mode, req.user?.id)
+          );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.get(
+      '/api/privacy/audit',
+      authMiddleware,
+      requireAdmin,
+      (req, res) => {
+        res.json(this.privacyGuard.getAudit(req.query.subsystem));
+      }
+    );
+
+    this.app.get(
+      '/api/projects/:id/privacy',
+      authMiddleware,
+      async (req, res) => {
+        try {
+          const project = await this.projectManager.getProject(req.params.id);
+          res.json(this.privacyGuard.getProjectSettings(project.id));
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
 
-    this.projectManager = new ProjectManager();
-    this.statusMonitor = new StatusMonitor();
-    this.authManager = new AuthManager();
-    this.logger = new Logger('APIServer');
+    this.app.put(
+      '/api/projects/:id/privacy',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        let project;
+        try {
+          project = await this.projectManager.getProject(req.params.id);
+        } catch (error) {
+          return res.status(404).json({ error: error.message });
+        }
 
-    this.setupMiddleware();
-    this.setupRoutes();
-    this.setupWebSocket();
-    this.setupErrorHandling();
-  }
+        try {
+          res.json(
+            await this.privacyGuard.setProjectMode(
+              project.id,
+              req.body.mode ?? null,
+              req.user?.id
+            )
+          );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
 
-  setupMiddleware() {
-    // Security middleware
-    this.app.use(
-      helmet({
-        contentSecurityPolicy: {
-          directives: {
-            defaultSrc: ["'self'"],
-            styleSrc: ["'self'", "'unsafe-inline'"],
-            scriptSrc: ["'self'"],
-            imgSrc: ["'self'", 'data:', 'https:'],
-          },
-        },
-      })
+    // MFA recovery for users who lost their device and backup codes
+    this.app.delete(
+      '/api/auth/mfa/users/:id',
+      authMiddleware,
+      requireSession,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.authManager.resetMfa(req.params.id, req.user?.id)
+          );
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
     );
 
-    // CORS
-    this.app.use(cors(this.config.cors));
+    // Backups: versioned archives of users, projects, suggestions and activity
+    this.app.get(
+      '/api/backups',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res.json(await this.backupManager.listBackups());
+        } catch (error) {
+          res.status(500).json({ error: error.message });
+        }
+      }
+    );
 
-    // Rate limiting
-    const limiter = rateLimit(this.config.rateLimit);
-    this.app.use(limiter);
+    this.app.post(
+      '/api/backups',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          const backup = await this.backupManager.createBackup({
+            createdBy: req.user?.id,
+            note: req.body.note,
+          });
+          res.status(201).json(backup);
+        } catch (error) {
+          res.status(500).json({ error: error.message });
+        }
+      }
+    );
 
-    // Compression
-    this.app.use(compression());
+    this.app.post(
+      '/api/backups/:name/restore',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        const dryRun = req.body.dryRun === true || req.query.dryRun === 'true';
+        try {
+          res.json(
+            await this.backupManager.restoreBackup(req.params.name, {
+              dryRun,
+              restoredBy: req.user?.id,
+            })
+          );
+        } catch (error) {
+          const status = /not found/i.test(error.message) ? 404 : 400;
+          res.status(status).json({ error: error.message });
+        }
+      }
+    );
 
-    // Body parsing
-    this.app.use(express.json({ limit: '10mb' }));
-    this.app.use(express.urlencoded({ extended: true, limit: '10mb' }));
+    // Logging policy
+    this.app.get(
+      '/api/logging/policy',
+      authMiddleware,
+      requireAdmin,
+      (req, res) => {
+        res.json(Logger.getPolicy());
+      }
+    );
 
-    // Logging
-    this.app.use((req, res, next) => {
-      this.logger.info(`${req.method} ${req.path}`, {
-        ip: req.ip,
-        userAgent: req.get('User-Agent'),
-        requestId: req.id,
-      });
-      next();
-    });
+    this.app.put(
+      '/api/logging/policy',
+      authMiddleware,
+      requireAdmin,
+      (req, res) => {
+        Logger.configurePolicy(req.body);
+        res.json(Logger.getPolicy());
+      }
+    );
 
-    // Request validation
-    this.app.use(validateRequest);
-  }
+    this.app.post(
+      '/api/logging/capture',
+      authMiddleware,
+      requireAdmin,
+      (req, res) => {
+        if (!req.body.requestId) {
+          return res.status(400).json({ error: 'requestId is required' });
+        }
+        Logger.captureRequest(req.body.requestId, req.body.ttl);
+        res.status(201).json(Logger.getPolicy());
+      }
+    );
 
-  setupRoutes() {
-    // Health check endpoint
-    this.app.get('/health', (req, res) => {
-      res.json({
-        status: 'healthy',
-        timestamp: new Date().toISOString(),
-        version: process.env.npm_package_version || '1.0.0',
-        uptime: process.uptime(),
-      });
+    // Prompt templates
+    this.app.get('/api/prompts', authMiddleware, requireAdmin, (req, res) => {
+      res.json(this.promptTemplates.listTemplates());
     });
 
-    // API documentation
-    this.app.get('/api/docs', (req, res) => {
-      res.json({
-        title: 'R&D Platform API',
-        version: '1.0.0',
-        description:
-          'REST API for R&D Platform project management and system control',
-        endpoints: {
-          auth: {
-            'POST /api/auth/login': 'Authenticate user',
-            'POST /api/auth/logout': 'Logout user',
-            'GET /api/auth/me': 'Get current user info',
-            'POST /api/auth/refresh': 'Refresh access token',
-          },
-          projects: {
-            'GET /api/projects': 'List projects',
-            'POST /api/projects': 'Create project',
-            'GET /api/projects/:id': 'Get project details',
-            'PUT /api/projects/:id': 'Update project',
-            'DELETE /api/projects/:id': 'Delete project',
-            'POST /api/projects/:id/start': 'Start project',
-            'POST /api/projects/:id/stop': 'Stop project',
-            'GET /api/projects/:id/status': 'Get project status',
-            'GET /api/projects/:id/logs': 'Get project logs',
-          },
-          system: {
-            'GET /api/system/status': 'Get system status',
-            'GET /api/system/health': 'Health check',
-            'GET /api/system/metrics': 'Get system metrics',
-            'POST /api/system/maintenance': 'Trigger maintenance',
-          },
-          webhooks: {
-            'POST /api/webhooks/github': 'GitHub webhook',
-            'POST /api/webhooks/deploy': 'Deployment webhook',
-            'GET /api/webhooks': 'List webhooks',
-          },
-        },
-      });
-    });
+    this.app.get(
+      '/api/prompts/:name',
+      authMiddleware,
+      requireAdmin,
+      (req, res) => {
+        try {
+          res.json(this.promptTemplates.getTemplate(req.params.name));
+        } catch (error) {
+          res.status(404).json({ error: error.message });
+        }
+      }
+    );
 
-    // API routes
-    this.app.use('/api/auth', authRoutes);
-    this.app.use('/api/projects', authMiddleware, projectRoutes);
-    this.app.use('/api/system', authMiddleware, systemRoutes);
-    this.app.use('/api/webhooks', webhookRoutes);
+    this.app.post(
+      '/api/prompts/:name/versions',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res
+            .status(201)
+            .json(
+              await this.promptTemplates.createVersion(
+                req.params.name,
+                req.body,
+                req.user?.id
+              )
+            );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.put(
+      '/api/prompts/:name',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        try {
+          res.json(
+            await this.promptTemplates.configure(req.params.name, req.body)
+          );
+        } catch (error) {
+          res.status(400).json({ error: error.message });
+        }
+      }
+    );
+
+    this.app.post(
+      '/api/prompts/:name/render',
+      authMiddleware,
+      requireAdmin,
+      (req, res) => {
+        try {
+          const { variables, ...options } = req.body;
+          res.json(
+            this.promptTemplates.render(req.params.name, variables, options)
+          );
+        } catch (error) {
+          const status = error instanceof PromptTemplateError ? 422 : 400;
+          res
+            .status(status)
+            .json({ error: error.message, missing: error.missing });
+        }
+      }
+    );
 
     // WebSocket status endpoint
     this.app.get('/api/socket/status', authMiddleware, (req, res) => {
       res.json({
         connected: this.io.engine.clientsCount,
         rooms: Object.keys(this.io.sockets.adapter.rooms),
+        encodings: this.encodingStats.summary(),
       });
     });
+
+    // Per-connection view for debugging missing events
+    this.app.get(
+      '/api/socket/connections',
+      authMiddleware,
+      requireAdmin,
+      async (req, res) => {
+        const sockets = await this.io.fetchSockets();
+        res.json(
+          sockets.map((socket) => ({
+            id: socket.id,
+            userId: socket.user?.id,
+            username: socket.user?.username,
+            role: socket.user?.role,
+            protocol: socket.data.protocol,
+            rooms: Array.from(socket.rooms),
+            deniedEvents: socket.data.deniedEvents || {},
+          }))
+        );
+      }
+    );
   }
 
   setupWebSocket() {
     this.io.use(async (socket, next) => {
       try {
         const token = socket.handshake.auth.token;
-        const user = await this.authManager.verifyToken(token);
+        const { user } = await this.authManager.verifyToken(token, {
+          ipAddress: socket.handshake.address,
+        });
         socket.user = user;
         next();
       } catch (error) {
@@ -176,11 +1794,53 @@ class APIServer {
         username: socket.user.username,
       });
 
-      // Join user-specific room
+      // Join user-specific and role rooms
       socket.join(`user:${socket.user.id}`);
+      socket.join(`role:${socket.user.role}`);
+
+      // Protocol negotiation; until a hello arrives the client gets v1
+      socket.data.protocol = defaultSession();
+
+      // Event type -> messages withheld by permission filtering
+      socket.data.deniedEvents = {};
+
+      socket.on('hello', (hello) => {
+        try {
+          socket.data.protocol = negotiate(hello);
+          socket.emit('hello:ack', {
+            version: socket.data.protocol.version,
+            serverVersion: PROTOCOL_VERSION,
+            messageTypes: socket.data.protocol.messageTypes,
+            encoding: socket.data.protocol.encoding,
+          });
+        } catch (error) {
+          socket.emit('hello:error', {
+            code: error.code,
+            message: error.message,
+            supportedVersions: SUPPORTED_VERSIONS,
+          });
+          socket.disconnect(true);
+        }
+      });
 
       // Project status subscriptions
-      socket.on('subscribe:project', (projectId) => {
+      // Decodes a client payload; a malformed frame gets an error event
+      // instead of throwing out of the handler
+      const receive = (payload) => {
+        try {
+          return deserialize(socket.data.protocol, payload);
+        } catch (error) {
+          this.send(socket, 'error', {
+            code: error.code,
+            message: error.message,
+          });
+          return undefined;
+        }
+      };
+
+      socket.on('subscribe:project', (payload) => {
+        const projectId = receive(payload);
+        if (projectId === undefined) return;
         socket.join(`project:${projectId}`);
         this.logger.info(`User subscribed to project ${projectId}`, {
           userId: socket.user.id,
@@ -188,7 +1848,9 @@ class APIServer {
         });
       });
 
-      socket.on('unsubscribe:project', (projectId) => {
+      socket.on('unsubscribe:project', (payload) => {
+        const projectId = receive(payload);
+        if (projectId === undefined) return;
         socket.leave(`project:${projectId}`);
         this.logger.info(`User unsubscribed from project ${projectId}`, {
           userId: socket.user.id,
@@ -214,25 +1876,31 @@ class APIServer {
       });
 
       // Real-time project operations
-      socket.on('project:start', async (projectId) => {
+      socket.on('project:start', async (payload) => {
+        const projectId = receive(payload);
+        if (projectId === undefined) return;
         try {
           await this.projectManager.startProject(projectId);
-          this.io
-            .to(`project:${projectId}`)
-            .emit('project:started', { projectId });
+          this.watchItem(socket.user.id, 'project', projectId, 'participated');
+          await this.broadcast(`project:${projectId}`, 'project:started', {
+            projectId,
+          });
         } catch (error) {
-          socket.emit('error', { message: error.message });
+          this.send(socket, 'error', { message: error.message });
         }
       });
 
-      socket.on('project:stop', async (projectId) => {
+      socket.on('project:stop', async (payload) => {
+        const projectId = receive(payload);
+        if (projectId === undefined) return;
         try {
           await this.projectManager.stopProject(projectId);
-          this.io
-            .to(`project:${projectId}`)
-            .emit('project:stopped', { projectId });
+          this.watchItem(socket.user.id, 'project', projectId, 'participated');
+          await this.broadcast(`project:${projectId}`, 'project:stopped', {
+            projectId,
+          });
         } catch (error) {
-          socket.emit('error', { message: error.message });
+          this.send(socket, 'error', { message: error.message });
         }
       });
 
@@ -245,16 +1913,242 @@ class APIServer {
 
     // Status monitoring integration
     this.statusMonitor.on('project:status', (data) => {
-      this.io.to(`project:${data.projectId}`).emit('project:status', data);
+      this.broadcast(`project:${data.projectId}`, 'project:status', data);
     });
 
     this.statusMonitor.on('system:status', (data) => {
-      this.io.to('system').emit('system:status', data);
+      this.broadcast('system', 'system:status', data);
     });
 
     this.statusMonitor.on('project:log', (data) => {
-      this.io.to(`project:${data.projectId}`).emit('project:log', data);
+      this.broadcast(`project:${data.projectId}`, 'project:log', data);
+    });
+
+    // Clone jobs report to the user who started them
+    for (const event of [
+      'project:clone-progress',
+      'project:clone-completed',
+      'project:clone-failed',
+    ]) {
+      this.projectManager.on(event, (job) => {
+        if (job.createdBy) {
+          this.broadcast(`user:${job.createdBy}`, event, job);
+        }
+      });
+    }
+    this.projectManager.on('project:clone-completed', (job) => {
+      if (job.createdBy && job.projectId) {
+        this.watchItem(job.createdBy, 'project', job.projectId, 'created');
+      }
+    });
+
+    // Watchers hear about changes to the projects they follow. Status-only
+    // updates are left to the started/stopped events.
+    this.projectManager.on('project:updated', (project, fields = []) => {
+      const changed = fields.filter(
+        (field) => !['status', 'updatedAt'].includes(field)
+      );
+      if (changed.length > 0) {
+        this.recordWatchActivity(
+          project,
+          'updated',
+          `Updated ${changed.join(', ')}`
+        );
+      }
+    });
+    this.projectManager.on('project:started', (project) => {
+      this.recordWatchActivity(project, 'started', 'Project started');
+    });
+    this.projectManager.on('project:stopped', (project) => {
+      this.recordWatchActivity(project, 'stopped', 'Project stopped');
+    });
+    this.projectManager.on('project:deleted', async (project) => {
+      await this.recordWatchActivity(project, 'deleted', 'Project deleted');
+      this.watches.removeItem('project', project.id).catch((error) => {
+        this.logger.error('Failed to remove project watchers:', error);
+      });
+    });
+    this.watches.on('watch:activity', ({ userId, entry }) => {
+      this.broadcast(`user:${userId}`, 'watch:activity', entry);
+    });
+
+    // Alert rule state changes
+    for (const event of ['rule:firing', 'rule:resolved']) {
+      this.statusMonitor.on(event, (alert) => {
+        this.broadcast('system', `alert:${alert.state}`, alert);
+      });
+    }
+
+    // Announcement broadcasts, scoped to the announcement's audience. Only
+    // live announcements go out; scheduled ones are sent when they start,
+    // which clients see as announcement:created
+    const announcementEvents = {
+      'announcement:created': 'announcement:created',
+      'announcement:updated': 'announcement:updated',
+      'announcement:started': 'announcement:created',
+    };
+    for (const [event, clientEvent] of Object.entries(announcementEvents)) {
+      this.announcementManager.on(event, (announcement) => {
+        if (!this.announcementManager.isActive(announcement)) return;
+
+        const { type, value } = announcement.audience;
+        this.broadcast(
+          type === 'all' ? null : `${type}:${value}`,
+          clientEvent,
+          this.announcementManager.toPublic(announcement)
+        );
+      });
+    }
+
+    // Onboarding: complete checklist items when the server sees the action,
+    // and tell the user's own sockets about progress
+    this.authManager.on('user:created', (user) => {
+      if (this.modules.onboarding && user.invitedBy) {
+        this.completeOnboarding(user.invitedBy, 'invite-teammate');
+      }
+    });
+    this.authManager.on('token:first-use', (record) => {
+      if (this.modules.onboarding) {
+        this.completeOnboarding(record.userId, 'connect-agent');
+      }
+    });
+    this.authManager.on('user:deleted', (user) => {
+      if (this.modules.onboarding) {
+        this.onboarding.removeUser(user.id).catch((error) => {
+          this.logger.error('Failed to remove onboarding state:', error);
+        });
+      }
+      this.watches.removeUser(user.id).catch((error) => {
+        this.logger.error('Failed to remove watches:', error);
+      });
+    });
+    for (const event of ['onboarding:item-completed', 'onboarding:completed']) {
+      this.onboarding.on(event, (data) => {
+        this.broadcast(`user:${data.userId}`, event, data);
+      });
+    }
+
+    // New-device logins go only to that user, with a revoke shortcut
+    this.authManager.on('auth:new-device', ({ user, entry }) => {
+      this.broadcast(`user:${user.id}`, 'auth:new-device', {
+        ...entry,
+        revoke: {
+          method: 'DELETE',
+          path: `/api/auth/sessions/${entry.sessionId}`,
+        },
+      });
     });
+
+    // Personal access token expiry reminders go to the token owner
+    for (const event of ['token:expiring', 'token:expired']) {
+      this.authManager.on(event, (record) => {
+        this.broadcast(`user:${record.userId}`, `auth:${event}`, record);
+      });
+    }
+
+    // User management; delivery is limited to admins by EVENT_PERMISSIONS
+    for (const event of ['user:created', 'user:updated', 'user:deleted']) {
+      this.authManager.on(event, (user) => {
+        this.broadcast(null, event, this.authManager.sanitizeUser(user));
+      });
+    }
+
+    // Incident lifecycle notifications
+    for (const event of [
+      'incident:opened',
+      'incident:acknowledged',
+      'incident:escalated',
+      'incident:resolved',
+    ]) {
+      this.incidentManager.on(event, (incident) => {
+        this.broadcast('system', event, incident);
+      });
+    }
+  }
+
+  completeOnboarding(userId, itemId) {
+    this.onboarding.complete(userId, itemId, 'auto').catch((error) => {
+      this.logger.error(`Failed to complete onboarding ${itemId}:`, error);
+    });
+  }
+
+  // Automatic watches are best-effort and never fail the request
+  watchItem(userId, itemType, itemId, reason) {
+    this.watches.watch(userId, itemType, itemId, reason).catch((error) => {
+      this.logger.error(`Failed to watch ${itemType} ${itemId}:`, error);
+    });
+  }
+
+  recordWatchActivity(project, event, summary) {
+    return this.watches
+      .recordActivity({
+        itemType: 'project',
+        itemId: project.id,
+        itemName: project.name,
+        event,
+        summary,
+      })
+      .catch((error) => {
+        this.logger.error('Failed to record watch activity:', error);
+      });
+  }
+
+  // Calls handler(req, createdItem) when the create request on a mounted
+  // router succeeds
+  afterCreate(handler) {
+    return (req, res, next) => {
+      if (req.method === 'POST' && req.path === '/' && req.user) {
+        const json = res.json.bind(res);
+        res.json = (body) => {
+          if (res.statusCode < 300) {
+            handler(req, body?.project || body);
+          }
+          return json(body);
+        };
+      }
+      next();
+    };
+  }
+
+  // Request details recorded with a new session
+  loginContext(req) {
+    return {
+      ipAddress: req.ip,
+      userAgent: req.get('User-Agent'),
+      acceptLanguage: req.get('Accept-Language'),
+    };
+  }
+
+  // Deliver to every socket in a room (or all sockets when room is null),
+  // encoded for each client's negotiated protocol version
+  async broadcast(room, event, data) {
+    try {
+      const sockets = await (room ? this.io.in(room) : this.io).fetchSockets();
+      for (const socket of sockets) {
+        this.send(socket, event, data);
+      }
+    } catch (error) {
+      this.logger.error(`Failed to broadcast ${event}:`, error);
+    }
+  }
+
+  send(socket, event, data) {
+    const permission = requiredPermission(event);
+    if (!hasPermission(socket.user, permission)) {
+      const denied = (socket.data.deniedEvents ??= {});
+      denied[event] = (denied[event] || 0) + 1;
+      this.logger.debug(`Withheld ${event} from ${socket.id}`, {
+        userId: socket.user?.id,
+        permission,
+      });
+      return;
+    }
+
+    const session = socket.data.protocol || defaultSession();
+    if (accepts(session, event)) {
+      const message = encodeMessage(session, event, data);
+      socket.emit(event, this.encodingStats.measure(session, message));
+    }
   }
 
   setupErrorHandling() {
@@ -288,10 +2182,107 @@ class APIServer {
     });
   }
 
+  // Load and validate the configuration before anything else starts, then
+  // hand resolved values to the components that read them
+  async applyConfiguration() {
+    await this.configManager.initialize();
+
+    this.config.port =
+      this.config.port || this.configManager.get('server.port', 8080);
+    this.config.host =
+      this.config.host || this.configManager.get('server.host', '0.0.0.0');
+    this.authManager.applyConfiguration();
+  }
+
+  // Modules chosen during first-run setup; explicit config.modules wins
+  async applySetupModules() {
+    await this.setup.loadState();
+    const chosen = this.setup.getModules();
+
+    for (const name of Object.keys(OPTIONAL_MODULES)) {
+      this.modules[name] =
+        this.config.modules?.[name] ?? chosen[name] ?? this.modules[name];
+    }
+    this.statusMonitor.config.alertRulesEnabled = this.modules.alerts;
+  }
+
+  // Core managers must start; enabled optional modules are deferred and
+  // retried if they fail
+  registerComponents() {
+    const optional = (name, component) => {
+      if (this.modules[name]) {
+        this.startup.register(name, { ...component, critical: false });
+      } else {
+        this.logger.info(`Module ${name} is disabled; skipping`);
+      }
+    };
+
+    this.startup.register('auth', {
+      start: () => this.authManager.initialize(),
+    });
+    this.startup.register('setup', {
+      start: async () => {
+        this.setup.attach({ authManager: this.authManager });
+        await this.setup.initialize();
+      },
+      dependsOn: ['auth'],
+    });
+    this.startup.register('cors', {
+      start: () => this.corsPolicy.initialize(),
+    });
+    this.startup.register('privacy', {
+      start: () => this.privacyGuard.initialize(),
+      stop: () => this.privacyGuard.stop(),
+    });
+    this.startup.register('backups', {
+      start: () => this.backupManager.initialize(),
+    });
+    this.startup.register('projects', {
+      start: () => this.projectManager.initialize(),
+    });
+    this.startup.register('monitoring', {
+      start: () => this.statusMonitor.initialize(),
+      stop: () => this.statusMonitor.stop(),
+    });
+    optional('analytics', {
+      start: () => this.analyticsCollector.initialize(),
+      stop: () => this.analyticsCollector.stop(),
+    });
+    optional('incidents', {
+      start: async () => {
+        await this.incidentManager.initialize();
+        this.incidentManager.attach({
+          statusMonitor: this.statusMonitor,
+          processManager: this.projectManager.processManager,
+        });
+      },
+      stop: () => this.incidentManager.stop(),
+      dependsOn: ['projects', 'monitoring'],
+    });
+    optional('announcements', {
+      start: () => this.announcementManager.initialize(),
+      stop: () => this.announcementManager.stop(),
+    });
+    this.startup.register('watches', {
+      start: () => this.watches.initialize(),
+      stop: () => this.watches.stop(),
+    });
+    optional('onboarding', {
+      start: () => this.onboarding.initialize(),
+      stop: () => this.onboarding.stop(),
+    });
+    optional('prompts', {
+      start: () => this.promptTemplates.initialize(),
+      stop: () => this.promptTemplates.stop(),
+    });
+  }
+
   async start() {
     try {
-      await this.projectManager.initialize();
-      await this.statusMonitor.initialize();
+      await this.applyConfiguration();
+      await this.applySetupModules();
+      this.registerComponents();
+      await this.startup.startAll();
 
       this.httpServer.listen(this.config.port, this.config.host, () => {
         this.logger.info(`API Server started`, {
@@ -326,8 +2317,8 @@ class APIServer {
         this.logger.info('WebSocket server closed');
       });
 
-      // Stop monitoring
-      await this.statusMonitor.stop();
+      // Stop components in reverse startup order
+      await this.startup.stopAll();
 
       this.logger.info('API server stopped successfully');
     } catch (error) {
@@ -355,6 +2346,55 @@ class APIServer {
       });
   }
 
+  async getStatusPage() {
+    const { results, errors } = await fanOut({
+      page: (signal) =>
+        this.statusMonitor.getStatusPage({ signal, nested: true }),
+      incidents: () =>
+        this.modules.incidents ? this.incidentManager.listIncidents() : [],
+    });
+    if (errors.page) throw errors.page;
+
+    const page = results.page;
+    page.incidents = results.incidents || [];
+    return page;
+  }
+
+  // Headline metrics for a widget; null when the project or token is
+  // wrong so callers cannot probe for project ids
+  async getProjectWidget(projectId, token) {
+    const project = this.projectManager.projects.get(projectId);
+    if (!project || !this.projectManager.verifyWidgetToken(project, token)) {
+      return null;
+    }
+
+    const [status, incidents] = await Promise.all([
+      this.projectManager.getProjectStatus(project.id),
+      this.modules.incidents
+        ? this.incidentManager.listIncidents({ projectId: project.id })
+        : [],
+    ]);
+
+    let health = 'ok';
+    if (incidents.some((incident) => incident.severity === 'critical')) {
+      health = 'critical';
+    } else if (incidents.length > 0) {
+      health = 'degraded';
+    }
+
+    const progress = Number(project.metadata?.progress);
+
+    return {
+      project: { id: project.id, name: project.name, status: status.status },
+      progress: Number.isFinite(progress)
+        ? Math.min(Math.max(progress, 0), 100)
+        : null,
+      openIncidents: incidents.length,
+      health,
+      updatedAt: new Date().toISOString(),
+    };
+  }
+
   // Public API for external access
   getExpressApp() {
     return this.app;
@@ -373,10 +2413,7 @@ export { APIServer };
 
 // CLI integration
 if (import.meta.url === `file://${process.argv[1]}`) {
-  const server = new APIServer({
-    port: process.env.PORT || 8080,
-    host: process.env.HOST || '0.0.0.0',
-  });
+  const server = new APIServer();
 
   server.start().catch((error) => {
     console.error('Failed to start server:', error);
@@ -0,0 +1,247 @@
+/**
+ * WebSocket Protocol
+ * Version negotiation and per-version message encoding for socket clients
+ */
+
+import { encode, decode } from '@msgpack/msgpack';
+
+const PROTOCOL_VERSION = 2;
+const SUPPORTED_VERSIONS = [1, 2];
+
+// Wire encodings; msgpack payloads are sent as binary socket.io frames
+const ENCODINGS = ['json', 'msgpack'];
+
+// Permission a connection's user needs to receive each message type (see
+// hasPermission in auth-manager.js). null means any authenticated user:
+// those events are already addressed to the user's own room or audience.
+// Types missing from this map are admin-only, so new events fail closed.
+const EVENT_PERMISSIONS = {
+  error: null,
+  'project:status': 'read',
+  'project:log': 'read',
+  'project:started': 'read',
+  'project:stopped': 'read',
+  'project:clone-progress': 'read',
+  'project:clone-completed': 'read',
+  'project:clone-failed': 'read',
+  'system:status': 'read',
+  'alert:firing': 'read',
+  'alert:resolved': 'read',
+  'announcement:created': null,
+  'announcement:updated': null,
+  'incident:opened': 'read',
+  'incident:acknowledged': 'read',
+  'incident:escalated': 'read',
+  'incident:resolved': 'read',
+  'auth:new-device': null,
+  'auth:token-expiring': null,
+  'auth:token-expired': null,
+  'user:created': 'admin',
+  'user:updated': 'admin',
+  'user:deleted': 'admin',
+  'onboarding:item-completed': null,
+  'onboarding:completed': null,
+  'watch:activity': 'read',
+};
+
+// Server-to-client message types a client can opt into via its hello;
+// every one has an entry above
+const MESSAGE_TYPES = Object.keys(EVENT_PERMISSIONS).filter(
+  (type) => type !== 'error'
+);
+
+function requiredPermission(type) {
+  return type in EVENT_PERMISSIONS ? EVENT_PERMISSIONS[type] : 'admin';
+}
+
+const PROTOCOL_ERRORS = {
+  INVALID_HELLO: 'INVALID_HELLO',
+  UNSUPPORTED_VERSION: 'UNSUPPORTED_PROTOCOL_VERSION',
+  UNSUPPORTED_ENCODING: 'UNSUPPORTED_ENCODING',
+  INVALID_PAYLOAD: 'INVALID_PAYLOAD',
+};
+
+class ProtocolError extends Error {
+  constructor(code, message) {
+    super(message);
+    this.name = 'ProtocolError';
+    this.code = code;
+  }
+}
+
+// Clients that never send a hello keep the original unversioned format
+function defaultSession() {
+  return { version: 1, messageTypes: null, encoding: 'json' };
+}
+
+function negotiate(hello = {}) {
+  if (!hello || typeof hello !== 'object') {
+    throw new ProtocolError(
+      PROTOCOL_ERRORS.INVALID_HELLO,
+      'hello must be an object like { version }'
+    );
+  }
+
+  const version = Number(hello.version);
+
+  if (!Number.isInteger(version)) {
+    throw new ProtocolError(
+      PROTOCOL_ERRORS.INVALID_HELLO,
+      'hello must declare an integer protocol version'
+    );
+  }
+
+  if (!SUPPORTED_VERSIONS.includes(version)) {
+    throw new ProtocolError(
+      PROTOCOL_ERRORS.UNSUPPORTED_VERSION,
+      `Protocol version ${version} is not supported (supported: ${SUPPORTED_VERSIONS.join(', ')})`
+    );
+  }
+
+  let messageTypes = null;
+  if (hello.messageTypes !== undefined) {
+    if (!Array.isArray(hello.messageTypes)) {
+      throw new ProtocolError(
+        PROTOCOL_ERRORS.INVALID_HELLO,
+        'messageTypes must be an array'
+      );
+    }
+    messageTypes = hello.messageTypes.filter((type) =>
+      MESSAGE_TYPES.includes(type)
+    );
+  }
+
+  const encoding = hello.encoding || 'json';
+  if (!ENCODINGS.includes(encoding)) {
+    throw new ProtocolError(
+      PROTOCOL_ERRORS.UNSUPPORTED_ENCODING,
+      `Encoding ${encoding} is not supported (supported: ${ENCODINGS.join(', ')})`
+    );
+  }
+
+  return { version, messageTypes, encoding };
+}
+
+// Errors are always delivered; everything else respects the client's list
+function accepts(session, type) {
+  return (
+    type === 'error' ||
+    !session.messageTypes ||
+    session.messageTypes.includes(type)
+  );
+}
+
+// v2 wraps payloads in a typed envelope; v1 clients get the bare payload
+function encodeMessage(session, type, data) {
+  if (session.version < 2) {
+    return data;
+  }
+
+  return {
+    v: session.version,
+    type,
+    sentAt: new Date().toISOString(),
+    data,
+  };
+}
+
+// Apply the negotiated wire encoding to an encoded message
+function serialize(session, message) {
+  return session.encoding === 'msgpack'
+    ? Buffer.from(encode(message))
+    : message;
+}
+
+// Inverse of serialize for client-to-server payloads. Client bytes are
+// untrusted, so a frame that does not decode is a ProtocolError.
+function deserialize(session, payload) {
+  if (session.encoding === 'msgpack' && payload instanceof Uint8Array) {
+    try {
+      return decode(payload);
+    } catch (error) {
+      throw new ProtocolError(
+        PROTOCOL_ERRORS.INVALID_PAYLOAD,
+        `Malformed msgpack payload: ${error.message}`
+      );
+    }
+  }
+  return payload;
+}
+
+// Per-encoding message counts, sizes, and serialization time so the
+// msgpack savings can be compared against the JSON equivalent. Sizes and
+// timings come from 1 in sampleEvery messages, since measuring the JSON
+// size costs an extra JSON.stringify.
+class EncodingStats {
+  constructor({ sampleEvery = 20 } = {}) {
+    this.sampleEvery = sampleEvery;
+    this.stats = Object.fromEntries(
+      ENCODINGS.map((encoding) => [
+        encoding,
+        { messages: 0, sampled: 0, bytes: 0, jsonBytes: 0, serializeMs: 0 },
+      ])
+    );
+  }
+
+  measure(session, message) {
+    const entry = this.stats[session.encoding];
+    entry.messages++;
+    if ((entry.messages - 1) % this.sampleEvery !== 0) {
+      return serialize(session, message);
+    }
+
+    const start = process.hrtime.bigint();
+    const payload = serialize(session, message);
+    const elapsed = Number(process.hrtime.bigint() - start) / 1e6;
+
+    const jsonBytes = Buffer.byteLength(JSON.stringify(message) ?? '');
+    entry.sampled++;
+    entry.bytes += Buffer.isBuffer(payload) ? payload.length : jsonBytes;
+    entry.jsonBytes += jsonBytes;
+    entry.serializeMs += elapsed;
+
+    return payload;
+  }
+
+  summary() {
+    return Object.fromEntries(
+      Object.entries(this.stats).map(([encoding, entry]) => {
+        const avgBytes = entry.sampled ? entry.bytes / entry.sampled : 0;
+        return [
+          encoding,
+          {
+            messages: entry.messages,
+            sampled: entry.sampled,
+            // Estimated from the sampled messages
+            bytes: Math.round(avgBytes * entry.messages),
+            avgBytes,
+            avgSerializeMs: entry.sampled
+              ? entry.serializeMs / entry.sampled
+              : 0,
+            savedRatio: entry.jsonBytes
+              ? 1 - entry.bytes / entry.jsonBytes
+              : 0,
+          },
+        ];
+      })
+    );
+  }
+}
+
+export {
+  PROTOCOL_VERSION,
+  SUPPORTED_VERSIONS,
+  ENCODINGS,
+  MESSAGE_TYPES,
+  EVENT_PERMISSIONS,
+  PROTOCOL_ERRORS,
+  ProtocolError,
+  defaultSession,
+  negotiate,
+  accepts,
+  requiredPermission,
+  encodeMessage,
+  serialize,
+  deserialize,
+  EncodingStats,
+};
@@ -0,0 +1,162 @@
+/**
+ * Tests for WebSocket Protocol
+ */
+
+import {
+  MESSAGE_TYPES,
+  EVENT_PERMISSIONS,
+  PROTOCOL_ERRORS,
+  defaultSession,
+  negotiate,
+  accepts,
+  encodeMessage,
+  serialize,
+  deserialize,
+  requiredPermission,
+  EncodingStats,
+} from './socket-protocol.js';
+
+describe('socket protocol', () => {
+  describe('negotiate', () => {
+    it('should accept a supported version with defaults', () => {
+      expect(negotiate({ version: 2 })).toEqual({
+        version: 2,
+        messageTypes: null,
+        encoding: 'json',
+      });
+    });
+
+    it('should reject null and non-object hellos', () => {
+      for (const hello of [null, 'v2', 2]) {
+        expect(() => negotiate(hello)).toThrow(
+          'hello must be an object like { version }'
+        );
+      }
+    });
+
+    it('should report the error code', () => {
+      const codeOf = (hello) => {
+        try {
+          negotiate(hello);
+        } catch (error) {
+          return error.code;
+        }
+      };
+
+      expect(codeOf({})).toBe(PROTOCOL_ERRORS.INVALID_HELLO);
+      expect(codeOf({ version: 9 })).toBe(PROTOCOL_ERRORS.UNSUPPORTED_VERSION);
+      expect(codeOf({ version: 2, messageTypes: 'project:log' })).toBe(
+        PROTOCOL_ERRORS.INVALID_HELLO
+      );
+    });
+
+    it('should drop unknown message types', () => {
+      const session = negotiate({
+        version: 2,
+        messageTypes: ['project:log', 'bogus', 'error'],
+      });
+
+      expect(session.messageTypes).toEqual(['project:log']);
+    });
+  });
+
+  describe('accepts', () => {
+    it('should always deliver errors', () => {
+      const session = negotiate({ version: 2, messageTypes: ['project:log'] });
+
+      expect(accepts(session, 'error')).toBe(true);
+      expect(accepts(session, 'project:log')).toBe(true);
+      expect(accepts(session, 'system:status')).toBe(false);
+      expect(accepts(defaultSession(), 'system:status')).toBe(true);
+    });
+  });
+
+  describe('encodeMessage', () => {
+    it('should wrap v2 payloads and leave v1 bare', () => {
+      const data = { id: 'p1' };
+
+      expect(encodeMessage(defaultSession(), 'project:log', data)).toBe(data);
+      expect(
+        encodeMessage({ version: 2 }, 'project:log', data)
+      ).toMatchObject({ v: 2, type: 'project:log', data });
+    });
+  });
+
+  describe('msgpack encoding', () => {
+    it('should negotiate supported encodings only', () => {
+      expect(negotiate({ version: 2, encoding: 'msgpack' }).encoding).toBe(
+        'msgpack'
+      );
+      try {
+        negotiate({ version: 2, encoding: 'xml' });
+        throw new Error('expected negotiate to fail');
+      } catch (error) {
+        expect(error.code).toBe(PROTOCOL_ERRORS.UNSUPPORTED_ENCODING);
+      }
+    });
+
+    it('should round-trip msgpack payloads', () => {
+      const session = negotiate({ version: 2, encoding: 'msgpack' });
+      const message = { type: 'project:log', data: { line: 'ok' } };
+
+      const payload = serialize(session, message);
+
+      expect(Buffer.isBuffer(payload)).toBe(true);
+      expect(deserialize(session, payload)).toEqual(message);
+    });
+
+    it('should reject malformed msgpack frames', () => {
+      const session = negotiate({ version: 2, encoding: 'msgpack' });
+
+      // 0xc1 is never used by msgpack; 0xa5 starts a truncated string
+      for (const bytes of [[0xc1], [0xa5, 0x68, 0x69]]) {
+        try {
+          deserialize(session, Uint8Array.from(bytes));
+          throw new Error('expected deserialize to fail');
+        } catch (error) {
+          expect(error.name).toBe('ProtocolError');
+          expect(error.code).toBe(PROTOCOL_ERRORS.INVALID_PAYLOAD);
+        }
+      }
+    });
+
+    it('should pass JSON payloads through', () => {
+      const message = { projectId: 'p1' };
+
+      expect(serialize(defaultSession(), message)).toBe(message);
+      expect(deserialize(defaultSession(), message)).toBe(message);
+    });
+  });
+
+  describe('requiredPermission', () => {
+    it('should map every message type to a permission', () => {
+      expect(MESSAGE_TYPES).not.toContain('error');
+      expect(MESSAGE_TYPES).toHaveLength(
+        Object.keys(EVENT_PERMISSIONS).length - 1
+      );
+      expect(requiredPermission('project:log')).toBe('read');
+      expect(requiredPermission('auth:new-device')).toBeNull();
+      expect(requiredPermission('user:created')).toBe('admin');
+    });
+
+    it('should treat unknown types as admin-only', () => {
+      expect(requiredPermission('secret:event')).toBe('admin');
+    });
+  });
+
+  describe('EncodingStats', () => {
+    it('should count every message and sample some', () => {
+      const stats = new EncodingStats({ sampleEvery: 5 });
+      const session = defaultSession();
+
+      for (let i = 0; i < 12; i++) {
+        stats.measure(session, { i });
+      }
+
+      const { json } = stats.summary();
+      expect(json.messages).toBe(12);
+      expect(json.sampled).toBe(3);
+      expect(json.bytes).toBe(Math.round(json.avgBytes * 12));
+    });
+  });
+});
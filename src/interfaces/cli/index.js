@@ -12,12 +12,21 @@ import { ProjectManager } from '../core/project-manager.js';
 import { StatusMonitor } from '../core/status-monitor.js';
 import { AuthManager } from '../core/auth-manager.js';
 import { APIClient } from '../core/api-client.js';
+import { ConfigManager } from '../core/config-manager.js';
+import { AnnouncementManager } from '../core/announcement-manager.js';
+import { BackupManager } from '../core/backup-manager.js';
+import {
+  NotificationRelay,
+  DEFAULT_EVENT_TYPES,
+} from '../core/notification-relay.js';
 
 const VERSION = '1.0.0';
-const projectManager = new ProjectManager();
+const configManager = new ConfigManager();
+const projectManager = new ProjectManager({ configManager });
 const statusMonitor = new StatusMonitor();
-const authManager = new AuthManager();
+const authManager = new AuthManager({ configManager });
 const apiClient = new APIClient();
+const announcementManager = new AnnouncementManager();
 
 // Global error handler
 process.on('uncaughtException', (error) => {
@@ -34,7 +43,38 @@ process.on('unhandledRejection', (reason) => {
 program
   .name('rd-platform')
   .description('R&D Platform CLI - Project Management and System Control')
-  .version(VERSION);
+  .version(VERSION)
+  .option('-c, --config <file>', 'Configuration file')
+  .option(
+    '--set <key=value>',
+    'Override a configuration key (repeatable)',
+    collectOverride,
+    {}
+  )
+  .option('--validate-config', 'Validate configuration and exit')
+  .hook('preAction', async () => {
+    // Every command runs against the loaded, validated configuration
+    const options = program.opts();
+    if (options.config) configManager.config.configFile = options.config;
+    configManager.config.overrides = options.set;
+
+    try {
+      await configManager.initialize();
+    } catch (error) {
+      console.error(chalk.red('✖ Configuration check failed:'), error.message);
+      process.exit(1);
+    }
+
+    authManager.applyConfiguration();
+  })
+  .action(async (options) => {
+    if (!options.validateConfig) {
+      program.outputHelp();
+      return;
+    }
+
+    console.log(chalk.green('✓ Configuration is valid'));
+  });
 
 // Authentication commands
 program
@@ -47,6 +87,7 @@ program
       .option('-u, --username <username>', 'Username')
       .option('-p, --password <password>', 'Password')
       .option('--token <token>', 'API token')
+      .option('--code <code>', 'MFA code or backup code')
       .action(async (options) => {
         try {
           let credentials = options;
@@ -69,9 +110,27 @@ program
             ]);
           }
 
-          const result = await authManager.login(credentials);
+          let result = await authManager.login(credentials);
+          if (result.mfaSetupRequired) {
+            console.log(
+              chalk.yellow('⚠ Your account requires MFA; set it up first:')
+            );
+            console.log(chalk.dim('  rd-platform auth mfa enroll'));
+            process.exit(1);
+          }
+          if (result.mfaRequired) {
+            result = await authManager.completeMfaLogin(
+              result.mfaToken,
+              options.code || (await promptMfaCode())
+            );
+          }
           console.log(chalk.green('✓ Successfully logged in'));
           console.log(chalk.dim(`Token: ${result.token}`));
+
+          await announcementManager.initialize();
+          displayAnnouncements(
+            await announcementManager.getActiveFor(result.user)
+          );
         } catch (error) {
           console.error(chalk.red('✖ Login failed:'), error.message);
           process.exit(1);
@@ -114,6 +173,112 @@ program
           console.error(chalk.red('✖ Status check failed:'), error.message);
         }
       })
+  )
+  .addCommand(
+    program
+      .createCommand('mfa')
+      .description('Manage multi-factor authentication (TOTP)')
+      .addCommand(
+        program
+          .createCommand('status')
+          .description('Show MFA status')
+          .option('-u, --username <username>', 'Username')
+          .action(async (options) => {
+            try {
+              const user = await signInLocally(options);
+              const status = authManager.getMfaStatus(user.id);
+
+              const state = status.enabled
+                ? chalk.green('enabled')
+                : chalk.yellow('disabled');
+              console.log(`MFA: ${state}`);
+              if (status.required) {
+                console.log(chalk.dim(`Required for role: ${user.role}`));
+              }
+              if (status.enabled) {
+                console.log(
+                  chalk.dim(`Backup codes left: ${status.backupCodesRemaining}`)
+                );
+              }
+            } catch (error) {
+              console.error(chalk.red('✖ MFA status failed:'), error.message);
+              process.exit(1);
+            }
+          })
+      )
+      .addCommand(
+        program
+          .createCommand('enroll')
+          .description('Enroll an authenticator app')
+          .option('-u, --username <username>', 'Username')
+          .action(async (options) => {
+            try {
+              const user = await signInLocally(options);
+              const { secret, otpauthUri } = await authManager.enrollMfa(
+                user.id
+              );
+
+              console.log(chalk.bold('Add this account to your authenticator'));
+              console.log(`URI (render as a QR code): ${otpauthUri}`);
+              console.log(`Or enter the secret manually: ${secret}`);
+
+              const { backupCodes } = await authManager.confirmMfa(
+                user.id,
+                await promptMfaCode('Code from the app:')
+              );
+              console.log(chalk.green('✓ MFA enabled'));
+              displayBackupCodes(backupCodes);
+            } catch (error) {
+              console.error(
+                chalk.red('✖ MFA enrollment failed:'),
+                error.message
+              );
+              process.exit(1);
+            }
+          })
+      )
+      .addCommand(
+        program
+          .createCommand('backup-codes')
+          .description('Replace your backup codes')
+          .option('-u, --username <username>', 'Username')
+          .action(async (options) => {
+            try {
+              const user = await signInLocally(options);
+              const { backupCodes } = await authManager.regenerateBackupCodes(
+                user.id,
+                await promptMfaCode()
+              );
+              console.log(chalk.green('✓ Backup codes replaced'));
+              displayBackupCodes(backupCodes);
+            } catch (error) {
+              console.error(
+                chalk.red('✖ Failed to replace backup codes:'),
+                error.message
+              );
+              process.exit(1);
+            }
+          })
+      )
+      .addCommand(
+        program
+          .createCommand('disable')
+          .description('Disable MFA')
+          .option('-u, --username <username>', 'Username')
+          .action(async (options) => {
+            try {
+              const user = await signInLocally(options);
+              await authManager.disableMfa(user.id, await promptMfaCode());
+              console.log(chalk.green('✓ MFA disabled'));
+            } catch (error) {
+              console.error(
+                chalk.red('✖ Failed to disable MFA:'),
+                error.message
+              );
+              process.exit(1);
+            }
+          })
+      )
   );
 
 // Project management commands
@@ -128,6 +293,10 @@ program
       .option('-d, --description <description>', 'Project description')
       .option('-t, --template <template>', 'Project template')
       .option('--private', 'Make project private')
+      .option('--owner <owner>', 'Owning team or person')
+      .option('--cost-center <code>', 'Cost center for chargeback')
+      .option('--environment <env>', 'Deployment environment')
+      .option('--compliance-tier <tier>', 'Compliance tier')
       .action(async (name, options) => {
         try {
           await authManager.requireAuth();
@@ -137,6 +306,12 @@ program
             description: options.description || '',
             template: options.template || 'default',
             private: options.private || false,
+            metadata: {
+              owner: options.owner,
+              cost_center: options.costCenter,
+              environment: options.environment,
+              compliance_tier: options.complianceTier,
+            },
           };
 
           const project = await projectManager.createProject(projectConfig);
@@ -158,12 +333,20 @@ program
       .description('List projects')
       .option('-a, --all', 'Show all projects (including archived)')
       .option('-f, --format <format>', 'Output format (table, json)', 'table')
+      .option('--owner <owner>', 'Filter by owner')
+      .option('--cost-center <code>', 'Filter by cost center')
+      .option('--environment <env>', 'Filter by environment')
+      .option('--compliance-tier <tier>', 'Filter by compliance tier')
       .action(async (options) => {
         try {
           await authManager.requireAuth();
 
           const projects = await projectManager.listProjects({
             includeArchived: options.all,
+            owner: options.owner,
+            cost_center: options.costCenter,
+            environment: options.environment,
+            compliance_tier: options.complianceTier,
           });
 
           if (options.format === 'json') {
@@ -174,6 +357,8 @@ program
                 ID: p.id,
                 Name: p.name,
                 Status: p.status,
+                Owner: p.metadata?.owner || '',
+                'Cost Center': p.metadata?.cost_center || '',
                 Created: new Date(p.createdAt).toLocaleDateString(),
                 'Last Modified': new Date(p.updatedAt).toLocaleDateString(),
               }))
@@ -187,6 +372,53 @@ program
         }
       })
   )
+  .addCommand(
+    program
+      .createCommand('clone')
+      .description('Clone a project')
+      .argument('<project-id>', 'Project ID or name to clone')
+      .argument('<name>', 'Name for the clone')
+      .option('--no-files', 'Start from the template instead of copying files')
+      .option('--history', 'Copy the git history too')
+      .option('--no-tags', 'Leave out tags')
+      .option('--no-metadata', 'Leave out metadata and ownership fields')
+      .action(async (projectId, name, options) => {
+        try {
+          await authManager.requireAuth();
+          await projectManager.initialize();
+
+          projectManager.on('project:clone-progress', (job) => {
+            console.log(
+              chalk.dim(`Copied ${job.copiedFiles}/${job.totalFiles} files`)
+            );
+          });
+          const finished = new Promise((resolve) => {
+            projectManager.once('project:clone-completed', resolve);
+            projectManager.once('project:clone-failed', resolve);
+          });
+
+          await projectManager.cloneProject(projectId, {
+            name,
+            includeFiles: options.files,
+            includeHistory: options.history,
+            includeTags: options.tags,
+            includeMetadata: options.metadata,
+          });
+          const job = await finished;
+          if (job.status === 'failed') {
+            throw new Error(job.error);
+          }
+
+          const project = await projectManager.getProject(job.projectId);
+          console.log(chalk.green('✓ Project cloned successfully'));
+          console.log(chalk.dim(`ID: ${project.id}`));
+          console.log(chalk.dim(`Path: ${project.path}`));
+        } catch (error) {
+          console.error(chalk.red('✖ Project clone failed:'), error.message);
+          process.exit(1);
+        }
+      })
+  )
   .addCommand(
     program
       .createCommand('start')
@@ -381,17 +613,25 @@ program
     program
       .createCommand('start')
       .description('Start API server')
-      .option('-p, --port <port>', 'Port number', '8080')
-      .option('-h, --host <host>', 'Host address', 'localhost')
+      .option('-p, --port <port>', 'Port number (default: server.port)')
+      .option('-h, --host <host>', 'Host address (default: server.host)')
       .option('-d, --daemon', 'Run as daemon')
       .action(async (options) => {
         try {
           await authManager.requireAuth();
 
           const serverConfig = {
-            port: parseInt(options.port),
-            host: options.host,
+            port: parseInt(options.port || configManager.get('server.port')),
+            host: options.host || configManager.get('server.host'),
             daemon: options.daemon,
+            // The server process loads the configuration itself; pass the
+            // command-line overrides on as environment variables
+            env: Object.fromEntries(
+              Object.entries(program.opts().set).map(([key, value]) => [
+                configManager.toEnvName(key),
+                String(value),
+              ])
+            ),
           };
 
           const server = await apiClient.startServer(serverConfig);
@@ -413,6 +653,35 @@ program
         }
       })
   )
+  .addCommand(
+    program
+      .createCommand('version')
+      .description('Check CLI compatibility with a running API server')
+      .option('--url <url>', 'API server URL', 'http://localhost:8080')
+      .action(async (options) => {
+        try {
+          const advice = await apiClient.checkCompatibility(
+            options.url,
+            'cli',
+            VERSION
+          );
+
+          console.log(`Server version: ${advice.serverVersion}`);
+          console.log(`CLI version: ${VERSION}`);
+          console.log(
+            `Minimum CLI version: ${advice.minimumClientVersions.cli}`
+          );
+          displayCompatibilityWarnings(advice);
+
+          if (advice.client?.compatibility === 'unsupported') {
+            process.exit(1);
+          }
+        } catch (error) {
+          console.error(chalk.red('✖ Version check failed:'), error.message);
+          process.exit(1);
+        }
+      })
+  )
   .addCommand(
     program
       .createCommand('stop')
@@ -427,7 +696,358 @@ program
       })
   );
 
+// Desktop notification relay
+program
+  .command('notify')
+  .description('Relay server notifications to desktop notifications')
+  .option('-l, --listen', 'Listen for notifications until interrupted')
+  .option('--url <url>', 'API server URL')
+  .option('--token <token>', 'API token (defaults to KASKMAN_TOKEN)')
+  .option(
+    '--types <types>',
+    'Comma-separated event types',
+    DEFAULT_EVENT_TYPES.join(',')
+  )
+  .option('--quiet-hours <range>', 'Suppress notifications, e.g. 22:00-07:00')
+  .action(async (options) => {
+    if (!options.listen) {
+      console.log(chalk.yellow('⚠ Nothing to do; pass --listen to start'));
+      return;
+    }
+
+    try {
+      const relay = new NotificationRelay({
+        ...(options.url && { url: options.url }),
+        ...(options.token && { token: options.token }),
+        eventTypes: options.types.split(',').map((type) => type.trim()),
+        quietHours: options.quietHours,
+      });
+
+      relay.on('connected', () => {
+        console.log(chalk.green('✓ Listening for notifications'));
+        console.log(
+          chalk.dim(`Events: ${relay.config.eventTypes.join(', ')}`)
+        );
+      });
+      relay.on('notification', (notification) => {
+        const note = notification.suppressed ? ' (quiet hours)' : '';
+        console.log(
+          chalk.dim(`${notification.type}: ${notification.title}`) +
+            chalk.yellow(note)
+        );
+      });
+      relay.on('disconnected', (reason) => {
+        console.log(
+          chalk.yellow(`⚠ Disconnected (${reason}); reconnecting...`)
+        );
+      });
+      relay.on('error', (error) => {
+        console.error(chalk.red('✖ Notification relay:'), error.message);
+      });
+
+      await warnIfIncompatible(relay.config.url);
+      relay.start();
+
+      process.on('SIGINT', () => {
+        console.log(chalk.yellow('\n⚠ Stopping notification relay...'));
+        relay.stop();
+        process.exit(0);
+      });
+    } catch (error) {
+      console.error(
+        chalk.red('✖ Failed to start notification relay:'),
+        error.message
+      );
+      process.exit(1);
+    }
+  });
+
+// Backup commands; these work on the local data directory, so run them on
+// the host that holds it
+program
+  .command('backup')
+  .description('Create, list and restore backup archives')
+  .addCommand(
+    program
+      .createCommand('create')
+      .description('Create a backup archive')
+      .option('-n, --note <note>', 'Note stored in the backup manifest')
+      .option('-t, --target <target>', 'Directory or s3://bucket/prefix')
+      .action(async (options) => {
+        try {
+          const backupManager = new BackupManager({ target: options.target });
+          const backup = await backupManager.createBackup({
+            note: options.note,
+          });
+
+          console.log(chalk.green(`✓ Backup created: ${backup.name}`));
+          console.log(chalk.dim(`Location: ${backupManager.store.describe()}`));
+          console.log(
+            chalk.dim(`Files: ${backup.files.length}, ${backup.size} bytes`)
+          );
+        } catch (error) {
+          console.error(chalk.red('✖ Failed to create backup:'), error.message);
+          process.exit(1);
+        }
+      })
+  )
+  .addCommand(
+    program
+      .createCommand('list')
+      .description('List backup archives, newest first')
+      .option('-t, --target <target>', 'Directory or s3://bucket/prefix')
+      .action(async (options) => {
+        try {
+          const backupManager = new BackupManager({ target: options.target });
+          const backups = await backupManager.listBackups();
+
+          if (backups.length === 0) {
+            console.log(chalk.yellow('No backups found'));
+            return;
+          }
+
+          for (const backup of backups) {
+            const date = new Date(backup.modifiedAt).toLocaleString();
+            console.log(`${backup.name}  ${chalk.dim(`${backup.size} bytes`)}`);
+            console.log(chalk.dim(`  ${date}`));
+          }
+        } catch (error) {
+          console.error(chalk.red('✖ Failed to list backups:'), error.message);
+          process.exit(1);
+        }
+      })
+  )
+  .addCommand(
+    program
+      .createCommand('restore')
+      .description('Restore a backup archive')
+      .argument('<name>', 'Backup archive name')
+      .option('-t, --target <target>', 'Directory or s3://bucket/prefix')
+      .option('--dry-run', 'Show what would change without writing anything')
+      .option('-y, --yes', 'Skip the confirmation prompt')
+      .action(async (name, options) => {
+        try {
+          const backupManager = new BackupManager({ target: options.target });
+          const preview = await backupManager.restoreBackup(name, {
+            dryRun: true,
+          });
+          displayRestorePlan(preview);
+
+          if (options.dryRun || preview.changes === 0) {
+            return;
+          }
+
+          if (!options.yes) {
+            const { confirm } = await inquirer.prompt([
+              {
+                type: 'confirm',
+                name: 'confirm',
+                message: `Restore ${preview.changes} files from ${name}?`,
+                default: false,
+              },
+            ]);
+            if (!confirm) {
+              console.log(chalk.yellow('⚠ Restore cancelled'));
+              return;
+            }
+          }
+
+          const result = await backupManager.restoreBackup(name);
+          console.log(chalk.green(`✓ Restored ${result.changes} files`));
+          console.log(chalk.dim(`Safety backup: ${result.safetyBackup}`));
+          console.log(
+            chalk.yellow('⚠ Restart the server to load the restored data')
+          );
+        } catch (error) {
+          console.error(
+            chalk.red('✖ Failed to restore backup:'),
+            error.message
+          );
+          process.exit(1);
+        }
+      })
+  );
+
+// Onboarding checklist, through the API so progress matches the dashboard
+program
+  .command('onboarding')
+  .description('Show and update your onboarding checklist')
+  .addCommand(
+    program
+      .createCommand('status')
+      .description('Show checklist progress and your dashboard views')
+      .option('--url <url>', 'API server URL (defaults to KASKMAN_URL)')
+      .option('--token <token>', 'API token (defaults to KASKMAN_TOKEN)')
+      .action(async (options) => {
+        try {
+          const { checklist, views, customized } = await requestServer(
+            options,
+            '/api/onboarding'
+          );
+          displayChecklist(checklist);
+
+          const source = customized ? 'saved' : 'role defaults';
+          console.log(chalk.bold(`\nDashboard views (${source}):`));
+          for (const view of views) {
+            console.log(`  ${view.name} ${chalk.dim(`[${view.resource}]`)}`);
+          }
+        } catch (error) {
+          console.error(
+            chalk.red('✖ Failed to load onboarding:'),
+            error.message
+          );
+          process.exit(1);
+        }
+      })
+  )
+  .addCommand(
+    program
+      .createCommand('complete')
+      .description('Mark a checklist item as done')
+      .argument('<item>', 'Checklist item id')
+      .option('--url <url>', 'API server URL (defaults to KASKMAN_URL)')
+      .option('--token <token>', 'API token (defaults to KASKMAN_TOKEN)')
+      .action(async (item, options) => {
+        try {
+          displayChecklist(
+            await requestServer(
+              options,
+              `/api/onboarding/items/${encodeURIComponent(item)}/complete`,
+              { method: 'POST' }
+            )
+          );
+        } catch (error) {
+          console.error(chalk.red('✖ Failed to complete item:'), error.message);
+          process.exit(1);
+        }
+      })
+  )
+  .addCommand(
+    program
+      .createCommand('dismiss')
+      .description('Hide a checklist item')
+      .argument('<item>', 'Checklist item id')
+      .option('--undo', 'Bring a dismissed item back')
+      .option('--url <url>', 'API server URL (defaults to KASKMAN_URL)')
+      .option('--token <token>', 'API token (defaults to KASKMAN_TOKEN)')
+      .action(async (item, options) => {
+        try {
+          displayChecklist(
+            await requestServer(
+              options,
+              `/api/onboarding/items/${encodeURIComponent(item)}/dismiss`,
+              { method: options.undo ? 'DELETE' : 'POST' }
+            )
+          );
+        } catch (error) {
+          console.error(chalk.red('✖ Failed to dismiss item:'), error.message);
+          process.exit(1);
+        }
+      })
+  );
+
 // Helper functions
+
+// --set server.port=9090 --set logging.level=debug
+function collectOverride(value, overrides) {
+  const separator = value.indexOf('=');
+  if (separator < 1) {
+    throw new Error(`Expected key=value for --set, got "${value}"`);
+  }
+
+  return {
+    ...overrides,
+    [value.slice(0, separator)]: value.slice(separator + 1),
+  };
+}
+
+// MFA commands work on the local user database and confirm the password
+// first, so they also work for accounts that must enroll before login
+async function signInLocally(options) {
+  await authManager.loadUsers();
+
+  const answers = await inquirer.prompt([
+    {
+      type: 'input',
+      name: 'username',
+      message: 'Username:',
+      when: !options.username,
+      validate: (input) => input.length > 0 || 'Username is required',
+    },
+    {
+      type: 'password',
+      name: 'password',
+      message: 'Password:',
+      mask: '*',
+      validate: (input) => input.length > 0 || 'Password is required',
+    },
+  ]);
+
+  return authManager.verifyCredentials(
+    options.username || answers.username,
+    answers.password
+  );
+}
+
+async function promptMfaCode(message = 'MFA code (or backup code):') {
+  const { code } = await inquirer.prompt([
+    {
+      type: 'input',
+      name: 'code',
+      message,
+      validate: (input) => input.trim().length > 0 || 'Code is required',
+    },
+  ]);
+  return code.trim();
+}
+
+function displayBackupCodes(codes) {
+  console.log(chalk.bold('\nBackup codes (each works once; keep them safe):'));
+  for (const code of codes) {
+    console.log(`  ${code}`);
+  }
+  console.log(chalk.dim('They will not be shown again.'));
+}
+
+// Authenticated JSON request to a running server
+async function requestServer(options, endpoint, init = {}) {
+  const url = options.url || process.env.KASKMAN_URL || 'http://localhost:8080';
+  const token = options.token || process.env.KASKMAN_TOKEN;
+  if (!token) {
+    throw new Error('An API token is required (--token or KASKMAN_TOKEN)');
+  }
+
+  const response = await fetch(`${url}${endpoint}`, {
+    ...init,
+    headers: { Authorization: `Bearer ${token}` },
+    signal: AbortSignal.timeout(10000),
+  });
+  const body = await response.json().catch(() => ({}));
+  if (!response.ok) {
+    throw new Error(body.error || `Request failed: ${response.status}`);
+  }
+  return body;
+}
+
+function displayChecklist(checklist) {
+  const marks = {
+    completed: chalk.green('✓'),
+    dismissed: chalk.dim('–'),
+    pending: chalk.yellow('○'),
+  };
+
+  console.log(
+    chalk.bold(
+      `Getting started: ${checklist.completed}/${checklist.total} done`
+    )
+  );
+  for (const item of checklist.items) {
+    const title =
+      item.status === 'dismissed' ? chalk.dim(item.title) : item.title;
+    console.log(`  ${marks[item.status]} ${title}`, chalk.dim(`(${item.id})`));
+  }
+}
+
 function displayProjectStatus(status) {
   console.log(chalk.bold(`Project Status: ${status.project.name}`));
   console.log('═'.repeat(50));
@@ -461,6 +1081,63 @@ function displaySystemStatus(status) {
   console.log(`Load Average: ${status.loadAverage.join(', ')}`);
 }
 
+function displayRestorePlan(summary) {
+  const colors = {
+    create: chalk.green,
+    overwrite: chalk.yellow,
+    unchanged: chalk.dim,
+  };
+
+  console.log(chalk.bold(`Restore plan: ${summary.backup}`));
+  console.log(chalk.dim(`Created: ${summary.createdAt}`));
+  console.log('═'.repeat(50));
+  for (const item of summary.plan) {
+    console.log(colors[item.action](`${item.action.padEnd(10)} ${item.path}`));
+  }
+  console.log('═'.repeat(50));
+  console.log(
+    `${summary.changes} of ${summary.plan.length} files would change`
+  );
+}
+
+function displayAnnouncements(announcements) {
+  const colors = {
+    critical: chalk.red,
+    warning: chalk.yellow,
+    info: chalk.blue,
+  };
+
+  for (const announcement of announcements) {
+    const color = colors[announcement.severity] || chalk.dim;
+    console.log(color(`\n📢 ${announcement.title}`));
+    if (announcement.message) {
+      console.log(chalk.dim(`  ${announcement.message}`));
+    }
+  }
+}
+
+function displayCompatibilityWarnings(advice) {
+  if (advice.warnings.length === 0) {
+    console.log(chalk.green('✓ CLI is compatible with the server'));
+    return;
+  }
+
+  for (const warning of advice.warnings) {
+    console.log(chalk.yellow(`⚠ ${warning}`));
+  }
+}
+
+async function warnIfIncompatible(url) {
+  try {
+    const advice = await apiClient.checkCompatibility(url, 'cli', VERSION);
+    for (const warning of advice.warnings) {
+      console.log(chalk.yellow(`⚠ ${warning}`));
+    }
+  } catch (error) {
+    // Best effort; an unreachable server is reported by the command itself
+  }
+}
+
 function getStatusColor(status) {
   switch (status.toLowerCase()) {
     case 'running':
@@ -480,9 +1157,4 @@ function getStatusColor(status) {
 }
 
 // Parse command line arguments
-program.parse();
-
-// Show help if no command provided
-if (!process.argv.slice(2).length) {
-  program.outputHelp();
-}
+program.parseAsync();
@@ -5,17 +5,21 @@
 
 import { EventEmitter } from 'events';
 import { Logger } from './logger.js';
+import { DATA_CLASSES } from './privacy-guard.js';
+import { escapeHTML } from '../api/project-widgets.js';
 
 class AIOrchestrator extends EventEmitter {
   constructor(config = {}) {
     super();
     this.config = {
+      provider: config.provider || 'local', // checked against privacy modes
       ...config,
     };
 
     this.logger = new Logger('AIOrchestrator');
     this.projectManager = null;
     this.statusMonitor = null;
+    this.privacyGuard = null;
   }
 
   async initialize() {
@@ -27,9 +31,38 @@ class AIOrchestrator extends EventEmitter {
     }
   }
 
+  // Without a privacy guard attached, every data class may be used
+  canUse(dataClass, projectId) {
+    return (
+      !this.privacyGuard ||
+      this.privacyGuard.authorize('llm', dataClass, {
+        projectId,
+        provider: this.config.provider,
+      })
+    );
+  }
+
+  // Result for a request whose metrics the privacy mode kept from the model
+  withheld(fields) {
+    this.logger.info(
+      `Metrics for ${fields.projectId} withheld by the privacy mode`
+    );
+    return {
+      ...fields,
+      withheld: DATA_CLASSES.SYSTEM_METRICS,
+      recommendations: [],
+      timestamp: new Date().toISOString(),
+    };
+  }
+
   async orchestrateTask(taskConfig) {
     try {
-      const { task, strategy, agents } = taskConfig;
+      const { task, strategy, agents, projectId } = taskConfig;
+
+      // Task text is user content and may be withheld from the model
+      const prompt = this.canUse(DATA_CLASSES.USER_CONTENT, projectId)
+        ? task
+        : '[redacted]';
 
       // Mock AI orchestration
       const result = {
@@ -40,7 +73,7 @@ class AIOrchestrator extends EventEmitter {
         status: 'completed',
         result: {
           success: true,
-          analysis: `AI analysis of task: ${task}`,
+          analysis: `AI analysis of task: ${prompt}`,
           recommendations: [
             'Optimize resource allocation',
             'Implement caching strategy',
@@ -71,6 +104,12 @@ class AIOrchestrator extends EventEmitter {
   async analyzeProjectPerformance(analysisConfig) {
     try {
       const { projectId, analysisType, timeRange } = analysisConfig;
+      if (!this.canUse(DATA_CLASSES.SYSTEM_METRICS, projectId)) {
+        return this.withheld({
+          projectId,
+          analysisType: analysisType || 'performance',
+        });
+      }
 
       // Mock performance analysis
       const analysis = {
@@ -146,6 +185,18 @@ class AIOrchestrator extends EventEmitter {
         ],
       };
 
+      // A deleted project still gets a report, just without ownership
+      if (this.projectManager) {
+        try {
+          const project = await this.projectManager.getProject(projectId);
+          report.ownership = this.projectManager.getOwnership(project);
+        } catch (error) {
+          this.logger.warn(`No ownership for report on ${projectId}`, {
+            error: error.message,
+          });
+        }
+      }
+
       if (format === 'markdown') {
         return this.formatReportAsMarkdown(report);
       } else if (format === 'html') {
@@ -160,9 +211,18 @@ class AIOrchestrator extends EventEmitter {
   }
 
   formatReportAsMarkdown(report) {
+    const ownership = report.ownership
+      ? `## Ownership
+${Object.entries(report.ownership)
+  .map(([field, value]) => `- ${field}: ${value || 'unset'}`)
+  .join('\n')}
+
+`
+      : '';
+
     return `# Project Report: ${report.projectId}
 
-## Summary
+${ownership}## Summary
 - Status: ${report.summary.status}
 - Uptime: ${report.summary.uptime}
 - Performance: ${report.summary.performance}
@@ -184,11 +244,24 @@ ${report.recommendations.map((rec) => `- ${rec}`).join('\n')}
   }
 
   formatReportAsHTML(report) {
+    const ownership = report.ownership
+      ? `<h2>Ownership</h2>
+<ul>
+${Object.entries(report.ownership)
+  .map(
+    ([field, value]) =>
+      `<li>${escapeHTML(field)}: ${escapeHTML(value || 'unset')}</li>`
+  )
+  .join('\n')}
+</ul>
+`
+      : '';
+
     return `<!DOCTYPE html>
-<html><head><title>Project Report: ${report.projectId}</title></head>
+<html><head><title>Project Report: ${escapeHTML(report.projectId)}</title></head>
 <body>
-<h1>Project Report: ${report.projectId}</h1>
-<h2>Summary</h2>
+<h1>Project Report: ${escapeHTML(report.projectId)}</h1>
+${ownership}<h2>Summary</h2>
 <ul>
 <li>Status: ${report.summary.status}</li>
 <li>Uptime: ${report.summary.uptime}</li>
@@ -209,6 +282,12 @@ ${report.recommendations.map((rec) => `- ${rec}`).join('\n')}
   async optimizeProjectResources(optimizationConfig) {
     try {
       const { projectId, optimizationType, aggressive } = optimizationConfig;
+      if (!this.canUse(DATA_CLASSES.SYSTEM_METRICS, projectId)) {
+        return this.withheld({
+          projectId,
+          optimizationType: optimizationType || 'auto',
+        });
+      }
 
       // Mock resource optimization
       const optimization = {
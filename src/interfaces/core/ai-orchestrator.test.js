@@ -0,0 +1,100 @@
+/**
+ * Tests for AI Orchestrator
+ */
+
+import { AIOrchestrator } from './ai-orchestrator.js';
+import { PrivacyGuard } from './privacy-guard.js';
+import { promises as fs } from 'fs';
+import os from 'os';
+import path from 'path';
+
+describe('AIOrchestrator', () => {
+  let orchestrator;
+  let projects;
+
+  beforeEach(() => {
+    projects = new Map();
+    orchestrator = new AIOrchestrator();
+    // Stands in for ProjectManager's lookup and ownership helpers
+    orchestrator.projectManager = {
+      getProject: async (id) => {
+        if (!projects.has(id)) throw new Error(`Project not found: ${id}`);
+        return projects.get(id);
+      },
+      getOwnership: (project) => ({ owner: project.metadata.owner || null }),
+    };
+  });
+
+  describe('generateProjectReport', () => {
+    it('should include ownership for known projects', async () => {
+      projects.set('p1', { id: 'p1', metadata: { owner: 'platform' } });
+
+      const report = await orchestrator.generateProjectReport({
+        projectId: 'p1',
+      });
+
+      expect(report.ownership).toEqual({ owner: 'platform' });
+    });
+
+    it('should still report on a deleted project', async () => {
+      const report = await orchestrator.generateProjectReport({
+        projectId: 'gone',
+      });
+
+      expect(report.projectId).toBe('gone');
+      expect(report.ownership).toBeUndefined();
+    });
+
+    it('should escape ownership values in HTML', async () => {
+      projects.set('p1', {
+        id: 'p1',
+        metadata: { owner: '<script>alert(1)</script>' },
+      });
+
+      const html = await orchestrator.generateProjectReport({
+        projectId: 'p1',
+        format: 'html',
+      });
+
+      expect(html).toContain('&lt;script&gt;alert(1)&lt;/script&gt;');
+      expect(html).not.toContain('<script>');
+    });
+  });
+
+  describe('privacy', () => {
+    let dir;
+
+    beforeEach(async () => {
+      dir = await fs.mkdtemp(path.join(os.tmpdir(), 'orchestrator-'));
+      orchestrator.privacyGuard = new PrivacyGuard({
+        stateFile: path.join(dir, 'privacy.json'),
+      });
+    });
+
+    afterEach(async () => {
+      await fs.rm(dir, { recursive: true, force: true });
+    });
+
+    it('should redact task text under strict mode', async () => {
+      orchestrator.privacyGuard.orgMode = 'strict';
+
+      const result = await orchestrator.orchestrateTask({
+        task: 'Summarize the customer contract',
+      });
+
+      expect(result.result.analysis).toBe('AI analysis of task: [redacted]');
+    });
+
+    it('should withhold analysis the guard refuses', async () => {
+      orchestrator.privacyGuard.authorize = () => false;
+
+      const analysis = await orchestrator.analyzeProjectPerformance({
+        projectId: 'p1',
+      });
+
+      expect(analysis.withheld).toBe('system-metrics');
+      expect(analysis.metrics).toBeUndefined();
+      expect(analysis.recommendations).toEqual([]);
+    });
+  });
+});
@@ -0,0 +1,330 @@
+/**
+ * Alert Rule Engine
+ * Evaluates threshold rules against collected metrics and tracks each rule
+ * through pending, firing, and resolved states, honoring silences
+ */
+
+import { EventEmitter } from 'events';
+import { promises as fs } from 'fs';
+import path from 'path';
+import crypto from 'crypto';
+import { Logger } from './logger.js';
+
+const COMPARATORS = {
+  '>': (value, threshold) => value > threshold,
+  '>=': (value, threshold) => value >= threshold,
+  '<': (value, threshold) => value < threshold,
+  '<=': (value, threshold) => value <= threshold,
+  '==': (value, threshold) => value === threshold,
+  '!=': (value, threshold) => value !== threshold,
+};
+
+const ALERT_SEVERITIES = ['info', 'warning', 'critical'];
+
+class AlertRuleEngine extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      rulesFile: config.rulesFile || './data/alert-rules.json',
+      ...config,
+    };
+
+    this.logger = new Logger('AlertRuleEngine');
+    this.rules = new Map();
+    this.silences = new Map();
+
+    // Runtime state per rule id; not persisted
+    this.states = new Map();
+  }
+
+  async initialize() {
+    try {
+      await this.loadRules();
+      this.logger.info('AlertRuleEngine initialized successfully');
+    } catch (error) {
+      this.logger.error('Failed to initialize AlertRuleEngine:', error);
+      throw error;
+    }
+  }
+
+  async loadRules() {
+    try {
+      const data = JSON.parse(await fs.readFile(this.config.rulesFile, 'utf8'));
+
+      for (const rule of data.rules || []) {
+        this.rules.set(rule.id, rule);
+      }
+      for (const silence of data.silences || []) {
+        this.silences.set(silence.id, silence);
+      }
+
+      this.logger.info(
+        `Loaded ${this.rules.size} alert rules, ${this.silences.size} silences`
+      );
+    } catch (error) {
+      if (error.code !== 'ENOENT') {
+        this.logger.error('Failed to load alert rules:', error);
+        throw error;
+      }
+    }
+  }
+
+  async saveRules() {
+    try {
+      await fs.mkdir(path.dirname(this.config.rulesFile), { recursive: true });
+      await fs.writeFile(
+        this.config.rulesFile,
+        JSON.stringify(
+          {
+            rules: Array.from(this.rules.values()),
+            silences: Array.from(this.silences.values()),
+          },
+          null,
+          2
+        )
+      );
+    } catch (error) {
+      this.logger.error('Failed to save alert rules:', error);
+      throw error;
+    }
+  }
+
+  validateRule(rule) {
+    if (!rule.name || rule.name.trim() === '') {
+      throw new Error('Alert rule name is required');
+    }
+    if (!rule.metric) {
+      throw new Error('Alert rule metric is required');
+    }
+    if (!COMPARATORS[rule.comparator]) {
+      throw new Error(`Invalid comparator: ${rule.comparator}`);
+    }
+    if (typeof rule.threshold !== 'number') {
+      throw new Error('Alert rule threshold must be a number');
+    }
+    if (!(rule.duration >= 0)) {
+      throw new Error('Alert rule duration must be a non-negative number');
+    }
+    if (!ALERT_SEVERITIES.includes(rule.severity)) {
+      throw new Error(`Invalid severity: ${rule.severity}`);
+    }
+  }
+
+  async createRule(data, createdBy) {
+    const rule = {
+      id: crypto.randomUUID(),
+      name: data.name,
+      metric: data.metric,
+      comparator: data.comparator || '>',
+      threshold: data.threshold,
+      duration: data.duration ?? 0, // ms the condition must hold
+      severity: data.severity || 'warning',
+      enabled: data.enabled !== false,
+      createdBy: createdBy || null,
+      createdAt: new Date().toISOString(),
+      updatedAt: new Date().toISOString(),
+    };
+
+    this.validateRule(rule);
+
+    this.rules.set(rule.id, rule);
+    await this.saveRules();
+
+    this.emit('rule:created', rule);
+    return rule;
+  }
+
+  async updateRule(ruleId, updates) {
+    const rule = this.getRule(ruleId);
+    const { id: _id, createdAt: _createdAt, ...allowed } = updates;
+
+    const updated = {
+      ...rule,
+      ...allowed,
+      updatedAt: new Date().toISOString(),
+    };
+
+    this.validateRule(updated);
+
+    this.rules.set(ruleId, updated);
+    this.states.delete(ruleId);
+    await this.saveRules();
+
+    this.emit('rule:updated', updated);
+    return updated;
+  }
+
+  async deleteRule(ruleId) {
+    const rule = this.getRule(ruleId);
+
+    this.rules.delete(ruleId);
+    this.states.delete(ruleId);
+    await this.saveRules();
+
+    this.emit('rule:deleted', rule);
+    return rule;
+  }
+
+  getRule(ruleId) {
+    const rule = this.rules.get(ruleId);
+    if (!rule) {
+      throw new Error(`Alert rule not found: ${ruleId}`);
+    }
+    return rule;
+  }
+
+  listRules() {
+    return Array.from(this.rules.values()).map((rule) => ({
+      ...rule,
+      state: this.states.get(rule.id)?.state || 'inactive',
+    }));
+  }
+
+  // Silences match a rule id or every rule on a metric until they expire
+  async createSilence(data, createdBy) {
+    if (!data.ruleId && !data.metric) {
+      throw new Error('Silence needs a ruleId or a metric');
+    }
+
+    const expiresAt = new Date(data.expiresAt);
+    if (isNaN(expiresAt) || expiresAt <= new Date()) {
+      throw new Error('Silence expiresAt must be a future timestamp');
+    }
+
+    const silence = {
+      id: crypto.randomUUID(),
+      ruleId: data.ruleId || null,
+      metric: data.metric || null,
+      reason: data.reason || '',
+      createdBy: createdBy || null,
+      createdAt: new Date().toISOString(),
+      expiresAt: expiresAt.toISOString(),
+    };
+
+    this.silences.set(silence.id, silence);
+    await this.saveRules();
+
+    this.emit('silence:created', silence);
+    return silence;
+  }
+
+  async deleteSilence(silenceId) {
+    const silence = this.silences.get(silenceId);
+    if (!silence) {
+      throw new Error(`Silence not found: ${silenceId}`);
+    }
+
+    this.silences.delete(silenceId);
+    await this.saveRules();
+
+    return silence;
+  }
+
+  listSilences() {
+    return Array.from(this.silences.values());
+  }
+
+  isSilenced(rule, now = new Date()) {
+    return Array.from(this.silences.values()).some(
+      (silence) =>
+        new Date(silence.expiresAt) > now &&
+        (silence.ruleId === rule.id || silence.metric === rule.metric)
+    );
+  }
+
+  async purgeExpiredSilences(now = new Date()) {
+    let removed = 0;
+    for (const [id, silence] of this.silences) {
+      if (new Date(silence.expiresAt) <= now) {
+        this.silences.delete(id);
+        removed++;
+      }
+    }
+    if (removed > 0) {
+      await this.saveRules();
+    }
+  }
+
+  // Advance every enabled rule against a flat { metric: number } snapshot
+  evaluate(metrics, now = Date.now()) {
+    const transitions = [];
+
+    for (const rule of this.rules.values()) {
+      if (!rule.enabled) continue;
+
+      const value = metrics[rule.metric];
+      if (typeof value !== 'number') continue;
+
+      const state = this.states.get(rule.id) || { state: 'inactive' };
+      const breached = COMPARATORS[rule.comparator](value, rule.threshold);
+
+      if (breached) {
+        if (state.state === 'inactive') {
+          state.state = 'pending';
+          state.activeSince = now;
+        }
+        if (
+          state.state === 'pending' &&
+          now - state.activeSince >= rule.duration
+        ) {
+          state.state = 'firing';
+          state.firedAt = now;
+          transitions.push(this.buildAlert(rule, 'firing', value, now));
+        }
+      } else if (state.state === 'firing') {
+        transitions.push(this.buildAlert(rule, 'resolved', value, now));
+        state.state = 'inactive';
+      } else {
+        state.state = 'inactive';
+      }
+
+      state.value = value;
+      this.states.set(rule.id, state);
+    }
+
+    for (const alert of transitions) {
+      this.emit(`alert:${alert.state}`, alert);
+    }
+
+    return transitions;
+  }
+
+  buildAlert(rule, state, value, now) {
+    return {
+      ruleId: rule.id,
+      name: rule.name,
+      metric: rule.metric,
+      state,
+      severity: rule.severity,
+      value,
+      threshold: rule.threshold,
+      comparator: rule.comparator,
+      silenced: this.isSilenced(rule, new Date(now)),
+      message:
+        state === 'firing'
+          ? `${rule.name}: ${rule.metric} ${rule.comparator} ${rule.threshold} (current ${value})`
+          : `${rule.name} resolved (current ${value})`,
+      timestamp: new Date(now).toISOString(),
+    };
+  }
+
+  getActiveAlerts() {
+    return Array.from(this.states.entries())
+      .filter(([, state]) => state.state !== 'inactive')
+      .map(([ruleId, state]) => {
+        const rule = this.rules.get(ruleId);
+        return {
+          ruleId,
+          name: rule.name,
+          metric: rule.metric,
+          severity: rule.severity,
+          state: state.state,
+          value: state.value,
+          since: new Date(state.activeSince).toISOString(),
+          silenced: this.isSilenced(rule),
+        };
+      });
+  }
+}
+
+export { AlertRuleEngine, COMPARATORS, ALERT_SEVERITIES };
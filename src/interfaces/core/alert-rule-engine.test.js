@@ -0,0 +1,153 @@
+/**
+ * Tests for Alert Rule Engine
+ */
+
+import { AlertRuleEngine } from './alert-rule-engine.js';
+import { jest } from '@jest/globals';
+import { promises as fs } from 'fs';
+import os from 'os';
+import path from 'path';
+
+describe('AlertRuleEngine', () => {
+  let dir;
+  let engine;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'alert-rules-'));
+    engine = new AlertRuleEngine({
+      rulesFile: path.join(dir, 'alert-rules.json'),
+    });
+  });
+
+  afterEach(async () => {
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  const createCpuRule = (overrides = {}) =>
+    engine.createRule(
+      { name: 'High CPU', metric: 'cpu', threshold: 90, ...overrides },
+      'user-1'
+    );
+
+  describe('createRule', () => {
+    it('should apply defaults and persist the rule', async () => {
+      const rule = await createCpuRule();
+
+      expect(rule).toMatchObject({
+        comparator: '>',
+        duration: 0,
+        severity: 'warning',
+        enabled: true,
+        createdBy: 'user-1',
+      });
+
+      const reloaded = new AlertRuleEngine({
+        rulesFile: engine.config.rulesFile,
+      });
+      await reloaded.loadRules();
+      expect(reloaded.getRule(rule.id).name).toBe('High CPU');
+    });
+
+    it('should reject invalid rules', async () => {
+      await expect(createCpuRule({ comparator: '=~' })).rejects.toThrow(
+        'Invalid comparator: =~'
+      );
+      await expect(createCpuRule({ threshold: '90' })).rejects.toThrow(
+        'Alert rule threshold must be a number'
+      );
+      await expect(createCpuRule({ duration: -1 })).rejects.toThrow(
+        'Alert rule duration must be a non-negative number'
+      );
+      await expect(createCpuRule({ severity: 'page' })).rejects.toThrow(
+        'Invalid severity: page'
+      );
+    });
+  });
+
+  describe('evaluate', () => {
+    it('should fire once the condition holds for the duration', async () => {
+      const rule = await createCpuRule({ duration: 1000 });
+      const firing = jest.fn();
+      engine.on('alert:firing', firing);
+
+      expect(engine.evaluate({ cpu: 95 }, 0)).toEqual([]);
+      expect(engine.listRules()[0].state).toBe('pending');
+
+      expect(engine.evaluate({ cpu: 95 }, 500)).toEqual([]);
+      const [alert] = engine.evaluate({ cpu: 96 }, 1000);
+
+      expect(alert).toMatchObject({
+        ruleId: rule.id,
+        state: 'firing',
+        value: 96,
+      });
+      expect(firing).toHaveBeenCalledTimes(1);
+      expect(engine.evaluate({ cpu: 97 }, 2000)).toEqual([]);
+    });
+
+    it('should reset a pending rule that recovers', async () => {
+      await createCpuRule({ duration: 1000 });
+
+      engine.evaluate({ cpu: 95 }, 0);
+      engine.evaluate({ cpu: 50 }, 500);
+
+      expect(engine.evaluate({ cpu: 95 }, 1000)).toEqual([]);
+    });
+
+    it('should resolve a firing rule when the metric recovers', async () => {
+      await createCpuRule();
+      const resolved = jest.fn();
+      engine.on('alert:resolved', resolved);
+
+      engine.evaluate({ cpu: 95 }, 0);
+      const [alert] = engine.evaluate({ cpu: 40 }, 1000);
+
+      expect(alert.state).toBe('resolved');
+      expect(resolved).toHaveBeenCalledTimes(1);
+      expect(engine.getActiveAlerts()).toEqual([]);
+    });
+
+    it('should skip disabled rules and missing metrics', async () => {
+      await createCpuRule({ enabled: false });
+      await engine.createRule({ name: 'Disk', metric: 'disk', threshold: 90 });
+
+      expect(engine.evaluate({ cpu: 99 }, 0)).toEqual([]);
+    });
+  });
+
+  describe('silences', () => {
+    it('should mark alerts matching a silence', async () => {
+      const rule = await createCpuRule();
+      await engine.createSilence({
+        metric: 'cpu',
+        expiresAt: new Date(Date.now() + 60000).toISOString(),
+      });
+
+      const [alert] = engine.evaluate({ cpu: 95 });
+
+      expect(alert.ruleId).toBe(rule.id);
+      expect(alert.silenced).toBe(true);
+    });
+
+    it('should reject silences that already expired', async () => {
+      await expect(
+        engine.createSilence({
+          ruleId: 'rule-1',
+          expiresAt: new Date(Date.now() - 1000).toISOString(),
+        })
+      ).rejects.toThrow('Silence expiresAt must be a future timestamp');
+    });
+
+    it('should purge expired silences', async () => {
+      await engine.createSilence({
+        ruleId: 'rule-1',
+        expiresAt: new Date(Date.now() + 1000).toISOString(),
+      });
+
+      await engine.purgeExpiredSilences(new Date(Date.now() + 2000));
+
+      expect(engine.listSilences()).toEqual([]);
+      expect(engine.isSilenced({ id: 'rule-1' })).toBe(false);
+    });
+  });
+});
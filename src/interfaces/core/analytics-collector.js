@@ -0,0 +1,297 @@
+/**
+ * Analytics Collector
+ * Records anonymized feature usage events for self-hosted product analytics.
+ * Events are appended to a JSON Lines file, one event per line.
+ */
+
+import { EventEmitter } from 'events';
+import { promises as fs } from 'fs';
+import path from 'path';
+import { Logger } from './logger.js';
+
+// Upper bounds (ms) for latency buckets; slower requests fall into 'slow'
+const LATENCY_BUCKETS = [50, 100, 250, 500, 1000, 2500];
+
+class AnalyticsCollector extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      analyticsFile: config.analyticsFile || './data/analytics.jsonl',
+      enabled:
+        config.enabled !== undefined
+          ? config.enabled
+          : process.env.ANALYTICS_ENABLED !== 'false',
+      sampleRate:
+        config.sampleRate !== undefined
+          ? config.sampleRate
+          : parseFloat(process.env.ANALYTICS_SAMPLE_RATE || '1'),
+      honorDoNotTrack: config.honorDoNotTrack !== false,
+      flushInterval: config.flushInterval || 30000, // 30 seconds
+      retentionPeriod: config.retentionPeriod || 90 * 24 * 60 * 60 * 1000, // 90 days
+      // Oldest events are dropped past this many
+      maxEvents: config.maxEvents || 100000,
+      ...config,
+    };
+
+    this.logger = new Logger('AnalyticsCollector');
+    this.events = [];
+    this.pending = []; // recorded but not yet appended to the file
+    this.fileEvents = 0; // lines in the file, including dropped events
+    this.unreadableLines = 0; // e.g. a write cut short by a crash
+  }
+
+  async initialize() {
+    try {
+      await this.loadEvents();
+      await this.cleanup();
+
+      this.flushTimer = setInterval(async () => {
+        await this.cleanup();
+        await this.flush();
+      }, this.config.flushInterval);
+      this.flushTimer.unref?.();
+
+      this.logger.info('AnalyticsCollector initialized successfully', {
+        enabled: this.config.enabled,
+        sampleRate: this.config.sampleRate,
+      });
+    } catch (error) {
+      this.logger.error('Failed to initialize AnalyticsCollector:', error);
+      throw error;
+    }
+  }
+
+  async loadEvents() {
+    try {
+      const eventsData = await fs.readFile(this.config.analyticsFile, 'utf8');
+      const lines = eventsData.split('\n').filter((line) => line.trim() !== '');
+
+      this.events = [];
+      this.unreadableLines = 0;
+      for (const line of lines) {
+        try {
+          this.events.push(JSON.parse(line));
+        } catch (error) {
+          this.unreadableLines++;
+        }
+      }
+      if (this.unreadableLines > 0) {
+        this.logger.warn(
+          `Skipped ${this.unreadableLines} unreadable analytics lines`
+        );
+      }
+
+      this.fileEvents = lines.length;
+      this.trimToLimit();
+      this.logger.info(`Loaded ${this.events.length} analytics events`);
+    } catch (error) {
+      if (error.code === 'ENOENT') {
+        this.events = [];
+      } else {
+        this.logger.error('Failed to load analytics events:', error);
+        throw error;
+      }
+    }
+  }
+
+  toLines(events) {
+    return events.map((event) => `${JSON.stringify(event)}\n`).join('');
+  }
+
+  // Rewrites the file with only the events still held in memory
+  async saveEvents() {
+    try {
+      const analyticsDir = path.dirname(this.config.analyticsFile);
+      await fs.mkdir(analyticsDir, { recursive: true });
+
+      const events = this.events.slice();
+      await fs.writeFile(this.config.analyticsFile, this.toLines(events));
+
+      // Events recorded during the write are still pending
+      const written = new Set(events);
+      this.pending = this.pending.filter((event) => !written.has(event));
+      this.fileEvents = events.length;
+      this.unreadableLines = 0;
+    } catch (error) {
+      this.logger.error('Failed to save analytics events:', error);
+      throw error;
+    }
+  }
+
+  async flush() {
+    if (this.pending.length === 0) return;
+
+    const pending = this.pending;
+    this.pending = [];
+    try {
+      const analyticsDir = path.dirname(this.config.analyticsFile);
+      await fs.mkdir(analyticsDir, { recursive: true });
+      await fs.appendFile(this.config.analyticsFile, this.toLines(pending));
+      this.fileEvents += pending.length;
+    } catch (error) {
+      this.logger.error('Failed to append analytics events:', error);
+      this.pending = pending.concat(this.pending);
+    }
+  }
+
+  // Drop the oldest events past maxEvents
+  trimToLimit() {
+    const excess = this.events.length - this.config.maxEvents;
+    if (excess > 0) {
+      this.events.splice(0, excess);
+    }
+    if (this.pending.length > this.config.maxEvents) {
+      this.pending.splice(0, this.pending.length - this.config.maxEvents);
+    }
+  }
+
+  isOptedOut(headers = {}) {
+    if (!this.config.enabled) return true;
+    if (!this.config.honorDoNotTrack) return false;
+
+    return headers.dnt === '1' || headers['sec-gpc'] === '1';
+  }
+
+  shouldSample() {
+    return Math.random() < this.config.sampleRate;
+  }
+
+  // Replace identifiers in paths so events cannot be tied to specific records
+  normalizeRoute(routePath) {
+    return routePath
+      .split('?')[0]
+      .replace(
+        /\/[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}/gi,
+        '/:id'
+      )
+      .replace(/\/\d+(?=\/|$)/g, '/:id');
+  }
+
+  latencyBucket(durationMs) {
+    const bound = LATENCY_BUCKETS.find((limit) => durationMs <= limit);
+    return bound ? `<=${bound}ms` : 'slow';
+  }
+
+  record(event) {
+    const entry = {
+      timestamp: new Date().toISOString(),
+      method: event.method,
+      route: this.normalizeRoute(event.route || ''),
+      feature: event.feature || null,
+      status: event.status,
+      latencyBucket: this.latencyBucket(event.durationMs || 0),
+    };
+
+    this.events.push(entry);
+    this.pending.push(entry);
+    this.trimToLimit();
+
+    this.emit('event:recorded', entry);
+    return entry;
+  }
+
+  middleware() {
+    return (req, res, next) => {
+      if (this.isOptedOut(req.headers) || !this.shouldSample()) {
+        return next();
+      }
+
+      const startTime = Date.now();
+      res.on('finish', () => {
+        this.record({
+          method: req.method,
+          route: req.baseUrl + (req.route?.path || req.path),
+          feature: req.get('X-Feature-Flag') || null,
+          status: res.statusCode,
+          durationMs: Date.now() - startTime,
+        });
+      });
+
+      next();
+    };
+  }
+
+  async getAggregates(timeRange = '24h') {
+    const ranges = {
+      '1h': 60 * 60 * 1000,
+      '24h': 24 * 60 * 60 * 1000,
+      '7d': 7 * 24 * 60 * 60 * 1000,
+      '30d': 30 * 24 * 60 * 60 * 1000,
+      '90d': 90 * 24 * 60 * 60 * 1000,
+    };
+
+    const startTime = Date.now() - (ranges[timeRange] || ranges['24h']);
+    const events = this.events.filter(
+      (event) => new Date(event.timestamp).getTime() >= startTime
+    );
+
+    const routes = {};
+    const features = {};
+
+    for (const event of events) {
+      const key = `${event.method} ${event.route}`;
+      if (!routes[key]) {
+        routes[key] = { count: 0, errors: 0, latency: {} };
+      }
+      routes[key].count++;
+      if (event.status >= 500) routes[key].errors++;
+      routes[key].latency[event.latencyBucket] =
+        (routes[key].latency[event.latencyBucket] || 0) + 1;
+
+      if (event.feature) {
+        features[event.feature] = (features[event.feature] || 0) + 1;
+      }
+    }
+
+    return {
+      timeRange,
+      sampleRate: this.config.sampleRate,
+      totalEvents: events.length,
+      routes,
+      features,
+    };
+  }
+
+  async cleanup() {
+    try {
+      const cutoffTime = Date.now() - this.config.retentionPeriod;
+
+      const initialCount = this.events.length;
+      this.events = this.events.filter(
+        (event) => new Date(event.timestamp).getTime() >= cutoffTime
+      );
+
+      const removedCount = initialCount - this.events.length;
+      if (removedCount > 0) {
+        this.logger.info(`Cleaned up ${removedCount} old analytics events`);
+      }
+
+      // Appends leave expired and dropped events in the file; compact it
+      // once they make up half of it. Unreadable lines are compacted away
+      // right after loading, before an append can land on a partial line.
+      if (
+        this.fileEvents > 2 * this.events.length ||
+        this.unreadableLines > 0
+      ) {
+        await this.saveEvents();
+      }
+    } catch (error) {
+      this.logger.error('Failed to cleanup analytics events:', error);
+    }
+  }
+
+  async stop() {
+    try {
+      if (this.flushTimer) {
+        clearInterval(this.flushTimer);
+      }
+      await this.flush();
+      this.logger.info('AnalyticsCollector stopped successfully');
+    } catch (error) {
+      this.logger.error('Error stopping AnalyticsCollector:', error);
+      throw error;
+    }
+  }
+}
+
+export { AnalyticsCollector, LATENCY_BUCKETS };
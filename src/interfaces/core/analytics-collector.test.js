@@ -0,0 +1,148 @@
+/**
+ * Tests for Analytics Collector
+ */
+
+import { AnalyticsCollector } from './analytics-collector.js';
+import { promises as fs } from 'fs';
+import os from 'os';
+import path from 'path';
+
+describe('AnalyticsCollector', () => {
+  let dir;
+  let analyticsFile;
+  let collector;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'analytics-'));
+    analyticsFile = path.join(dir, 'analytics.jsonl');
+    collector = new AnalyticsCollector({
+      analyticsFile,
+      enabled: true,
+      sampleRate: 1,
+    });
+  });
+
+  afterEach(async () => {
+    await collector.stop();
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  const readLines = async () =>
+    (await fs.readFile(analyticsFile, 'utf8')).split('\n').filter(Boolean);
+
+  describe('record', () => {
+    it('should strip identifiers and bucket latency', () => {
+      const event = collector.record({
+        method: 'GET',
+        route: '/api/projects/3f2b1c9e-1a2b-4c3d-8e9f-001122334455/logs/42?x=1',
+        status: 200,
+        durationMs: 120,
+      });
+
+      expect(event.route).toBe('/api/projects/:id/logs/:id');
+      expect(event.latencyBucket).toBe('<=250ms');
+      expect(collector.latencyBucket(5000)).toBe('slow');
+    });
+
+    it('should keep at most maxEvents in memory', () => {
+      collector.config.maxEvents = 3;
+
+      for (let i = 0; i < 5; i++) {
+        collector.record({ method: 'GET', route: `/r${i}`, status: 200 });
+      }
+
+      expect(collector.events.map((event) => event.route)).toEqual([
+        '/r2',
+        '/r3',
+        '/r4',
+      ]);
+      expect(collector.pending).toHaveLength(3);
+    });
+  });
+
+  describe('isOptedOut', () => {
+    it('should honor Do Not Track and Global Privacy Control', () => {
+      expect(collector.isOptedOut({ dnt: '1' })).toBe(true);
+      expect(collector.isOptedOut({ 'sec-gpc': '1' })).toBe(true);
+      expect(collector.isOptedOut({})).toBe(false);
+
+      collector.config.enabled = false;
+      expect(collector.isOptedOut({})).toBe(true);
+    });
+  });
+
+  describe('flush', () => {
+    it('should append only the pending events', async () => {
+      collector.record({ method: 'GET', route: '/a', status: 200 });
+      await collector.flush();
+      collector.record({ method: 'GET', route: '/b', status: 200 });
+      await collector.flush();
+
+      const lines = await readLines();
+      expect(lines.map((line) => JSON.parse(line).route)).toEqual(['/a', '/b']);
+      expect(collector.pending).toEqual([]);
+    });
+  });
+
+  describe('loadEvents', () => {
+    it('should skip a truncated last line and compact it away', async () => {
+      const event = {
+        timestamp: new Date().toISOString(),
+        method: 'GET',
+        route: '/a',
+        status: 200,
+      };
+      await fs.writeFile(
+        analyticsFile,
+        `${JSON.stringify(event)}\n{"timestamp":"20`
+      );
+
+      await collector.initialize();
+
+      expect(collector.events).toHaveLength(1);
+      expect(await readLines()).toEqual([JSON.stringify(event)]);
+
+      collector.record({ method: 'GET', route: '/b', status: 200 });
+      await collector.flush();
+      expect(await readLines()).toHaveLength(2);
+    });
+  });
+
+  describe('cleanup', () => {
+    it('should drop expired events and compact the file', async () => {
+      const old = new Date(Date.now() - 100 * 86400000).toISOString();
+      const lines = [old, old, new Date().toISOString()].map((timestamp) =>
+        JSON.stringify({ timestamp, method: 'GET', route: '/a', status: 200 })
+      );
+      await fs.writeFile(analyticsFile, `${lines.join('\n')}\n`);
+
+      await collector.loadEvents();
+      await collector.cleanup();
+
+      expect(collector.events).toHaveLength(1);
+      expect(await readLines()).toHaveLength(1);
+    });
+  });
+
+  describe('getAggregates', () => {
+    it('should count routes, errors and features', async () => {
+      collector.record({ method: 'GET', route: '/a', status: 200 });
+      collector.record({ method: 'GET', route: '/a', status: 503 });
+      collector.record({
+        method: 'POST',
+        route: '/b',
+        status: 201,
+        feature: 'beta',
+      });
+
+      const aggregates = await collector.getAggregates('1h');
+
+      expect(aggregates.totalEvents).toBe(3);
+      expect(aggregates.routes['GET /a']).toMatchObject({
+        count: 2,
+        errors: 1,
+      });
+      expect(aggregates.features).toEqual({ beta: 1 });
+    });
+  });
+});
@@ -0,0 +1,284 @@
+/**
+ * Announcement Manager
+ * Manages workspace-wide announcement banners with scheduling, audience
+ * targeting, and per-user dismissal
+ */
+
+import { EventEmitter } from 'events';
+import { promises as fs } from 'fs';
+import path from 'path';
+import crypto from 'crypto';
+import { Logger } from './logger.js';
+
+const ANNOUNCEMENT_SEVERITIES = ['info', 'warning', 'critical'];
+const AUDIENCE_TYPES = ['all', 'role', 'project'];
+// setTimeout overflows past ~24.8 days; longer waits are taken in steps
+const MAX_TIMER_DELAY = 2 ** 31 - 1;
+
+class AnnouncementManager extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      announcementsFile:
+        config.announcementsFile || './data/announcements.json',
+      ...config,
+    };
+
+    this.logger = new Logger('AnnouncementManager');
+    this.announcements = new Map();
+    this.startTimers = new Map(); // announcement id -> timeout
+  }
+
+  async initialize() {
+    try {
+      await this.loadAnnouncements();
+      this.logger.info('AnnouncementManager initialized successfully');
+    } catch (error) {
+      this.logger.error('Failed to initialize AnnouncementManager:', error);
+      throw error;
+    }
+  }
+
+  async loadAnnouncements() {
+    try {
+      const data = await fs.readFile(this.config.announcementsFile, 'utf8');
+
+      for (const announcement of JSON.parse(data)) {
+        this.announcements.set(announcement.id, announcement);
+        this.scheduleStart(announcement);
+      }
+
+      this.logger.info(`Loaded ${this.announcements.size} announcements`);
+    } catch (error) {
+      if (error.code !== 'ENOENT') {
+        this.logger.error('Failed to load announcements:', error);
+        throw error;
+      }
+    }
+  }
+
+  async saveAnnouncements() {
+    try {
+      await fs.mkdir(path.dirname(this.config.announcementsFile), {
+        recursive: true,
+      });
+      await fs.writeFile(
+        this.config.announcementsFile,
+        JSON.stringify(Array.from(this.announcements.values()), null, 2)
+      );
+    } catch (error) {
+      this.logger.error('Failed to save announcements:', error);
+      throw error;
+    }
+  }
+
+  validate(data) {
+    if (typeof data.title !== 'string' || data.title.trim() === '') {
+      throw new Error('Announcement title is required');
+    }
+
+    if (!ANNOUNCEMENT_SEVERITIES.includes(data.severity)) {
+      throw new Error(
+        `Invalid severity: ${data.severity} (expected ${ANNOUNCEMENT_SEVERITIES.join(', ')})`
+      );
+    }
+
+    if (
+      !data.audience ||
+      typeof data.audience !== 'object' ||
+      Array.isArray(data.audience)
+    ) {
+      throw new Error('Audience must be an object like { type, value }');
+    }
+
+    if (!AUDIENCE_TYPES.includes(data.audience.type)) {
+      throw new Error(`Invalid audience type: ${data.audience.type}`);
+    }
+
+    if (data.audience.type !== 'all' && !data.audience.value) {
+      throw new Error(`Audience ${data.audience.type} requires a value`);
+    }
+
+    if (
+      data.startsAt &&
+      data.endsAt &&
+      new Date(data.endsAt) <= new Date(data.startsAt)
+    ) {
+      throw new Error('Announcement must end after it starts');
+    }
+  }
+
+  async createAnnouncement(data, createdBy) {
+    const announcement = {
+      id: crypto.randomUUID(),
+      title: data.title,
+      message: data.message || '',
+      severity: data.severity || 'info',
+      audience: data.audience || { type: 'all' },
+      startsAt: data.startsAt || new Date().toISOString(),
+      endsAt: data.endsAt || null,
+      dismissible: data.dismissible !== false,
+      dismissedBy: [],
+      createdBy: createdBy || null,
+      createdAt: new Date().toISOString(),
+      updatedAt: new Date().toISOString(),
+    };
+
+    this.validate(announcement);
+
+    this.announcements.set(announcement.id, announcement);
+    await this.saveAnnouncements();
+    this.scheduleStart(announcement);
+
+    this.emit('announcement:created', announcement);
+    this.logger.info(`Announcement created: ${announcement.title}`);
+
+    return announcement;
+  }
+
+  async updateAnnouncement(announcementId, updates) {
+    const announcement = this.getAnnouncement(announcementId);
+    const { id: _id, dismissedBy: _dismissed, ...allowed } = updates;
+
+    const updated = {
+      ...announcement,
+      ...allowed,
+      updatedAt: new Date().toISOString(),
+    };
+
+    this.validate(updated);
+
+    this.announcements.set(announcementId, updated);
+    await this.saveAnnouncements();
+    this.scheduleStart(updated);
+
+    this.emit('announcement:updated', updated);
+    return updated;
+  }
+
+  async deleteAnnouncement(announcementId) {
+    const announcement = this.getAnnouncement(announcementId);
+
+    this.announcements.delete(announcementId);
+    this.cancelStart(announcementId);
+    await this.saveAnnouncements();
+
+    this.emit('announcement:deleted', announcement);
+    return announcement;
+  }
+
+  async dismiss(announcementId, userId) {
+    const announcement = this.getAnnouncement(announcementId);
+
+    if (!announcement.dismissible) {
+      throw new Error('Announcement cannot be dismissed');
+    }
+
+    if (!announcement.dismissedBy.includes(userId)) {
+      announcement.dismissedBy.push(userId);
+      await this.saveAnnouncements();
+    }
+
+    return { success: true };
+  }
+
+  getAnnouncement(announcementId) {
+    const announcement = this.announcements.get(announcementId);
+    if (!announcement) {
+      throw new Error(`Announcement not found: ${announcementId}`);
+    }
+    return announcement;
+  }
+
+  // Emits announcement:started when a scheduled announcement goes live
+  scheduleStart(announcement) {
+    this.cancelStart(announcement.id);
+
+    const delay = new Date(announcement.startsAt) - Date.now();
+    if (!(delay > 0)) return;
+
+    const timer = setTimeout(() => {
+      this.startTimers.delete(announcement.id);
+      const current = this.announcements.get(announcement.id);
+      if (!current) return;
+
+      if (new Date(current.startsAt) > Date.now()) {
+        this.scheduleStart(current);
+      } else if (this.isActive(current)) {
+        this.emit('announcement:started', current);
+      }
+    }, Math.min(delay, MAX_TIMER_DELAY));
+    timer.unref?.();
+    this.startTimers.set(announcement.id, timer);
+  }
+
+  cancelStart(announcementId) {
+    clearTimeout(this.startTimers.get(announcementId));
+    this.startTimers.delete(announcementId);
+  }
+
+  isActive(announcement, now = new Date()) {
+    return (
+      new Date(announcement.startsAt) <= now &&
+      (!announcement.endsAt || new Date(announcement.endsAt) > now)
+    );
+  }
+
+  matchesAudience(announcement, user, projectIds = []) {
+    const { type, value } = announcement.audience;
+
+    switch (type) {
+      case 'all':
+        return true;
+      case 'role':
+        return user?.role === value;
+      case 'project':
+        return projectIds.includes(value);
+      default:
+        return false;
+    }
+  }
+
+  // Active, targeted, and not yet dismissed announcements for a user
+  async getActiveFor(user, options = {}) {
+    const now = new Date();
+    const order = { critical: 0, warning: 1, info: 2 };
+
+    return Array.from(this.announcements.values())
+      .filter(
+        (a) =>
+          this.isActive(a, now) &&
+          this.matchesAudience(a, user, options.projectIds) &&
+          !a.dismissedBy.includes(user?.id)
+      )
+      .sort((a, b) => order[a.severity] - order[b.severity])
+      .map((a) => this.toPublic(a));
+  }
+
+  // Strip per-user dismissal state before sending to clients
+  toPublic(announcement) {
+    const { dismissedBy: _dismissed, ...publicAnnouncement } = announcement;
+    return publicAnnouncement;
+  }
+
+  async listAnnouncements() {
+    return Array.from(this.announcements.values()).sort(
+      (a, b) => new Date(b.createdAt) - new Date(a.createdAt)
+    );
+  }
+
+  async stop() {
+    try {
+      for (const announcementId of this.startTimers.keys()) {
+        this.cancelStart(announcementId);
+      }
+      await this.saveAnnouncements();
+      this.logger.info('AnnouncementManager stopped successfully');
+    } catch (error) {
+      this.logger.error('Error stopping AnnouncementManager:', error);
+      throw error;
+    }
+  }
+}
+
+export { AnnouncementManager, ANNOUNCEMENT_SEVERITIES, AUDIENCE_TYPES };
@@ -0,0 +1,160 @@
+/**
+ * Tests for Announcement Manager
+ */
+
+import { AnnouncementManager } from './announcement-manager.js';
+import { jest } from '@jest/globals';
+import { promises as fs } from 'fs';
+import os from 'os';
+import path from 'path';
+
+describe('AnnouncementManager', () => {
+  let dir;
+  let announcementManager;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'announcements-'));
+    announcementManager = new AnnouncementManager({
+      announcementsFile: path.join(dir, 'announcements.json'),
+    });
+  });
+
+  afterEach(async () => {
+    await announcementManager.stop();
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  const inMs = (ms) => new Date(Date.now() + ms).toISOString();
+
+  describe('createAnnouncement', () => {
+    it('should default to an info banner for everyone', async () => {
+      const announcement = await announcementManager.createAnnouncement(
+        { title: 'Maintenance tonight' },
+        'admin-1'
+      );
+
+      expect(announcement).toMatchObject({
+        severity: 'info',
+        audience: { type: 'all' },
+        dismissible: true,
+        createdBy: 'admin-1',
+      });
+    });
+
+    it('should reject malformed audiences', async () => {
+      for (const audience of ['all', ['role'], { type: 'team' }]) {
+        await expect(
+          announcementManager.createAnnouncement({ title: 'x', audience })
+        ).rejects.toThrow(/Audience must be an object|Invalid audience type/);
+      }
+      await expect(
+        announcementManager.createAnnouncement({
+          title: 'x',
+          audience: { type: 'role' },
+        })
+      ).rejects.toThrow('Audience role requires a value');
+    });
+
+    it('should reject windows that end before they start', async () => {
+      await expect(
+        announcementManager.createAnnouncement({
+          title: 'x',
+          startsAt: inMs(60000),
+          endsAt: inMs(1000),
+        })
+      ).rejects.toThrow('Announcement must end after it starts');
+    });
+  });
+
+  describe('getActiveFor', () => {
+    it('should filter by window, audience and dismissal', async () => {
+      const everyone = await announcementManager.createAnnouncement({
+        title: 'Everyone',
+      });
+      await announcementManager.createAnnouncement({
+        title: 'Admins',
+        severity: 'critical',
+        audience: { type: 'role', value: 'admin' },
+      });
+      await announcementManager.createAnnouncement({
+        title: 'Project',
+        audience: { type: 'project', value: 'p1' },
+      });
+      await announcementManager.createAnnouncement({
+        title: 'Later',
+        startsAt: inMs(60000),
+      });
+
+      const user = { id: 'user-1', role: 'user' };
+      const admin = { id: 'admin-1', role: 'admin' };
+
+      expect(
+        (await announcementManager.getActiveFor(user)).map((a) => a.title)
+      ).toEqual(['Everyone']);
+      expect(
+        (await announcementManager.getActiveFor(admin)).map((a) => a.title)
+      ).toEqual(['Admins', 'Everyone']);
+      expect(
+        await announcementManager.getActiveFor(user, { projectIds: ['p1'] })
+      ).toHaveLength(2);
+
+      await announcementManager.dismiss(everyone.id, user.id);
+      expect(await announcementManager.getActiveFor(user)).toEqual([]);
+    });
+
+    it('should not expose who dismissed a banner', async () => {
+      await announcementManager.createAnnouncement({ title: 'Everyone' });
+
+      const [announcement] = await announcementManager.getActiveFor({
+        id: 'user-1',
+      });
+
+      expect(announcement.dismissedBy).toBeUndefined();
+    });
+  });
+
+  describe('dismiss', () => {
+    it('should refuse banners that are not dismissible', async () => {
+      const announcement = await announcementManager.createAnnouncement({
+        title: 'Outage',
+        dismissible: false,
+      });
+
+      await expect(
+        announcementManager.dismiss(announcement.id, 'user-1')
+      ).rejects.toThrow('Announcement cannot be dismissed');
+    });
+  });
+
+  describe('scheduleStart', () => {
+    it('should emit announcement:started when a banner goes live', async () => {
+      const started = jest.fn();
+      announcementManager.on('announcement:started', started);
+
+      const announcement = await announcementManager.createAnnouncement({
+        title: 'Soon',
+        startsAt: inMs(30),
+      });
+      expect(started).not.toHaveBeenCalled();
+
+      await new Promise((resolve) => setTimeout(resolve, 80));
+      expect(started).toHaveBeenCalledWith(
+        expect.objectContaining({ id: announcement.id })
+      );
+    });
+
+    it('should not emit for deleted announcements', async () => {
+      const started = jest.fn();
+      announcementManager.on('announcement:started', started);
+
+      const announcement = await announcementManager.createAnnouncement({
+        title: 'Soon',
+        startsAt: inMs(30),
+      });
+      await announcementManager.deleteAnnouncement(announcement.id);
+
+      await new Promise((resolve) => setTimeout(resolve, 80));
+      expect(started).not.toHaveBeenCalled();
+    });
+  });
+});
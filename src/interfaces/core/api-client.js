@@ -51,6 +51,7 @@ class APIClient {
         PORT: config.port.toString(),
         HOST: config.host,
         NODE_ENV: process.env.NODE_ENV || 'development',
+        ...config.env,
       };
 
       this.serverProcess = spawn('node', [serverPath], {
@@ -160,6 +161,20 @@ class APIClient {
     }
   }
 
+  // Asks the server whether this client is still supported
+  async checkCompatibility(url, client, version) {
+    const response = await fetch(`${url}/api/version`, {
+      headers: { 'X-Client-Name': client, 'X-Client-Version': version },
+      signal: AbortSignal.timeout(5000),
+    });
+
+    if (!response.ok) {
+      throw new Error(`Version check failed with status: ${response.status}`);
+    }
+
+    return await response.json();
+  }
+
   async getServerStatus() {
     try {
       if (!this.serverProcess) {
@@ -11,6 +11,42 @@ import { promises as fs } from 'fs';
 import path from 'path';
 import { Logger } from './logger.js';
 import { ConfigManager } from './config-manager.js';
+import { GeoIPResolver } from './geoip.js';
+import { SessionActivityLog, fingerprintDevice } from './session-activity.js';
+import {
+  PersonalAccessTokenStore,
+  TOKEN_SCOPES,
+  isPersonalAccessToken,
+} from './personal-access-tokens.js';
+import { generateSecret, verifyTOTP, buildOtpauthURI } from './totp.js';
+import { OIDCProvider } from './oidc.js';
+
+// Backup codes are random enough (50 bits) that a fast hash is fine
+const BACKUP_CODE_ALPHABET = 'ABCDEFGHJKLMNPQRSTUVWXYZ23456789';
+
+function normalizeBackupCode(code) {
+  return String(code || '')
+    .toUpperCase()
+    .replace(/[\s-]/g, '');
+}
+
+function hashBackupCode(code) {
+  return crypto
+    .createHash('sha256')
+    .update(normalizeBackupCode(code))
+    .digest('hex');
+}
+
+// Role-based check shared by the CLI session and per-connection filters;
+// admins hold every permission, and a null permission is public
+function hasPermission(user, permission) {
+  if (!permission) return true;
+  if (!user) return false;
+  if (user.role === 'admin') return true;
+
+  const permissions = user.permissions || [];
+  return permissions.includes(permission) || permissions.includes('*');
+}
 
 class AuthManager extends EventEmitter {
   constructor(config = {}) {
@@ -26,11 +62,48 @@ class AuthManager extends EventEmitter {
       lockoutTime: config.lockoutTime || 15 * 60 * 1000, // 15 minutes
       usersFile: config.usersFile || './data/users.json',
       sessionsFile: config.sessionsFile || './data/sessions.json',
+      mfaIssuer: config.mfaIssuer || process.env.MFA_ISSUER || 'KaskMan',
+      mfaSecretLength: config.mfaSecretLength || 20,
+      // Roles that must complete MFA enrollment before they can sign in
+      mfaRequiredRoles:
+        config.mfaRequiredRoles ||
+        (process.env.MFA_REQUIRED_ROLES || '')
+          .split(',')
+          .map((role) => role.trim())
+          .filter(Boolean),
+      mfaWindow: config.mfaWindow ?? 1, // accepted 30s steps of clock drift
+      mfaChallengeExpiresIn: config.mfaChallengeExpiresIn || '5m',
+      backupCodeCount: config.backupCodeCount || 10,
+      // With single sign-on configured, SSO_ONLY turns off password sign-in
+      // for everyone except the break-glass accounts
+      ssoOnly: config.ssoOnly ?? process.env.SSO_ONLY === 'true',
+      breakGlassUsers:
+        config.breakGlassUsers ||
+        (process.env.SSO_BREAK_GLASS_USERS || '')
+          .split(',')
+          .map((username) => username.trim())
+          .filter(Boolean),
+      // Create accounts for SSO users signing in for the first time
+      ssoAutoProvision:
+        config.ssoAutoProvision ?? process.env.OIDC_AUTO_PROVISION !== 'false',
+      // Re-apply the mapped role to existing accounts on every SSO login;
+      // otherwise only new accounts get it
+      ssoSyncRoles:
+        config.ssoSyncRoles ?? process.env.OIDC_SYNC_ROLES === 'true',
       ...config,
     };
 
     this.logger = new Logger('AuthManager');
-    this.configManager = new ConfigManager();
+    this.configManager = config.configManager || new ConfigManager();
+    this.geoip = new GeoIPResolver(this.config.geoip);
+    this.activity = new SessionActivityLog(this.config.activity);
+    this.personalTokens = new PersonalAccessTokenStore(
+      this.config.personalTokens
+    );
+    this.oidc = new OIDCProvider(this.config.oidc);
+    for (const event of ['token:expiring', 'token:expired']) {
+      this.personalTokens.on(event, (record) => this.emit(event, record));
+    }
 
     this.users = new Map();
     this.sessions = new Map();
@@ -45,7 +118,13 @@ class AuthManager extends EventEmitter {
       await this.ensureDataDirectory();
       await this.loadUsers();
       await this.loadSessions();
-      await this.createDefaultAdmin();
+      await this.geoip.load();
+      await this.activity.load();
+      await this.personalTokens.initialize();
+
+      if (this.needsBootstrap()) {
+        this.logger.warn('No users exist; complete first-run setup first');
+      }
 
       // Cleanup expired sessions
       setInterval(() => this.cleanupExpiredSessions(), 60 * 60 * 1000); // Every hour
@@ -57,6 +136,14 @@ class AuthManager extends EventEmitter {
     }
   }
 
+  // Adopt values from the shared configuration once it has been loaded
+  applyConfiguration() {
+    this.config.sessionTimeout = this.configManager.get(
+      'security.sessionTimeout',
+      this.config.sessionTimeout
+    );
+  }
+
   generateSecret() {
     return crypto.randomBytes(64).toString('hex');
   }
@@ -138,32 +225,9 @@ class AuthManager extends EventEmitter {
     }
   }
 
-  async createDefaultAdmin() {
-    // Create default admin user if no users exist
-    if (this.users.size === 0) {
-      const defaultAdmin = {
-        id: crypto.randomUUID(),
-        username: 'admin',
-        email: 'admin@localhost',
-        password: await bcrypt.hash('admin123', this.config.bcryptRounds),
-        role: 'admin',
-        permissions: ['*'],
-        createdAt: new Date().toISOString(),
-        updatedAt: new Date().toISOString(),
-        active: true,
-        lastLogin: null,
-        loginAttempts: 0,
-        lockedUntil: null,
-      };
-
-      this.users.set(defaultAdmin.id, defaultAdmin);
-      await this.saveUsers();
-
-      this.logger.info(
-        'Created default admin user (username: admin, password: admin123)'
-      );
-      this.logger.warn('Please change the default admin password immediately!');
-    }
+  // Fresh installs get their first admin through first-run setup
+  needsBootstrap() {
+    return this.users.size === 0;
   }
 
   async createUser(userData) {
@@ -178,7 +242,7 @@ class AuthManager extends EventEmitter {
 
       // Check if email already exists
       const existingEmail = Array.from(this.users.values()).find(
-        (u) => u.email === userData.email
+        (u) => userData.email && u.email === userData.email
       );
       if (existingEmail) {
         throw new Error('Email already exists');
@@ -201,6 +265,10 @@ class AuthManager extends EventEmitter {
         loginAttempts: 0,
         lockedUntil: null,
         profile: userData.profile || {},
+        // Set when an existing user added this one
+        invitedBy: userData.invitedBy || null,
+        // Identity provider link for single sign-on users
+        sso: userData.sso || null,
       };
 
       this.users.set(user.id, user);
@@ -218,7 +286,8 @@ class AuthManager extends EventEmitter {
     }
   }
 
-  async login(credentials) {
+  // context: { ipAddress, userAgent, acceptLanguage } from the request
+  async login(credentials, context = {}) {
     try {
       const { username, password, token } = credentials;
 
@@ -227,82 +296,463 @@ class AuthManager extends EventEmitter {
         const decoded = jwt.verify(token, this.config.jwtSecret);
         const user = this.users.get(decoded.userId);
 
+        // MFA challenge tokens only work with the MFA endpoints
+        if (decoded.purpose) {
+          throw new Error('Invalid token');
+        }
+
         if (!user || !user.active) {
           throw new Error('Invalid token or user not active');
         }
 
-        const session = await this.createSession(user);
+        const session = await this.createSession(user, context);
         this.currentUser = user;
         this.currentSession = session;
+        await this.recordLogin(user, session, 'token');
 
         this.emit('user:login', { user, session, method: 'token' });
         return { user: this.sanitizeUser(user), session, token };
       }
 
       // Username/password login
-      if (!username || !password) {
-        throw new Error('Username and password are required');
+      const user = await this.verifyCredentials(username, password);
+
+      // Second factor; failed attempts stay counted until it is passed.
+      // Without a code the caller gets a short-lived challenge token.
+      if (user.mfa?.enabled || this.requiresMfa(user)) {
+        if (!credentials.mfaCode || !user.mfa?.enabled) {
+          return this.issueMfaChallenge(user);
+        }
+        await this.checkMfaCode(user, credentials.mfaCode);
+        return this.completeLogin(user, context, 'mfa');
       }
 
-      const user = Array.from(this.users.values()).find(
-        (u) => u.username === username
+      return this.completeLogin(user, context, 'password');
+    } catch (error) {
+      this.logger.error('Login failed:', error);
+      throw error;
+    }
+  }
+
+  // Password check with lockout accounting, shared by login and the CLI's
+  // local MFA commands
+  async verifyCredentials(username, password) {
+    if (!username || !password) {
+      throw new Error('Username and password are required');
+    }
+
+    const user = Array.from(this.users.values()).find(
+      (u) => u.username === username
+    );
+    if (!user) {
+      throw new Error('Invalid username or password');
+    }
+
+    await this.assertNotLocked(user);
+
+    // Verify password
+    const isPasswordValid = await bcrypt.compare(password, user.password);
+    if (!isPasswordValid) {
+      user.loginAttempts = (user.loginAttempts || 0) + 1;
+      await this.saveUsers();
+      throw new Error('Invalid username or password');
+    }
+
+    // Check if user is active
+    if (!user.active) {
+      throw new Error('Account is disabled');
+    }
+
+    if (this.isPasswordLoginDisabled(user)) {
+      throw new Error('Password sign-in is disabled; use single sign-on');
+    }
+
+    return user;
+  }
+
+  async assertNotLocked(user) {
+    // Check if account is locked
+    if (user.lockedUntil && new Date(user.lockedUntil) > new Date()) {
+      throw new Error(
+        'Account is locked due to too many failed login attempts'
       );
-      if (!user) {
-        throw new Error('Invalid username or password');
+    }
+
+    // Check login attempts
+    if (user.loginAttempts >= this.config.maxLoginAttempts) {
+      user.lockedUntil = new Date(
+        Date.now() + this.config.lockoutTime
+      ).toISOString();
+      await this.saveUsers();
+      throw new Error('Account locked due to too many failed login attempts');
+    }
+  }
+
+  async completeLogin(user, context, method) {
+    // Reset login attempts on successful login
+    user.loginAttempts = 0;
+    user.lockedUntil = null;
+    user.lastLogin = new Date().toISOString();
+    await this.saveUsers();
+
+    // Create session
+    const session = await this.createSession(user, context);
+    this.currentUser = user;
+    this.currentSession = session;
+    await this.recordLogin(user, session, method);
+
+    this.emit('user:login', { user, session, method });
+    this.logger.info(`User logged in: ${user.username} (${user.id})`);
+
+    return {
+      user: this.sanitizeUser(user),
+      session,
+      token: session.token,
+    };
+  }
+
+  // Single sign-on through an OIDC identity provider. Logins the provider
+  // did not confirm with a second factor still get the MFA challenge.
+
+  isPasswordLoginDisabled(user) {
+    return (
+      this.oidc.enabled &&
+      this.config.ssoOnly &&
+      !this.config.breakGlassUsers.includes(user.username)
+    );
+  }
+
+  getSsoStatus() {
+    return {
+      ...this.oidc.getPublicConfig(),
+      passwordLogin:
+        this.oidc.enabled && this.config.ssoOnly ? 'break-glass' : 'enabled',
+    };
+  }
+
+  startSsoLogin(options = {}) {
+    return this.oidc.createAuthorizationRequest(options);
+  }
+
+  // params: { code, state, error } from the identity provider's redirect
+  async completeSsoLogin(params, context = {}) {
+    try {
+      const { claims, returnTo } = await this.oidc.handleCallback(params);
+      const user = await this.provisionSsoUser(claims);
+      if (!user.active) {
+        throw new Error('Account is disabled');
+      }
+
+      // The provider's second factor counts only when the ID token says it
+      // happened (amr, RFC 8176); otherwise password login's rules apply
+      const providerMfa = [].concat(claims.amr ?? []).includes('mfa');
+      if (!providerMfa && (user.mfa?.enabled || this.requiresMfa(user))) {
+        return { ...this.issueMfaChallenge(user), returnTo };
       }
 
-      // Check if account is locked
-      if (user.lockedUntil && new Date(user.lockedUntil) > new Date()) {
+      return {
+        ...(await this.completeLogin(user, context, 'sso')),
+        returnTo,
+      };
+    } catch (error) {
+      this.logger.error('SSO login failed:', error);
+      throw error;
+    }
+  }
+
+  /**
+   * Find the account for a verified ID token, linking a local account with
+   * the same verified email the first time, or creating one. New accounts
+   * get the mapped role; linked accounts only follow it with ssoSyncRoles.
+   * Admin accounts are never linked by email, since whoever controls that
+   * address at the provider would inherit them.
+   */
+  async provisionSsoUser(claims) {
+    const subject = `${this.oidc.config.issuer}|${claims.sub}`;
+    const role = this.oidc.mapRole(claims);
+    const users = Array.from(this.users.values());
+
+    let user = users.find((u) => u.sso?.subject === subject);
+    if (!user && claims.email && claims.email_verified) {
+      user = users.find(
+        (u) =>
+          u.email === claims.email &&
+          !u.sso &&
+          !this.config.breakGlassUsers.includes(u.username)
+      );
+      if (user?.role === 'admin') {
         throw new Error(
-          'Account is locked due to too many failed login attempts'
+          'This email belongs to an admin account, which is not linked to single sign-on automatically'
         );
       }
+    }
 
-      // Check login attempts
-      if (user.loginAttempts >= this.config.maxLoginAttempts) {
-        user.lockedUntil = new Date(
-          Date.now() + this.config.lockoutTime
-        ).toISOString();
-        await this.saveUsers();
-        throw new Error('Account locked due to too many failed login attempts');
+    if (user) {
+      const updates = {};
+      if (user.sso?.subject !== subject) {
+        updates.sso = { subject, linkedAt: new Date().toISOString() };
       }
-
-      // Verify password
-      const isPasswordValid = await bcrypt.compare(password, user.password);
-      if (!isPasswordValid) {
-        user.loginAttempts = (user.loginAttempts || 0) + 1;
-        await this.saveUsers();
-        throw new Error('Invalid username or password');
+      if (this.config.ssoSyncRoles && user.role !== role) {
+        if (this.isLastActiveAdmin(user)) {
+          this.logger.warn(
+            `Not changing ${user.username} to ${role}: last active admin`
+          );
+        } else {
+          updates.role = role;
+        }
       }
 
-      // Check if user is active
-      if (!user.active) {
-        throw new Error('Account is disabled');
+      if (Object.keys(updates).length > 0) {
+        await this.updateUser(user.id, updates);
       }
+      return this.users.get(user.id);
+    }
 
-      // Reset login attempts on successful login
-      user.loginAttempts = 0;
-      user.lockedUntil = null;
-      user.lastLogin = new Date().toISOString();
-      await this.saveUsers();
+    if (!this.config.ssoAutoProvision) {
+      throw new Error('No account exists for this identity');
+    }
+
+    const base = (
+      claims.preferred_username ||
+      claims.email?.split('@')[0] ||
+      `sso-${claims.sub}`
+    ).replace(/[^a-zA-Z0-9._-]/g, '-');
+    let username = base;
+    for (let n = 2; users.some((u) => u.username === username); n++) {
+      username = `${base}-${n}`;
+    }
 
-      // Create session
-      const session = await this.createSession(user);
-      this.currentUser = user;
-      this.currentSession = session;
+    const created = await this.createUser({
+      username,
+      email: claims.email || null,
+      // Never used: SSO accounts have no password sign-in
+      password: crypto.randomBytes(32).toString('hex'),
+      role,
+      profile: { name: claims.name || null },
+      sso: { subject, linkedAt: new Date().toISOString() },
+    });
+    return this.users.get(created.id);
+  }
 
-      this.emit('user:login', { user, session, method: 'password' });
-      this.logger.info(`User logged in: ${user.username} (${user.id})`);
+  // MFA: TOTP from an authenticator app, with single-use backup codes
 
-      return {
-        user: this.sanitizeUser(user),
-        session,
-        token: session.token,
-      };
+  requiresMfa(user) {
+    return this.config.mfaRequiredRoles.includes(user.role);
+  }
+
+  // mfa-login asks for a code; mfa-setup lets a user whose role requires
+  // MFA enroll before their first session
+  issueMfaChallenge(user) {
+    const purpose = user.mfa?.enabled ? 'mfa-login' : 'mfa-setup';
+    const mfaToken = jwt.sign(
+      { userId: user.id, purpose },
+      this.config.jwtSecret,
+      { expiresIn: this.config.mfaChallengeExpiresIn }
+    );
+
+    return {
+      mfaRequired: purpose === 'mfa-login',
+      mfaSetupRequired: purpose === 'mfa-setup',
+      mfaToken,
+    };
+  }
+
+  verifyMfaChallenge(mfaToken, purpose) {
+    let decoded;
+    try {
+      decoded = jwt.verify(mfaToken, this.config.jwtSecret);
     } catch (error) {
-      this.logger.error('Login failed:', error);
-      throw error;
+      throw new Error('Invalid or expired MFA challenge');
+    }
+    if (decoded.purpose !== purpose) {
+      throw new Error('Invalid or expired MFA challenge');
+    }
+
+    const user = this.users.get(decoded.userId);
+    if (!user || !user.active) {
+      throw new Error('User not found or inactive');
     }
+    return user;
+  }
+
+  async completeMfaLogin(mfaToken, code, context = {}) {
+    const user = this.verifyMfaChallenge(mfaToken, 'mfa-login');
+    await this.assertNotLocked(user);
+    await this.checkMfaCode(user, code);
+    return this.completeLogin(user, context, 'mfa');
+  }
+
+  // Enrollment on the way in, for roles that require MFA
+  async completeMfaSetup(mfaToken, code, context = {}) {
+    const user = this.verifyMfaChallenge(mfaToken, 'mfa-setup');
+    await this.assertNotLocked(user);
+    const { backupCodes } = await this.confirmMfa(user.id, code);
+    return { ...(await this.completeLogin(user, context, 'mfa')), backupCodes };
+  }
+
+  // Accepts a TOTP code (each time step once) or an unused backup code
+  async checkMfaCode(user, code) {
+    const { mfa } = user;
+    if (!mfa?.enabled) {
+      throw new Error('MFA is not enabled');
+    }
+
+    const counter = verifyTOTP(mfa.secret, code, {
+      window: this.config.mfaWindow,
+    });
+    if (counter !== null && counter > (mfa.lastCounter ?? -1)) {
+      mfa.lastCounter = counter;
+      await this.saveUsers();
+      return 'totp';
+    }
+
+    const index = mfa.backupCodes.indexOf(hashBackupCode(code));
+    if (index !== -1) {
+      mfa.backupCodes.splice(index, 1);
+      await this.saveUsers();
+      this.emit('auth:mfa-backup-code-used', {
+        user: this.sanitizeUser(user),
+        remaining: mfa.backupCodes.length,
+      });
+      return 'backup-code';
+    }
+
+    user.loginAttempts = (user.loginAttempts || 0) + 1;
+    await this.saveUsers();
+    throw new Error('Invalid MFA code');
+  }
+
+  generateBackupCodes() {
+    return Array.from({ length: this.config.backupCodeCount }, () => {
+      const chars = Array.from(
+        crypto.randomBytes(10),
+        (byte) => BACKUP_CODE_ALPHABET[byte % BACKUP_CODE_ALPHABET.length]
+      ).join('');
+      return `${chars.slice(0, 5)}-${chars.slice(5)}`;
+    });
+  }
+
+  async enrollMfa(userId) {
+    const user = this.users.get(userId);
+    if (!user) {
+      throw new Error('User not found');
+    }
+    if (user.mfa?.enabled) {
+      throw new Error('MFA is already enabled');
+    }
+
+    // Not active until confirmed with a code from the app
+    const secret = generateSecret(this.config.mfaSecretLength);
+    user.mfa = { enabled: false, pendingSecret: secret };
+    await this.saveUsers();
+
+    return {
+      secret,
+      otpauthUri: buildOtpauthURI({
+        secret,
+        account: user.email || user.username,
+        issuer: this.config.mfaIssuer,
+      }),
+    };
+  }
+
+  async confirmMfa(userId, code) {
+    const user = this.users.get(userId);
+    const pendingSecret = user?.mfa?.pendingSecret;
+    if (!pendingSecret) {
+      throw new Error('Start MFA enrollment first');
+    }
+
+    const counter = verifyTOTP(pendingSecret, code, {
+      window: this.config.mfaWindow,
+    });
+    if (counter === null) {
+      throw new Error('Invalid MFA code');
+    }
+
+    const backupCodes = this.generateBackupCodes();
+    user.mfa = {
+      enabled: true,
+      secret: pendingSecret,
+      lastCounter: counter,
+      backupCodes: backupCodes.map(hashBackupCode),
+      enrolledAt: new Date().toISOString(),
+    };
+    user.updatedAt = new Date().toISOString();
+    await this.saveUsers();
+
+    this.emit('auth:mfa-enabled', { user: this.sanitizeUser(user) });
+    this.logger.info(`MFA enabled: ${user.username} (${userId})`);
+
+    // Shown once; only hashes are kept
+    return { enabled: true, backupCodes };
+  }
+
+  async regenerateBackupCodes(userId, code) {
+    const user = this.users.get(userId);
+    if (!user) {
+      throw new Error('User not found');
+    }
+    await this.checkMfaCode(user, code);
+
+    const backupCodes = this.generateBackupCodes();
+    user.mfa.backupCodes = backupCodes.map(hashBackupCode);
+    await this.saveUsers();
+
+    return { backupCodes };
+  }
+
+  async disableMfa(userId, code) {
+    const user = this.users.get(userId);
+    if (!user) {
+      throw new Error('User not found');
+    }
+    if (this.requiresMfa(user)) {
+      throw new Error(`MFA is required for ${user.role} accounts`);
+    }
+    await this.checkMfaCode(user, code);
+
+    delete user.mfa;
+    user.updatedAt = new Date().toISOString();
+    await this.saveUsers();
+
+    this.emit('auth:mfa-disabled', { user: this.sanitizeUser(user) });
+    this.logger.info(`MFA disabled: ${user.username} (${userId})`);
+
+    return { enabled: false };
+  }
+
+  // Admin recovery for a user who lost both their device and backup codes;
+  // a role that requires MFA is asked to enroll again at the next login
+  async resetMfa(userId, resetBy) {
+    const user = this.users.get(userId);
+    if (!user) {
+      throw new Error('User not found');
+    }
+
+    delete user.mfa;
+    user.updatedAt = new Date().toISOString();
+    await this.saveUsers();
+
+    this.emit('auth:mfa-disabled', { user: this.sanitizeUser(user), resetBy });
+    this.logger.warn(`MFA reset: ${user.username} (${userId}) by ${resetBy}`);
+
+    return { enabled: false };
+  }
+
+  getMfaStatus(userId) {
+    const user = this.users.get(userId);
+    if (!user) {
+      throw new Error('User not found');
+    }
+
+    return {
+      enabled: Boolean(user.mfa?.enabled),
+      required: this.requiresMfa(user),
+      pendingEnrollment: Boolean(user.mfa?.pendingSecret),
+      enrolledAt: user.mfa?.enrolledAt || null,
+      backupCodesRemaining: user.mfa?.backupCodes?.length ?? 0,
+    };
   }
 
   async logout(sessionId) {
@@ -327,6 +777,11 @@ class AuthManager extends EventEmitter {
         this.currentSession = null;
       }
 
+      await this.activity.record(session.userId, {
+        type: 'logout',
+        sessionId: session.id,
+      });
+
       this.emit('user:logout', { user, session });
       this.logger.info(`User logged out: ${user?.username} (${user?.id})`);
 
@@ -337,7 +792,64 @@ class AuthManager extends EventEmitter {
     }
   }
 
-  async createSession(user) {
+  // Logins from a device or country/region the user has not used before
+  // emit auth:new-device so the user can revoke the session if it's not them
+  async recordLogin(user, session, method) {
+    const { newDevice, newLocation } = this.activity.compareWithHistory(
+      user.id,
+      session
+    );
+
+    const entry = await this.activity.record(user.id, {
+      type: 'login',
+      method,
+      sessionId: session.id,
+      ipAddress: session.ipAddress,
+      userAgent: session.userAgent,
+      deviceId: session.deviceId,
+      location: session.location,
+      newDevice,
+      newLocation,
+    });
+
+    if (newDevice || newLocation) {
+      this.logger.warn(`Login from a new device or location: ${user.username}`);
+      this.emit('auth:new-device', { user: this.sanitizeUser(user), entry });
+    }
+  }
+
+  async revokeSession(userId, sessionId) {
+    const session = this.sessions.get(sessionId);
+    if (!session || session.userId !== userId) {
+      throw new Error('Session not found');
+    }
+
+    this.sessions.delete(sessionId);
+    this.refreshTokens.delete(session.refreshToken);
+    await this.saveSessions();
+
+    await this.activity.record(userId, { type: 'revoked', sessionId });
+    this.emit('session:revoked', { userId, sessionId });
+    this.logger.info(`Session revoked: ${sessionId} (${userId})`);
+
+    return { success: true };
+  }
+
+  async getSessionActivity(userId, options = {}) {
+    // Tokens never leave the server
+    const sessions = Array.from(this.sessions.values())
+      .filter((session) => session.userId === userId)
+      .map(
+        ({ token: _token, refreshToken: _refreshToken, ...session }) => session
+      );
+
+    return {
+      sessions,
+      timeline: this.activity.getTimeline(userId, options),
+    };
+  }
+
+  async createSession(user, context = {}) {
     const sessionId = crypto.randomUUID();
     const refreshToken = crypto.randomBytes(32).toString('hex');
     const expiresAt = new Date(
@@ -351,8 +863,10 @@ class AuthManager extends EventEmitter {
       createdAt: new Date().toISOString(),
       expiresAt,
       lastActivity: new Date().toISOString(),
-      ipAddress: null, // Will be set by middleware
-      userAgent: null, // Will be set by middleware
+      ipAddress: context.ipAddress || null,
+      userAgent: context.userAgent || null,
+      deviceId: context.deviceId || fingerprintDevice(context),
+      location: context.ipAddress ? this.geoip.lookup(context.ipAddress) : null,
     };
 
     // Generate JWT token
@@ -398,8 +912,12 @@ class AuthManager extends EventEmitter {
         throw new Error('User not found or inactive');
       }
 
-      // Create new session
-      const newSession = await this.createSession(user);
+      // Create new session on the same device
+      const newSession = await this.createSession(user, {
+        ipAddress: session.ipAddress,
+        userAgent: session.userAgent,
+        deviceId: session.deviceId,
+      });
 
       // Remove old session
       this.sessions.delete(sessionId);
@@ -419,7 +937,12 @@ class AuthManager extends EventEmitter {
     }
   }
 
-  async verifyToken(token) {
+  // context: { ipAddress } for personal access token usage counters
+  async verifyToken(token, context = {}) {
+    if (isPersonalAccessToken(token)) {
+      return this.verifyPersonalToken(token, context);
+    }
+
     try {
       const decoded = jwt.verify(token, this.config.jwtSecret);
 
@@ -456,6 +979,75 @@ class AuthManager extends EventEmitter {
     }
   }
 
+  // Personal access tokens act as their owner, narrowed to the token scopes
+  async verifyPersonalToken(token, context = {}) {
+    const record = await this.personalTokens.verify(token, context);
+
+    const user = this.users.get(record.userId);
+    if (!user || !user.active) {
+      throw new Error('User not found or inactive');
+    }
+    if (record.usageCount === 1) {
+      this.emit('token:first-use', record);
+    }
+
+    const granted = this.getGrantableScopes(user);
+    const scopes = record.scopes.filter((scope) => granted.includes(scope));
+
+    return {
+      user: {
+        ...this.sanitizeUser(user),
+        role:
+          user.role === 'admin' && !scopes.includes('admin')
+            ? 'user'
+            : user.role,
+        permissions: scopes.filter((scope) => scope !== 'admin'),
+        // Lets routes tell token callers from interactive sessions
+        personalTokenId: record.id,
+        tokenScopes: scopes,
+      },
+      session: null,
+      decoded: { userId: user.id, tokenId: record.id, scopes },
+      personalToken: record,
+    };
+  }
+
+  // Scopes a user may put on a token: never more than they hold themselves
+  getGrantableScopes(user) {
+    if (user.role === 'admin' || user.permissions.includes('*')) {
+      return TOKEN_SCOPES;
+    }
+    return TOKEN_SCOPES.filter(
+      (scope) => scope !== 'admin' && user.permissions.includes(scope)
+    );
+  }
+
+  // caller: the authenticated user object. A caller using a personal access
+  // token can only grant scopes that token has.
+  async createPersonalToken(userId, options = {}, caller = null) {
+    const user = this.users.get(userId);
+    if (!user) {
+      throw new Error('User not found');
+    }
+
+    let grantable = this.getGrantableScopes(user);
+    if (caller?.personalTokenId) {
+      grantable = grantable.filter((scope) =>
+        caller.tokenScopes.includes(scope)
+      );
+    }
+
+    return this.personalTokens.create(userId, options, grantable);
+  }
+
+  listPersonalTokens(userId) {
+    return this.personalTokens.listForUser(userId);
+  }
+
+  async revokePersonalToken(userId, tokenId) {
+    return this.personalTokens.revoke(userId, tokenId);
+  }
+
   async getStatus() {
     return {
       authenticated: !!this.currentUser,
@@ -477,16 +1069,7 @@ class AuthManager extends EventEmitter {
       throw new Error('Authentication required');
     }
 
-    // Admin has all permissions
-    if (this.currentUser.role === 'admin') {
-      return true;
-    }
-
-    // Check specific permissions
-    return (
-      this.currentUser.permissions.includes(permission) ||
-      this.currentUser.permissions.includes('*')
-    );
+    return hasPermission(this.currentUser, permission);
   }
 
   async requirePermission(permission) {
@@ -496,8 +1079,8 @@ class AuthManager extends EventEmitter {
   }
 
   sanitizeUser(user) {
-    const { password: _, ...sanitizedUser } = user; // eslint-disable-line no-unused-vars
-    return sanitizedUser;
+    const { password: _, mfa, ...sanitizedUser } = user; // eslint-disable-line no-unused-vars
+    return { ...sanitizedUser, mfaEnabled: Boolean(mfa?.enabled) };
   }
 
   async cleanupExpiredSessions() {
@@ -551,9 +1134,18 @@ class AuthManager extends EventEmitter {
       );
     }
 
+    const demoted =
+      (updates.role !== undefined && updates.role !== 'admin') ||
+      updates.active === false;
+    if (demoted && this.isLastActiveAdmin(user)) {
+      throw new Error('Cannot remove the last active admin');
+    }
+
+    // MFA state only changes through the enrollment methods
+    const { mfa: _mfa, ...changes } = updates;
     const updatedUser = {
       ...user,
-      ...updates,
+      ...changes,
       updatedAt: new Date().toISOString(),
     };
 
@@ -566,6 +1158,16 @@ class AuthManager extends EventEmitter {
     return this.sanitizeUser(updatedUser);
   }
 
+  isLastActiveAdmin(user) {
+    return (
+      user.role === 'admin' &&
+      user.active !== false &&
+      !Array.from(this.users.values()).some(
+        (u) => u.id !== user.id && u.role === 'admin' && u.active !== false
+      )
+    );
+  }
+
   async deleteUser(userId) {
     const user = this.users.get(userId);
     if (!user) {
@@ -594,6 +1196,8 @@ class AuthManager extends EventEmitter {
     this.users.delete(userId);
     await this.saveUsers();
     await this.saveSessions();
+    await this.activity.forgetUser(userId);
+    await this.personalTokens.revokeAllForUser(userId);
 
     this.emit('user:deleted', user);
     this.logger.info(`User deleted: ${user.username} (${userId})`);
@@ -636,6 +1240,7 @@ class AuthManager extends EventEmitter {
     try {
       await this.saveSessions();
       await this.saveUsers();
+      await this.personalTokens.stop();
 
       this.logger.info('AuthManager stopped successfully');
     } catch (error) {
@@ -645,4 +1250,4 @@ class AuthManager extends EventEmitter {
   }
 }
 
-export { AuthManager };
+export { AuthManager, hasPermission };
@@ -0,0 +1,293 @@
+/**
+ * Backup Manager
+ * Versioned backup archives of users, projects, R&D suggestions, activity
+ * and settings, with a restore path that can be previewed as a dry run
+ */
+
+import { EventEmitter } from 'events';
+import { promises as fs } from 'fs';
+import path from 'path';
+import crypto from 'crypto';
+import { promisify } from 'util';
+import { gzip, gunzip } from 'zlib';
+import { Logger } from './logger.js';
+import { createBackupStore } from './backup-storage.js';
+
+const gzipAsync = promisify(gzip);
+const gunzipAsync = promisify(gunzip);
+
+// Bump when the archive layout changes; restore refuses newer formats
+const BACKUP_FORMAT_VERSION = 1;
+
+// Live sessions are never backed up: restoring them would revive tokens
+const DEFAULT_SOURCES = {
+  data: {
+    root: './data',
+    include: /\.json$/,
+    exclude: [/^sessions\.json$/, /^rnd-module\/backups\//],
+  },
+  projects: {
+    root: './projects',
+    include: /^[^/]+\/project\.json$/,
+    exclude: [],
+  },
+};
+
+function checksum(content) {
+  return crypto.createHash('sha256').update(content).digest('hex');
+}
+
+async function walk(directory, prefix = '') {
+  let entries;
+  try {
+    entries = await fs.readdir(directory, { withFileTypes: true });
+  } catch (error) {
+    if (error.code === 'ENOENT') return [];
+    throw error;
+  }
+
+  const files = [];
+  for (const entry of entries) {
+    const relative = prefix ? `${prefix}/${entry.name}` : entry.name;
+    if (entry.isDirectory()) {
+      files.push(...(await walk(path.join(directory, entry.name), relative)));
+    } else if (entry.isFile()) {
+      files.push(relative);
+    }
+  }
+  return files;
+}
+
+class BackupManager extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      target: config.target || process.env.BACKUP_TARGET || './backups',
+      retain: config.retain || 10,
+      sources: config.sources || DEFAULT_SOURCES,
+      appVersion:
+        config.appVersion || process.env.npm_package_version || '1.0.0',
+      ...config,
+    };
+
+    this.logger = new Logger('BackupManager');
+    this.store = createBackupStore(this.config.target);
+    this.running = false;
+  }
+
+  async initialize() {
+    try {
+      this.logger.info(`Backups stored in ${this.store.describe()}`);
+      this.logger.info('BackupManager initialized successfully');
+    } catch (error) {
+      this.logger.error('Failed to initialize BackupManager:', error);
+      throw error;
+    }
+  }
+
+  async collectFiles() {
+    const files = [];
+
+    for (const [source, { root, include, exclude }] of Object.entries(
+      this.config.sources
+    )) {
+      for (const relative of await walk(root)) {
+        if (
+          !include.test(relative) ||
+          exclude.some((pattern) => pattern.test(relative))
+        ) {
+          continue;
+        }
+
+        const content = await fs.readFile(path.join(root, relative), 'utf8');
+        files.push({ path: `${source}/${relative}`, content });
+      }
+    }
+
+    return files;
+  }
+
+  async createBackup({ createdBy = null, note = '', label = 'backup' } = {}) {
+    if (this.running) {
+      throw new Error('A backup or restore is already running');
+    }
+    this.running = true;
+
+    try {
+      const files = await this.collectFiles();
+      const createdAt = new Date().toISOString();
+      const stamp = createdAt.replace(/[:.]/g, '-');
+      const name = `kaskman-${label}-${stamp}.json.gz`;
+
+      const manifest = {
+        formatVersion: BACKUP_FORMAT_VERSION,
+        appVersion: this.config.appVersion,
+        name,
+        createdAt,
+        createdBy,
+        note,
+        files: files.map((file) => ({
+          path: file.path,
+          size: Buffer.byteLength(file.content),
+          sha256: checksum(file.content),
+        })),
+      };
+
+      const archive = await gzipAsync(
+        JSON.stringify({
+          manifest,
+          files: Object.fromEntries(
+            files.map((file) => [file.path, file.content])
+          ),
+        })
+      );
+      await this.store.put(name, archive);
+      await this.prune();
+
+      this.emit('backup:created', manifest);
+      this.logger.info(
+        `Backup created: ${name} (${files.length} files, ${archive.length} bytes)`
+      );
+
+      return { ...manifest, size: archive.length };
+    } catch (error) {
+      this.logger.error('Failed to create backup:', error);
+      throw error;
+    } finally {
+      this.running = false;
+    }
+  }
+
+  async listBackups() {
+    const backups = await this.store.list();
+    return backups.sort((a, b) => b.modifiedAt.localeCompare(a.modifiedAt));
+  }
+
+  // Keeps the newest `retain` regular backups; pre-restore snapshots are
+  // never pruned automatically
+  async prune() {
+    const backups = (await this.listBackups()).filter(
+      (backup) => !backup.name.startsWith('kaskman-pre-restore-')
+    );
+
+    for (const backup of backups.slice(this.config.retain)) {
+      await this.store.delete(backup.name);
+      this.logger.info(`Removed old backup: ${backup.name}`);
+    }
+  }
+
+  async readBackup(name) {
+    const archive = JSON.parse(
+      (await gunzipAsync(await this.store.get(name))).toString('utf8')
+    );
+    const { manifest, files } = archive;
+
+    if (!manifest || manifest.formatVersion > BACKUP_FORMAT_VERSION) {
+      throw new Error(
+        `Backup format ${manifest?.formatVersion} is not supported (max ${BACKUP_FORMAT_VERSION})`
+      );
+    }
+
+    for (const entry of manifest.files) {
+      if (files[entry.path] === undefined) {
+        throw new Error(`Backup is missing ${entry.path}`);
+      }
+      if (checksum(files[entry.path]) !== entry.sha256) {
+        throw new Error(`Checksum mismatch for ${entry.path}`);
+      }
+    }
+
+    return archive;
+  }
+
+  // Archive path -> absolute target, refusing anything outside a source root
+  resolveTarget(archivePath) {
+    const [source, ...rest] = archivePath.split('/');
+    const config = this.config.sources[source];
+    if (!config || rest.length === 0) {
+      throw new Error(`Unknown backup entry: ${archivePath}`);
+    }
+
+    const root = path.resolve(config.root);
+    const target = path.resolve(root, ...rest);
+    if (!target.startsWith(`${root}${path.sep}`)) {
+      throw new Error(`Backup entry escapes its directory: ${archivePath}`);
+    }
+    return target;
+  }
+
+  /**
+   * Restore a backup. Files in the archive are created or overwritten;
+   * files that are not in it are left alone. With dryRun nothing is written
+   * and the returned plan shows what would change.
+   */
+  async restoreBackup(name, { dryRun = false, restoredBy = null } = {}) {
+    if (this.running) {
+      throw new Error('A backup or restore is already running');
+    }
+
+    const { manifest, files } = await this.readBackup(name);
+
+    const plan = [];
+    for (const entry of manifest.files) {
+      const target = this.resolveTarget(entry.path);
+      let action = 'create';
+      try {
+        const current = await fs.readFile(target, 'utf8');
+        action =
+          checksum(current) === entry.sha256 ? 'unchanged' : 'overwrite';
+      } catch (error) {
+        if (error.code !== 'ENOENT') throw error;
+      }
+      plan.push({ path: entry.path, action, size: entry.size });
+    }
+
+    const summary = {
+      backup: name,
+      formatVersion: manifest.formatVersion,
+      createdAt: manifest.createdAt,
+      dryRun,
+      changes: plan.filter((item) => item.action !== 'unchanged').length,
+      plan,
+    };
+
+    if (dryRun) {
+      return summary;
+    }
+
+    // Snapshot the current state first so a bad restore can be undone
+    const safety = await this.createBackup({
+      createdBy: restoredBy,
+      note: `Automatic snapshot before restoring ${name}`,
+      label: 'pre-restore',
+    });
+
+    this.running = true;
+    try {
+      const changed = plan.filter((entry) => entry.action !== 'unchanged');
+      for (const item of changed) {
+        const target = this.resolveTarget(item.path);
+        const temporary = `${target}.restore-${process.pid}`;
+
+        await fs.mkdir(path.dirname(target), { recursive: true });
+        await fs.writeFile(temporary, files[item.path]);
+        await fs.rename(temporary, target);
+      }
+    } finally {
+      this.running = false;
+    }
+
+    const result = {
+      ...summary,
+      safetyBackup: safety.name,
+      // Managers hold their data in memory and only read it at startup
+      restartRequired: true,
+    };
+
+    this.emit('backup:restored', { ...result, restoredBy });
+    this.logger.warn(`Backup restored: ${name} (${summary.changes} files)`);
+    return result;
+  }
+}
+
+export { BackupManager, BACKUP_FORMAT_VERSION, DEFAULT_SOURCES };
@@ -0,0 +1,128 @@
+/**
+ * Tests for Backup Manager
+ */
+
+import { BackupManager, BACKUP_FORMAT_VERSION } from './backup-manager.js';
+import { promises as fs } from 'fs';
+import crypto from 'crypto';
+import os from 'os';
+import path from 'path';
+import { promisify } from 'util';
+import { gzip } from 'zlib';
+
+const gzipAsync = promisify(gzip);
+
+describe('BackupManager', () => {
+  let dir;
+  let dataRoot;
+  let backupManager;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'backups-'));
+    dataRoot = path.join(dir, 'data');
+    await fs.mkdir(dataRoot);
+    backupManager = new BackupManager({
+      target: path.join(dir, 'backups'),
+      sources: {
+        data: { root: dataRoot, include: /\.json$/, exclude: [] },
+      },
+    });
+  });
+
+  afterEach(async () => {
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  // Writes an archive by hand, as a tampered or foreign backup would be
+  const writeArchive = async (name, files) => {
+    const manifest = {
+      formatVersion: BACKUP_FORMAT_VERSION,
+      name,
+      files: Object.entries(files).map(([file, content]) => ({
+        path: file,
+        size: Buffer.byteLength(content),
+        sha256: crypto.createHash('sha256').update(content).digest('hex'),
+      })),
+    };
+    await backupManager.store.put(
+      name,
+      await gzipAsync(JSON.stringify({ manifest, files }))
+    );
+  };
+
+  describe('resolveTarget', () => {
+    it('should resolve entries inside their source root', () => {
+      expect(backupManager.resolveTarget('data/users.json')).toBe(
+        path.join(dataRoot, 'users.json')
+      );
+      expect(backupManager.resolveTarget('data/a/../b.json')).toBe(
+        path.join(dataRoot, 'b.json')
+      );
+    });
+
+    it('should reject entries that escape their source root', () => {
+      for (const entry of [
+        'data/../outside.json',
+        'data/a/../../outside.json',
+        'data/..',
+      ]) {
+        expect(() => backupManager.resolveTarget(entry)).toThrow(
+          'Backup entry escapes its directory'
+        );
+      }
+    });
+
+    it('should keep absolute-looking entries under the root', () => {
+      expect(backupManager.resolveTarget('data//etc/passwd')).toBe(
+        path.join(dataRoot, 'etc', 'passwd')
+      );
+    });
+
+    it('should not treat a sibling with the same prefix as inside', () => {
+      const sibling = `${path.basename(dataRoot)}-other/x.json`;
+
+      expect(() => backupManager.resolveTarget(`data/../${sibling}`)).toThrow(
+        'Backup entry escapes its directory'
+      );
+    });
+
+    it('should reject unknown sources and bare source names', () => {
+      expect(() => backupManager.resolveTarget('secrets/key.json')).toThrow(
+        'Unknown backup entry: secrets/key.json'
+      );
+      expect(() => backupManager.resolveTarget('data')).toThrow(
+        'Unknown backup entry: data'
+      );
+    });
+  });
+
+  describe('restoreBackup', () => {
+    it('should restore files from a backup', async () => {
+      await fs.writeFile(path.join(dataRoot, 'users.json'), '[1]');
+      const { name } = await backupManager.createBackup();
+      await fs.writeFile(path.join(dataRoot, 'users.json'), '[2]');
+
+      const result = await backupManager.restoreBackup(name);
+
+      expect(result.changes).toBe(1);
+      expect(await fs.readFile(path.join(dataRoot, 'users.json'), 'utf8')).toBe(
+        '[1]'
+      );
+    });
+
+    it('should refuse archives that write outside a source', async () => {
+      await writeArchive('kaskman-backup-evil.json.gz', {
+        'data/../escaped.json': '{}',
+      });
+
+      await expect(
+        backupManager.restoreBackup('kaskman-backup-evil.json.gz', {
+          dryRun: true,
+        })
+      ).rejects.toThrow('Backup entry escapes its directory');
+      await expect(
+        fs.access(path.join(dir, 'escaped.json'))
+      ).rejects.toThrow();
+    });
+  });
+});
@@ -0,0 +1,243 @@
+/**
+ * Backup Storage
+ * Where backup archives live: a local directory or an S3-compatible bucket
+ * (signed with AWS Signature V4, no SDK required)
+ */
+
+import { promises as fs } from 'fs';
+import path from 'path';
+import crypto from 'crypto';
+
+// Archive names are generated by BackupManager; anything else is rejected so
+// a name can never escape the target directory or prefix
+const ARCHIVE_NAME = /^[A-Za-z0-9._-]+\.json\.gz$/;
+
+function assertArchiveName(name) {
+  if (!ARCHIVE_NAME.test(name)) {
+    throw new Error(`Invalid backup name: ${name}`);
+  }
+}
+
+class LocalBackupStore {
+  constructor(directory) {
+    this.type = 'local';
+    this.directory = directory;
+  }
+
+  describe() {
+    return this.directory;
+  }
+
+  async put(name, buffer) {
+    assertArchiveName(name);
+    await fs.mkdir(this.directory, { recursive: true });
+    await fs.writeFile(path.join(this.directory, name), buffer);
+  }
+
+  async get(name) {
+    assertArchiveName(name);
+    try {
+      return await fs.readFile(path.join(this.directory, name));
+    } catch (error) {
+      if (error.code === 'ENOENT') {
+        throw new Error(`Backup not found: ${name}`);
+      }
+      throw error;
+    }
+  }
+
+  async list() {
+    let files;
+    try {
+      files = await fs.readdir(this.directory);
+    } catch (error) {
+      if (error.code === 'ENOENT') return [];
+      throw error;
+    }
+
+    const backups = [];
+    for (const name of files.filter((file) => ARCHIVE_NAME.test(file))) {
+      const stats = await fs.stat(path.join(this.directory, name));
+      backups.push({
+        name,
+        size: stats.size,
+        modifiedAt: stats.mtime.toISOString(),
+      });
+    }
+    return backups;
+  }
+
+  async delete(name) {
+    assertArchiveName(name);
+    await fs.unlink(path.join(this.directory, name));
+  }
+}
+
+const sha256 = (data) =>
+  crypto.createHash('sha256').update(data).digest('hex');
+const hmac = (key, data) =>
+  crypto.createHmac('sha256', key).update(data).digest();
+
+// RFC 3986 encoding as S3 expects it; "/" is kept in object keys
+function encodeS3(value, keepSlash = false) {
+  const encoded = encodeURIComponent(value).replace(
+    /[!'()*]/g,
+    (char) => `%${char.charCodeAt(0).toString(16).toUpperCase()}`
+  );
+  return keepSlash ? encoded.replace(/%2F/g, '/') : encoded;
+}
+
+class S3BackupStore {
+  constructor({ bucket, prefix, region, endpoint, accessKeyId, secretKey }) {
+    this.type = 's3';
+    this.bucket = bucket;
+    this.prefix = prefix ? `${prefix.replace(/\/+$/, '')}/` : '';
+    this.region = region || 'us-east-1';
+    // Path-style URLs so MinIO and other S3-compatible stores work too
+    this.endpoint = (
+      endpoint || `https://s3.${this.region}.amazonaws.com`
+    ).replace(/\/+$/, '');
+    this.accessKeyId = accessKeyId;
+    this.secretKey = secretKey;
+
+    if (!this.accessKeyId || !this.secretKey) {
+      throw new Error(
+        'S3 backups need AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY'
+      );
+    }
+  }
+
+  describe() {
+    return `s3://${this.bucket}/${this.prefix}`;
+  }
+
+  async request(method, key, { query = {}, body } = {}) {
+    const url = new URL(`${this.endpoint}/${this.bucket}`);
+    const canonicalURI = key
+      ? `${url.pathname}/${encodeS3(key, true)}`
+      : url.pathname;
+    const canonicalQuery = Object.keys(query)
+      .sort()
+      .map((name) => `${encodeS3(name)}=${encodeS3(query[name])}`)
+      .join('&');
+
+    const amzDate = new Date().toISOString().replace(/[:-]|\.\d{3}/g, '');
+    const date = amzDate.slice(0, 8);
+    const payloadHash = sha256(body || '');
+    const headers = {
+      host: url.host,
+      'x-amz-content-sha256': payloadHash,
+      'x-amz-date': amzDate,
+    };
+    const signedHeaders = Object.keys(headers).join(';');
+
+    const canonicalRequest = [
+      method,
+      canonicalURI,
+      canonicalQuery,
+      ...Object.entries(headers).map(([name, value]) => `${name}:${value}`),
+      '',
+      signedHeaders,
+      payloadHash,
+    ].join('\n');
+
+    const scope = `${date}/${this.region}/s3/aws4_request`;
+    const stringToSign = [
+      'AWS4-HMAC-SHA256',
+      amzDate,
+      scope,
+      sha256(canonicalRequest),
+    ].join('\n');
+
+    const signingKey = ['s3', 'aws4_request'].reduce(
+      (key, part) => hmac(key, part),
+      hmac(hmac(`AWS4${this.secretKey}`, date), this.region)
+    );
+    const signature = hmac(signingKey, stringToSign).toString('hex');
+
+    const search = canonicalQuery ? `?${canonicalQuery}` : '';
+    const response = await fetch(`${url.origin}${canonicalURI}${search}`, {
+      method,
+      headers: {
+        ...headers,
+        authorization: `AWS4-HMAC-SHA256 Credential=${this.accessKeyId}/${scope}, SignedHeaders=${signedHeaders}, Signature=${signature}`,
+      },
+      body,
+    });
+
+    if (response.status === 404) {
+      throw new Error(`Backup not found: ${key}`);
+    }
+    if (!response.ok) {
+      const detail = await response.text();
+      throw new Error(`S3 ${method} failed: ${response.status} ${detail}`);
+    }
+    return response;
+  }
+
+  async put(name, buffer) {
+    assertArchiveName(name);
+    await this.request('PUT', `${this.prefix}${name}`, { body: buffer });
+  }
+
+  async get(name) {
+    assertArchiveName(name);
+    const response = await this.request('GET', `${this.prefix}${name}`);
+    return Buffer.from(await response.arrayBuffer());
+  }
+
+  async list() {
+    const backups = [];
+    let token;
+
+    do {
+      const query = { 'list-type': '2', prefix: this.prefix };
+      if (token) query['continuation-token'] = token;
+
+      const xml = await (await this.request('GET', '', { query })).text();
+      const objects = xml.matchAll(/<Contents>(.*?)<\/Contents>/gs);
+      for (const [, contents] of objects) {
+        const field = (tag) =>
+          new RegExp(`<${tag}>(.*?)</${tag}>`, 's').exec(contents)?.[1];
+        const name = field('Key').slice(this.prefix.length);
+        if (ARCHIVE_NAME.test(name)) {
+          backups.push({
+            name,
+            size: Number(field('Size')),
+            modifiedAt: field('LastModified'),
+          });
+        }
+      }
+
+      token = /<NextContinuationToken>(.*?)<\/NextContinuationToken>/.exec(
+        xml
+      )?.[1];
+    } while (token);
+
+    return backups;
+  }
+
+  async delete(name) {
+    assertArchiveName(name);
+    await this.request('DELETE', `${this.prefix}${name}`);
+  }
+}
+
+// "s3://bucket/prefix" selects S3; anything else is a local directory
+function createBackupStore(target, env = process.env) {
+  const match = /^s3:\/\/([^/]+)\/?(.*)$/.exec(target);
+  if (!match) {
+    return new LocalBackupStore(target);
+  }
+
+  return new S3BackupStore({
+    bucket: match[1],
+    prefix: match[2],
+    region: env.AWS_REGION,
+    endpoint: env.S3_ENDPOINT,
+    accessKeyId: env.AWS_ACCESS_KEY_ID,
+    secretKey: env.AWS_SECRET_ACCESS_KEY,
+  });
+}
+
+export { LocalBackupStore, S3BackupStore, createBackupStore, ARCHIVE_NAME };
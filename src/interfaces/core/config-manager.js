@@ -7,16 +7,67 @@ import { promises as fs } from 'fs';
 import path from 'path';
 import { Logger } from './logger.js';
 
+// Environment variables are named ENV_PREFIX + the dotted path in upper
+// snake case, e.g. server.port -> KASKMAN_SERVER_PORT
+const ENV_PREFIX = 'KASKMAN_';
+
+// Validation rules keyed by dotted configuration path
+const CONFIG_SCHEMA = {
+  environment: {
+    type: 'string',
+    required: true,
+    enum: ['development', 'test', 'staging', 'production'],
+  },
+  'server.port': { type: 'integer', required: true, min: 1, max: 65535 },
+  'server.host': { type: 'string', required: true },
+  'database.path': { type: 'string' },
+  'database.url': { type: 'string' },
+  'logging.level': {
+    type: 'string',
+    enum: ['error', 'warn', 'info', 'debug', 'trace'],
+  },
+  'logging.file': { type: 'string' },
+  'security.sessionTimeout': { type: 'integer', min: 60000 },
+  'security.jwtSecret': {
+    type: 'string',
+    deprecated: 'ignored by AuthManager; set JWT_SECRET instead',
+  },
+};
+
+class ConfigurationError extends Error {
+  constructor(source, errors) {
+    super(
+      `Invalid configuration in ${source}:\n` +
+        errors
+          .map(
+            (e) =>
+              `  - ${e.path}${e.location ? ` (${e.location})` : ''}: ` +
+              e.message
+          )
+          .join('\n')
+    );
+    this.name = 'ConfigurationError';
+    this.errors = errors;
+  }
+}
+
 class ConfigManager {
   constructor(config = {}) {
     this.config = {
       configFile: config.configFile || './config/app.json',
       environment: config.environment || process.env.NODE_ENV || 'development',
+      overrides: config.overrides || {},
+      env: config.env || process.env,
       ...config,
     };
 
     this.logger = new Logger('ConfigManager');
     this.configuration = {};
+    this.configurationText = '';
+    this.environmentOverrides = {};
+    this.flagOverrides = {};
+    this.sources = {}; // dotted key -> env var or flag that set it
+    this.resolvedConfiguration = {};
     this.watchers = new Map();
   }
 
@@ -34,6 +85,7 @@ class ConfigManager {
     try {
       const configData = await fs.readFile(this.config.configFile, 'utf8');
       this.configuration = JSON.parse(configData);
+      this.configurationText = configData;
       this.logger.info(`Loaded configuration from ${this.config.configFile}`);
     } catch (error) {
       if (error.code === 'ENOENT') {
@@ -44,6 +96,232 @@ class ConfigManager {
         throw error;
       }
     }
+
+    this.sources = {};
+    this.environmentOverrides = await this.loadEnvironmentOverrides();
+    this.flagOverrides = this.loadFlagOverrides();
+    this.resolve();
+    this.assertValid();
+  }
+
+  // Precedence: overrides (flags) > environment > config file > defaults
+  resolve() {
+    const resolved = JSON.parse(JSON.stringify(this.configuration));
+    const layers = [this.environmentOverrides, this.flagOverrides];
+
+    for (const layer of layers) {
+      for (const [key, value] of Object.entries(layer)) {
+        this.assign(resolved, key, value);
+      }
+    }
+
+    this.resolvedConfiguration = resolved;
+    return resolved;
+  }
+
+  async loadEnvironmentOverrides() {
+    const env = this.config.env;
+    const keys = new Set([
+      ...Object.keys(CONFIG_SCHEMA),
+      ...this.leafKeys(this.configuration),
+    ]);
+    const overrides = {};
+
+    for (const key of keys) {
+      const name = this.toEnvName(key);
+      let raw = env[name];
+      let source = name;
+
+      if (raw === undefined && env[`${name}_FILE`]) {
+        source = `${name}_FILE`;
+        try {
+          raw = (await fs.readFile(env[`${name}_FILE`], 'utf8')).trim();
+        } catch (error) {
+          throw new Error(
+            `Failed to read ${name}_FILE (${env[`${name}_FILE`]}): ${error.message}`
+          );
+        }
+      }
+
+      if (raw !== undefined) {
+        overrides[key] = this.coerce(key, raw);
+        this.sources[key] = source;
+        this.logger.debug(`Configuration ${key} overridden by ${source}`);
+      }
+    }
+
+    return overrides;
+  }
+
+  // Command-line values arrive as strings and are coerced like env values
+  loadFlagOverrides() {
+    const overrides = {};
+
+    for (const [key, value] of Object.entries(this.config.overrides)) {
+      overrides[key] =
+        typeof value === 'string' ? this.coerce(key, value) : value;
+      this.sources[key] = `--set ${key}`;
+    }
+
+    return overrides;
+  }
+
+  toEnvName(key) {
+    return (
+      ENV_PREFIX +
+      key
+        .split('.')
+        .map((part) => part.replace(/([a-z0-9])([A-Z])/g, '$1_$2'))
+        .join('_')
+        .toUpperCase()
+    );
+  }
+
+  coerce(key, raw) {
+    const rule = CONFIG_SCHEMA[key];
+    const current = this.lookup(this.configuration, key);
+    const type = rule?.type || typeof current;
+
+    switch (type) {
+      case 'integer':
+      case 'number': {
+        const number = Number(raw);
+        // Leave unparseable values as strings so validation reports them
+        return raw.trim() !== '' && !Number.isNaN(number) ? number : raw;
+      }
+      case 'boolean':
+        return raw === 'true' ? true : raw === 'false' ? false : raw;
+      case 'object':
+        try {
+          return JSON.parse(raw);
+        } catch (error) {
+          return raw;
+        }
+      default:
+        return raw;
+    }
+  }
+
+  leafKeys(object, prefix = '') {
+    const keys = [];
+
+    for (const [k, value] of Object.entries(object || {})) {
+      const key = prefix ? `${prefix}.${k}` : k;
+      if (value && typeof value === 'object' && !Array.isArray(value)) {
+        keys.push(...this.leafKeys(value, key));
+      } else {
+        keys.push(key);
+      }
+    }
+
+    return keys;
+  }
+
+  validate(configuration = this.resolvedConfiguration) {
+    const errors = [];
+    const warnings = [];
+
+    for (const [key, rule] of Object.entries(CONFIG_SCHEMA)) {
+      const value = this.lookup(configuration, key);
+
+      if (value === undefined) {
+        if (rule.required) {
+          errors.push({ path: key, message: 'is required' });
+        }
+        continue;
+      }
+
+      if (rule.deprecated) {
+        warnings.push({
+          path: key,
+          message: `is deprecated: ${rule.deprecated}`,
+        });
+      }
+
+      const typeOk =
+        rule.type === 'integer'
+          ? Number.isInteger(value)
+          : typeof value === rule.type;
+      if (!typeOk) {
+        errors.push({
+          path: key,
+          message: `must be ${rule.type === 'integer' ? 'an' : 'a'} ${rule.type} (got ${JSON.stringify(value)})`,
+        });
+        continue;
+      }
+
+      if (rule.enum && !rule.enum.includes(value)) {
+        errors.push({
+          path: key,
+          message: `must be one of ${rule.enum.join(', ')} (got ${JSON.stringify(value)})`,
+        });
+      }
+
+      if (rule.min !== undefined && value < rule.min) {
+        errors.push({
+          path: key,
+          message: `must be >= ${rule.min} (got ${value})`,
+        });
+      }
+
+      if (rule.max !== undefined && value > rule.max) {
+        errors.push({
+          path: key,
+          message: `must be <= ${rule.max} (got ${value})`,
+        });
+      }
+    }
+
+    for (const entry of [...errors, ...warnings]) {
+      const location = this.locate(entry.path);
+      if (location) entry.location = location;
+    }
+
+    return { valid: errors.length === 0, errors, warnings };
+  }
+
+  /**
+   * Where a key's value came from: the env var or flag that overrode it, or
+   * file:line of the key in the config file. Null for missing keys.
+   */
+  locate(key) {
+    if (this.sources[key]) return this.sources[key];
+
+    const lines = this.configurationText.split('\n');
+    let line = 0;
+    for (const part of key.split('.')) {
+      const name = JSON.stringify(part);
+      while (line < lines.length && !lines[line].includes(`${name}:`)) line++;
+      if (line === lines.length) return null;
+    }
+
+    return `${this.config.configFile}:${line + 1}`;
+  }
+
+  assertValid() {
+    const { errors, warnings } = this.validate();
+
+    for (const warning of warnings) {
+      this.logger.warn(`Configuration ${warning.path} ${warning.message}`);
+    }
+
+    if (errors.length > 0) {
+      throw new ConfigurationError(this.config.configFile, errors);
+    }
+  }
+
+  lookup(configuration, key) {
+    let value = configuration;
+
+    for (const k of key.split('.')) {
+      if (value && typeof value === 'object' && k in value) {
+        value = value[k];
+      } else {
+        return undefined;
+      }
+    }
+
+    return value;
   }
 
   async createDefaultConfiguration() {
@@ -61,12 +339,12 @@ class ConfigManager {
         file: './logs/app.log',
       },
       security: {
-        jwtSecret: 'your-secret-key-here',
         sessionTimeout: 86400000,
       },
     };
 
     this.configuration = defaultConfig;
+    this.configurationText = JSON.stringify(defaultConfig, null, 2);
     await this.saveConfiguration();
   }
 
@@ -88,23 +366,17 @@ class ConfigManager {
   }
 
   get(key, defaultValue = null) {
-    const keys = key.split('.');
-    let value = this.configuration;
-
-    for (const k of keys) {
-      if (value && typeof value === 'object' && k in value) {
-        value = value[k];
-      } else {
-        return defaultValue;
-      }
-    }
-
-    return value;
+    const value = this.lookup(this.resolvedConfiguration, key);
+    return value === undefined ? defaultValue : value;
   }
 
   set(key, value) {
+    this.assign(this.configuration, key, value);
+    this.resolve();
+  }
+
+  assign(target, key, value) {
     const keys = key.split('.');
-    let target = this.configuration;
 
     for (let i = 0; i < keys.length - 1; i++) {
       const k = keys[i];
@@ -122,7 +394,7 @@ class ConfigManager {
   }
 
   getAll() {
-    return { ...this.configuration };
+    return { ...this.resolvedConfiguration };
   }
 
   async reload() {
@@ -134,4 +406,4 @@ class ConfigManager {
   }
 }
 
-export { ConfigManager };
+export { ConfigManager, ConfigurationError, CONFIG_SCHEMA, ENV_PREFIX };
@@ -0,0 +1,139 @@
+/**
+ * Tests for Configuration Manager
+ */
+
+import { ConfigManager } from './config-manager.js';
+import { AuthManager } from './auth-manager.js';
+import { promises as fs } from 'fs';
+import os from 'os';
+import path from 'path';
+
+describe('ConfigManager', () => {
+  let dir;
+  let configFile;
+
+  const load = async (options = {}) => {
+    const configManager = new ConfigManager({
+      configFile,
+      env: {},
+      ...options,
+    });
+    await configManager.initialize();
+    return configManager;
+  };
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'config-manager-'));
+    configFile = path.join(dir, 'app.json');
+  });
+
+  afterEach(async () => {
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  const writeConfig = (configuration) =>
+    fs.writeFile(configFile, JSON.stringify(configuration, null, 2));
+
+  describe('loadConfiguration', () => {
+    it('should write defaults when the file is missing', async () => {
+      const configManager = await load();
+
+      expect(configManager.get('server.port')).toBe(8080);
+      expect(JSON.parse(await fs.readFile(configFile, 'utf8'))).toEqual(
+        configManager.configuration
+      );
+    });
+
+    it('should report invalid keys with their file and line', async () => {
+      await writeConfig({
+        environment: 'development',
+        server: { host: 'localhost', port: 70000 },
+      });
+
+      await expect(load()).rejects.toThrow(
+        `server.port (${configFile}:5): must be <= 65535 (got 70000)`
+      );
+    });
+
+    it('should report the variable behind an invalid override', async () => {
+      await expect(
+        load({ env: { KASKMAN_LOGGING_LEVEL: 'verbose' } })
+      ).rejects.toThrow('logging.level (KASKMAN_LOGGING_LEVEL): must be one');
+    });
+
+    it('should list every missing required key', async () => {
+      await writeConfig({ server: {} });
+
+      try {
+        await load();
+        throw new Error('expected load to fail');
+      } catch (error) {
+        expect(error.name).toBe('ConfigurationError');
+        expect(error.errors.map((e) => e.path)).toEqual([
+          'environment',
+          'server.port',
+          'server.host',
+        ]);
+      }
+    });
+  });
+
+  describe('overrides', () => {
+    it('should apply KASKMAN_* variables where consumers read them', async () => {
+      const configManager = await load({
+        env: { KASKMAN_SECURITY_SESSION_TIMEOUT: '120000' },
+      });
+      const authManager = new AuthManager({ configManager });
+
+      authManager.applyConfiguration();
+
+      expect(configManager.get('security.sessionTimeout')).toBe(120000);
+      expect(authManager.config.sessionTimeout).toBe(120000);
+    });
+
+    it('should apply *_FILE secrets where consumers read them', async () => {
+      const secret = path.join(dir, 'session-timeout');
+      await fs.writeFile(secret, '300000\n');
+
+      const configManager = await load({
+        env: { KASKMAN_SECURITY_SESSION_TIMEOUT_FILE: secret },
+      });
+      const authManager = new AuthManager({ configManager });
+
+      authManager.applyConfiguration();
+
+      expect(authManager.config.sessionTimeout).toBe(300000);
+    });
+
+    it('should prefer the plain variable over its _FILE form', async () => {
+      const secret = path.join(dir, 'port');
+      await fs.writeFile(secret, '9001');
+
+      const configManager = await load({
+        env: {
+          KASKMAN_SERVER_PORT: '9000',
+          KASKMAN_SERVER_PORT_FILE: secret,
+        },
+      });
+
+      expect(configManager.get('server.port')).toBe(9000);
+    });
+
+    it('should coerce --set values and let them win over env', async () => {
+      const configManager = await load({
+        env: { KASKMAN_SERVER_PORT: '9000' },
+        overrides: { 'server.port': '9100', 'logging.level': 'debug' },
+      });
+
+      expect(configManager.get('server.port')).toBe(9100);
+      expect(configManager.get('logging.level')).toBe('debug');
+      expect(configManager.locate('server.port')).toBe('--set server.port');
+    });
+
+    it('should blame the flag for an invalid --set value', async () => {
+      await expect(
+        load({ overrides: { 'server.port': 'http' } })
+      ).rejects.toThrow('server.port (--set server.port): must be an integer');
+    });
+  });
+});
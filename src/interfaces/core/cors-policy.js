@@ -0,0 +1,238 @@
+/**
+ * CORS Policy
+ * Per-origin capability grants (methods, route prefixes, credentials),
+ * evaluated per request and editable at runtime
+ */
+
+import { EventEmitter } from 'events';
+import { promises as fs } from 'fs';
+import path from 'path';
+import crypto from 'crypto';
+import { Logger } from './logger.js';
+
+const CORS_METHODS = ['GET', 'HEAD', 'POST', 'PUT', 'PATCH', 'DELETE'];
+
+class CorsPolicyManager extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      grantsFile: config.grantsFile || './data/cors-grants.json',
+      defaultMaxAge: config.defaultMaxAge || 600, // seconds
+      ...config,
+    };
+
+    this.logger = new Logger('CorsPolicy');
+    this.grants = new Map();
+  }
+
+  async initialize() {
+    try {
+      await this.loadGrants();
+      this.logger.info('CorsPolicy initialized successfully');
+    } catch (error) {
+      this.logger.error('Failed to initialize CorsPolicy:', error);
+      throw error;
+    }
+  }
+
+  async loadGrants() {
+    try {
+      const data = await fs.readFile(this.config.grantsFile, 'utf8');
+
+      for (const grant of JSON.parse(data)) {
+        this.grants.set(grant.id, grant);
+      }
+
+      this.logger.info(`Loaded ${this.grants.size} CORS grants`);
+    } catch (error) {
+      if (error.code !== 'ENOENT') {
+        this.logger.error('Failed to load CORS grants:', error);
+        throw error;
+      }
+    }
+  }
+
+  async saveGrants() {
+    try {
+      await fs.mkdir(path.dirname(this.config.grantsFile), { recursive: true });
+      await fs.writeFile(
+        this.config.grantsFile,
+        JSON.stringify(Array.from(this.grants.values()), null, 2)
+      );
+    } catch (error) {
+      this.logger.error('Failed to save CORS grants:', error);
+      throw error;
+    }
+  }
+
+  validateGrant(grant) {
+    let origin;
+    try {
+      origin = new URL(grant.origin).origin;
+    } catch (error) {
+      throw new Error(`Invalid origin: ${grant.origin}`);
+    }
+    if (origin !== grant.origin) {
+      throw new Error(
+        `Origin must be scheme://host[:port], got ${grant.origin}`
+      );
+    }
+
+    if (!Array.isArray(grant.methods) || grant.methods.length === 0) {
+      throw new Error('At least one method is required');
+    }
+    const invalid = grant.methods.filter((m) => !CORS_METHODS.includes(m));
+    if (invalid.length > 0) {
+      throw new Error(`Invalid methods: ${invalid.join(', ')}`);
+    }
+
+    if (
+      !Array.isArray(grant.routes) ||
+      grant.routes.length === 0 ||
+      !grant.routes.every((route) => route.startsWith('/'))
+    ) {
+      throw new Error('routes must be a non-empty list of path prefixes');
+    }
+
+    const duplicate = Array.from(this.grants.values()).find(
+      (other) => other.origin === grant.origin && other.id !== grant.id
+    );
+    if (duplicate) {
+      throw new Error(`A grant for ${grant.origin} already exists`);
+    }
+  }
+
+  async createGrant(data, createdBy) {
+    const grant = {
+      id: crypto.randomUUID(),
+      origin: data.origin,
+      methods: (data.methods || ['GET']).map((m) => m.toUpperCase()),
+      routes: data.routes,
+      credentials: data.credentials === true,
+      maxAge: data.maxAge ?? this.config.defaultMaxAge,
+      note: data.note || '',
+      createdBy: createdBy || null,
+      createdAt: new Date().toISOString(),
+      updatedAt: new Date().toISOString(),
+    };
+
+    this.validateGrant(grant);
+
+    this.grants.set(grant.id, grant);
+    await this.saveGrants();
+
+    this.emit('grant:created', grant);
+    this.logger.info(`CORS grant created for ${grant.origin}`);
+    return grant;
+  }
+
+  async updateGrant(grantId, updates) {
+    const grant = this.getGrant(grantId);
+    const { id: _id, createdAt: _createdAt, ...allowed } = updates;
+
+    const updated = {
+      ...grant,
+      ...allowed,
+      updatedAt: new Date().toISOString(),
+    };
+    updated.methods = updated.methods.map((m) => m.toUpperCase());
+    updated.credentials = updated.credentials === true;
+
+    this.validateGrant(updated);
+
+    this.grants.set(grantId, updated);
+    await this.saveGrants();
+
+    this.emit('grant:updated', updated);
+    return updated;
+  }
+
+  async deleteGrant(grantId) {
+    const grant = this.getGrant(grantId);
+
+    this.grants.delete(grantId);
+    await this.saveGrants();
+
+    this.emit('grant:deleted', grant);
+    return grant;
+  }
+
+  getGrant(grantId) {
+    const grant = this.grants.get(grantId);
+    if (!grant) {
+      throw new Error(`CORS grant not found: ${grantId}`);
+    }
+    return grant;
+  }
+
+  listGrants() {
+    return Array.from(this.grants.values());
+  }
+
+  findGrant(origin) {
+    return Array.from(this.grants.values()).find(
+      (grant) => grant.origin === origin
+    );
+  }
+
+  allows(grant, method, requestPath) {
+    // HEAD rides along with GET, as it does for same-origin requests
+    const methodAllowed =
+      grant.methods.includes(method) ||
+      (method === 'HEAD' && grant.methods.includes('GET'));
+
+    return (
+      methodAllowed &&
+      grant.routes.some(
+        (route) =>
+          requestPath === route ||
+          requestPath.startsWith(route.endsWith('/') ? route : `${route}/`)
+      )
+    );
+  }
+
+  // Origins with a grant are held to it; all others go to the fallback
+  // (the server-wide cors() options)
+  middleware(fallback) {
+    return (req, res, next) => {
+      const origin = req.get('Origin');
+      const grant = origin && this.findGrant(origin);
+      if (!grant) return fallback(req, res, next);
+
+      const requestedMethod = req.get('Access-Control-Request-Method');
+      const preflight = req.method === 'OPTIONS' && Boolean(requestedMethod);
+      const method = preflight ? requestedMethod.toUpperCase() : req.method;
+
+      res.vary('Origin');
+
+      if (!this.allows(grant, method, req.path)) {
+        this.logger.warn(`CORS grant denied ${method} ${req.path}`, {
+          origin,
+        });
+        return res.status(403).json({
+          error: `Origin ${origin} is not allowed to ${method} ${req.path}`,
+          code: 'CORS_CAPABILITY_DENIED',
+        });
+      }
+
+      res.set('Access-Control-Allow-Origin', origin);
+      if (grant.credentials) {
+        res.set('Access-Control-Allow-Credentials', 'true');
+      }
+
+      if (preflight) {
+        res.set('Access-Control-Allow-Methods', grant.methods.join(', '));
+        const headers = req.get('Access-Control-Request-Headers');
+        if (headers) {
+          res.set('Access-Control-Allow-Headers', headers);
+        }
+        res.set('Access-Control-Max-Age', String(grant.maxAge));
+        return res.sendStatus(204);
+      }
+
+      next();
+    };
+  }
+}
+
+export { CorsPolicyManager, CORS_METHODS };
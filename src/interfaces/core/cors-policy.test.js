@@ -0,0 +1,191 @@
+/**
+ * Tests for CORS Policy
+ */
+
+import { CorsPolicyManager } from './cors-policy.js';
+import { jest } from '@jest/globals';
+import { promises as fs } from 'fs';
+import os from 'os';
+import path from 'path';
+
+// Minimal Express request/response doubles for the middleware
+function mockRequest(method, requestPath, headers = {}) {
+  const lower = Object.fromEntries(
+    Object.entries(headers).map(([name, value]) => [name.toLowerCase(), value])
+  );
+  return {
+    method,
+    path: requestPath,
+    get: (name) => lower[name.toLowerCase()],
+  };
+}
+
+function mockResponse() {
+  const res = { headers: {}, statusCode: 200, body: null };
+  res.set = (name, value) => {
+    res.headers[name] = value;
+    return res;
+  };
+  res.vary = jest.fn();
+  res.status = (code) => {
+    res.statusCode = code;
+    return res;
+  };
+  res.json = (body) => {
+    res.body = body;
+    return res;
+  };
+  res.sendStatus = (code) => {
+    res.statusCode = code;
+    return res;
+  };
+  return res;
+}
+
+describe('CorsPolicyManager', () => {
+  let dir;
+  let policy;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'cors-'));
+    policy = new CorsPolicyManager({
+      grantsFile: path.join(dir, 'cors-grants.json'),
+    });
+  });
+
+  afterEach(async () => {
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  describe('createGrant', () => {
+    it('should normalise methods and default maxAge', async () => {
+      const grant = await policy.createGrant({
+        origin: 'https://dashboard.example.com',
+        methods: ['get', 'post'],
+        routes: ['/api/projects'],
+      });
+
+      expect(grant.methods).toEqual(['GET', 'POST']);
+      expect(grant.credentials).toBe(false);
+      expect(grant.maxAge).toBe(600);
+    });
+
+    it('should reject origins with a path', async () => {
+      await expect(
+        policy.createGrant({
+          origin: 'https://example.com/app',
+          routes: ['/api'],
+        })
+      ).rejects.toThrow('Origin must be scheme://host[:port]');
+    });
+
+    it('should reject unknown methods and relative routes', async () => {
+      await expect(
+        policy.createGrant({
+          origin: 'https://example.com',
+          methods: ['TRACE'],
+          routes: ['/api'],
+        })
+      ).rejects.toThrow('Invalid methods: TRACE');
+      await expect(
+        policy.createGrant({ origin: 'https://example.com', routes: ['api'] })
+      ).rejects.toThrow('routes must be a non-empty list');
+    });
+
+    it('should allow one grant per origin', async () => {
+      const data = { origin: 'https://example.com', routes: ['/api'] };
+      await policy.createGrant(data);
+
+      await expect(policy.createGrant(data)).rejects.toThrow(
+        'A grant for https://example.com already exists'
+      );
+    });
+  });
+
+  describe('allows', () => {
+    const grant = { methods: ['GET'], routes: ['/api/projects'] };
+
+    it('should match route prefixes on segment boundaries', () => {
+      expect(policy.allows(grant, 'GET', '/api/projects')).toBe(true);
+      expect(policy.allows(grant, 'GET', '/api/projects/123')).toBe(true);
+      expect(policy.allows(grant, 'GET', '/api/projects-archive')).toBe(false);
+    });
+
+    it('should let HEAD ride along with GET', () => {
+      expect(policy.allows(grant, 'HEAD', '/api/projects')).toBe(true);
+      expect(policy.allows(grant, 'POST', '/api/projects')).toBe(false);
+    });
+  });
+
+  describe('middleware', () => {
+    let fallback;
+    let next;
+
+    beforeEach(async () => {
+      await policy.createGrant({
+        origin: 'https://dashboard.example.com',
+        methods: ['GET', 'POST'],
+        routes: ['/api/projects'],
+        credentials: true,
+        maxAge: 120,
+      });
+      fallback = jest.fn();
+      next = jest.fn();
+    });
+
+    it('should hand origins without a grant to the fallback', () => {
+      const req = mockRequest('GET', '/api/projects', {
+        Origin: 'https://other.example.com',
+      });
+
+      policy.middleware(fallback)(req, mockResponse(), next);
+
+      expect(fallback).toHaveBeenCalled();
+      expect(next).not.toHaveBeenCalled();
+    });
+
+    it('should allow granted requests with credentials', () => {
+      const req = mockRequest('GET', '/api/projects/1', {
+        Origin: 'https://dashboard.example.com',
+      });
+      const res = mockResponse();
+
+      policy.middleware(fallback)(req, res, next);
+
+      expect(next).toHaveBeenCalled();
+      expect(res.headers['Access-Control-Allow-Origin']).toBe(
+        'https://dashboard.example.com'
+      );
+      expect(res.headers['Access-Control-Allow-Credentials']).toBe('true');
+    });
+
+    it('should answer granted preflights', () => {
+      const req = mockRequest('OPTIONS', '/api/projects', {
+        Origin: 'https://dashboard.example.com',
+        'Access-Control-Request-Method': 'post',
+        'Access-Control-Request-Headers': 'Content-Type',
+      });
+      const res = mockResponse();
+
+      policy.middleware(fallback)(req, res, next);
+
+      expect(res.statusCode).toBe(204);
+      expect(res.headers['Access-Control-Allow-Methods']).toBe('GET, POST');
+      expect(res.headers['Access-Control-Allow-Headers']).toBe('Content-Type');
+      expect(res.headers['Access-Control-Max-Age']).toBe('120');
+    });
+
+    it('should deny methods and routes outside the grant', () => {
+      const req = mockRequest('DELETE', '/api/users/1', {
+        Origin: 'https://dashboard.example.com',
+      });
+      const res = mockResponse();
+
+      policy.middleware(fallback)(req, res, next);
+
+      expect(res.statusCode).toBe(403);
+      expect(res.body.code).toBe('CORS_CAPABILITY_DENIED');
+      expect(next).not.toHaveBeenCalled();
+    });
+  });
+});
@@ -0,0 +1,81 @@
+/**
+ * Fan-out
+ * Runs independent async queries in parallel with bounded concurrency and
+ * per-query timeouts, collecting results and failures by name. Each query
+ * gets an AbortSignal that fires when it times out, so it can stop its work.
+ */
+
+class QueryTimeoutError extends Error {
+  constructor(name, timeout) {
+    super(`Query ${name} timed out after ${timeout}ms`);
+    this.name = 'QueryTimeoutError';
+    this.query = name;
+  }
+}
+
+// Aborts the query's signal on timeout or when the caller's signal aborts
+function withTimeout(name, query, timeout, parentSignal) {
+  if (parentSignal?.aborted) {
+    return Promise.reject(parentSignal.reason);
+  }
+
+  const controller = new AbortController();
+  const onParentAbort = () => controller.abort(parentSignal.reason);
+  parentSignal?.addEventListener('abort', onParentAbort, { once: true });
+
+  const timer = setTimeout(
+    () => controller.abort(new QueryTimeoutError(name, timeout)),
+    timeout
+  );
+  const aborted = new Promise((resolve, reject) => {
+    controller.signal.addEventListener(
+      'abort',
+      () => reject(controller.signal.reason),
+      { once: true }
+    );
+  });
+
+  return Promise.race([
+    Promise.resolve().then(() => query(controller.signal)),
+    aborted,
+  ]).finally(() => {
+    clearTimeout(timer);
+    parentSignal?.removeEventListener('abort', onParentAbort);
+  });
+}
+
+// queries: { name: (signal) => Promise }; options.timeouts overrides per
+// name, and options.signal aborts every query still running.
+// Never rejects: failed or timed-out queries land in `errors`.
+async function fanOut(queries, options = {}) {
+  const concurrency = options.concurrency || 4;
+  const timeout = options.timeout || 5000;
+  const entries = Object.entries(queries);
+  const results = {};
+  const errors = {};
+  let next = 0;
+
+  const worker = async () => {
+    while (next < entries.length) {
+      const [name, query] = entries[next++];
+      try {
+        results[name] = await withTimeout(
+          name,
+          query,
+          options.timeouts?.[name] || timeout,
+          options.signal
+        );
+      } catch (error) {
+        errors[name] = error;
+      }
+    }
+  };
+
+  await Promise.all(
+    Array.from({ length: Math.min(concurrency, entries.length) }, worker)
+  );
+
+  return { results, errors };
+}
+
+export { fanOut, QueryTimeoutError };
@@ -0,0 +1,105 @@
+/**
+ * Tests for Fan-out
+ */
+
+import { fanOut, QueryTimeoutError } from './fan-out.js';
+
+const delay = (ms, value) =>
+  new Promise((resolve) => setTimeout(() => resolve(value), ms));
+
+describe('fanOut', () => {
+  it('should collect results and errors by name', async () => {
+    const { results, errors } = await fanOut({
+      cpu: async () => 42,
+      disk: async () => {
+        throw new Error('df failed');
+      },
+    });
+
+    expect(results).toEqual({ cpu: 42 });
+    expect(errors.disk.message).toBe('df failed');
+  });
+
+  it('should run at most `concurrency` queries at once', async () => {
+    let running = 0;
+    let peak = 0;
+    const query = async () => {
+      running++;
+      peak = Math.max(peak, running);
+      await delay(5);
+      running--;
+    };
+
+    await fanOut(
+      { a: query, b: query, c: query, d: query, e: query },
+      { concurrency: 2 }
+    );
+
+    expect(peak).toBe(2);
+  });
+
+  it('should time out slow queries and abort their signal', async () => {
+    let received;
+    const { results, errors } = await fanOut(
+      {
+        fast: async () => 'ok',
+        slow: (signal) => {
+          received = signal;
+          return delay(200, 'late');
+        },
+      },
+      { timeout: 20 }
+    );
+
+    expect(results).toEqual({ fast: 'ok' });
+    expect(errors.slow).toBeInstanceOf(QueryTimeoutError);
+    expect(errors.slow.query).toBe('slow');
+    expect(received.aborted).toBe(true);
+  });
+
+  it('should honour per-query timeouts', async () => {
+    const { results, errors } = await fanOut(
+      { quick: () => delay(50, 'a'), patient: () => delay(50, 'b') },
+      { timeout: 10, timeouts: { patient: 500 } }
+    );
+
+    expect(results).toEqual({ patient: 'b' });
+    expect(errors.quick).toBeInstanceOf(QueryTimeoutError);
+  });
+
+  it('should abort running queries with the caller signal', async () => {
+    const controller = new AbortController();
+    let received;
+    const pending = fanOut(
+      {
+        slow: (signal) => {
+          received = signal;
+          return delay(200);
+        },
+      },
+      { signal: controller.signal }
+    );
+
+    controller.abort(new Error('request closed'));
+    const { errors } = await pending;
+
+    expect(errors.slow.message).toBe('request closed');
+    expect(received.aborted).toBe(true);
+  });
+
+  it('should not start queries once the caller signal aborted', async () => {
+    let started = false;
+
+    const { errors } = await fanOut(
+      {
+        cpu: async () => {
+          started = true;
+        },
+      },
+      { signal: AbortSignal.abort(new Error('gone')) }
+    );
+
+    expect(started).toBe(false);
+    expect(errors.cpu.message).toBe('gone');
+  });
+});
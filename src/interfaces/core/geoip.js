@@ -0,0 +1,108 @@
+/**
+ * GeoIP
+ * Offline IPv4 geolocation from a CSV range database, so logins can be
+ * placed without calling an external service
+ */
+
+import { promises as fs } from 'fs';
+import net from 'net';
+import { Logger } from './logger.js';
+
+// "1.2.3.4" -> 16909060; IPv4-mapped IPv6 ("::ffff:1.2.3.4") is unwrapped
+function ipToInt(ip) {
+  const address = String(ip || '').replace(/^::ffff:/i, '');
+  if (!net.isIPv4(address)) return null;
+
+  return address
+    .split('.')
+    .reduce((acc, octet) => acc * 256 + Number(octet), 0);
+}
+
+function isPrivateAddress(ip) {
+  const value = ipToInt(ip);
+  if (value === null) return String(ip) === '::1';
+
+  const inRange = (start, end) =>
+    value >= ipToInt(start) && value <= ipToInt(end);
+  return (
+    inRange('10.0.0.0', '10.255.255.255') ||
+    inRange('172.16.0.0', '172.31.255.255') ||
+    inRange('192.168.0.0', '192.168.255.255') ||
+    inRange('127.0.0.0', '127.255.255.255')
+  );
+}
+
+class GeoIPResolver {
+  constructor(config = {}) {
+    this.config = {
+      // CSV rows: start,end,country[,region[,city]]; start and end are
+      // dotted IPv4 or integers. Rows that do not parse are skipped.
+      databaseFile:
+        config.databaseFile || process.env.GEOIP_DB || './data/geoip.csv',
+      ...config,
+    };
+
+    this.logger = new Logger('GeoIP');
+    this.ranges = [];
+    this.loaded = false;
+  }
+
+  async load() {
+    try {
+      const data = await fs.readFile(this.config.databaseFile, 'utf8');
+      const parseBound = (value) =>
+        /^\d+$/.test(value) ? Number(value) : ipToInt(value);
+
+      this.ranges = data
+        .split('\n')
+        .map((line) => line.trim().replace(/"/g, '').split(','))
+        .map(([start, end, country, region, city]) => ({
+          start: parseBound(start),
+          end: parseBound(end),
+          country: country || null,
+          region: region || null,
+          city: city || null,
+        }))
+        .filter((range) => range.start !== null && range.end !== null)
+        .sort((a, b) => a.start - b.start);
+
+      this.loaded = true;
+      this.logger.info(`Loaded ${this.ranges.length} GeoIP ranges`);
+    } catch (error) {
+      if (error.code !== 'ENOENT') {
+        this.logger.error('Failed to load GeoIP database:', error);
+        throw error;
+      }
+      this.logger.info('No GeoIP database found; locations will be unknown');
+    }
+  }
+
+  // Returns { country, region, city } or null when the address is unknown
+  lookup(ip) {
+    if (isPrivateAddress(ip)) {
+      return { country: 'private', region: null, city: null };
+    }
+
+    const value = ipToInt(ip);
+    if (value === null || this.ranges.length === 0) return null;
+
+    let low = 0;
+    let high = this.ranges.length - 1;
+    while (low <= high) {
+      const mid = (low + high) >> 1;
+      const range = this.ranges[mid];
+      if (value < range.start) {
+        high = mid - 1;
+      } else if (value > range.end) {
+        low = mid + 1;
+      } else {
+        const { country, region, city } = range;
+        return { country, region, city };
+      }
+    }
+
+    return null;
+  }
+}
+
+export { GeoIPResolver, ipToInt, isPrivateAddress };
@@ -0,0 +1,346 @@
+/**
+ * Incident Manager
+ * Tracks incidents raised from critical events through acknowledgment,
+ * escalation, and resolution
+ */
+
+import { EventEmitter } from 'events';
+import { promises as fs } from 'fs';
+import path from 'path';
+import crypto from 'crypto';
+import { Logger } from './logger.js';
+
+const SEVERITIES = ['critical', 'major', 'minor'];
+// Shown on the public status page until an operator writes a public title;
+// internal titles can name projects, processes and hosts
+const PUBLIC_TITLES = {
+  critical: 'Service outage',
+  major: 'Service disruption',
+  minor: 'Degraded performance',
+};
+
+class IncidentManager extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      incidentsFile: config.incidentsFile || './data/incidents.json',
+      escalationWindow: config.escalationWindow || 15 * 60 * 1000, // 15 minutes
+      maxEscalationLevel: config.maxEscalationLevel || 3,
+      checkInterval: config.checkInterval || 60 * 1000, // 1 minute
+      ...config,
+    };
+
+    this.logger = new Logger('IncidentManager');
+    this.incidents = new Map();
+  }
+
+  async initialize() {
+    try {
+      await this.loadIncidents();
+
+      this.escalationTimer = setInterval(
+        () => this.checkEscalations(),
+        this.config.checkInterval
+      );
+
+      this.logger.info('IncidentManager initialized successfully');
+    } catch (error) {
+      this.logger.error('Failed to initialize IncidentManager:', error);
+      throw error;
+    }
+  }
+
+  async loadIncidents() {
+    try {
+      const incidentsData = await fs.readFile(
+        this.config.incidentsFile,
+        'utf8'
+      );
+
+      for (const incident of JSON.parse(incidentsData)) {
+        this.incidents.set(incident.id, incident);
+      }
+
+      this.logger.info(`Loaded ${this.incidents.size} incidents`);
+    } catch (error) {
+      if (error.code !== 'ENOENT') {
+        this.logger.error('Failed to load incidents:', error);
+        throw error;
+      }
+    }
+  }
+
+  async saveIncidents() {
+    try {
+      await fs.mkdir(path.dirname(this.config.incidentsFile), {
+        recursive: true,
+      });
+      await fs.writeFile(
+        this.config.incidentsFile,
+        JSON.stringify(Array.from(this.incidents.values()), null, 2)
+      );
+    } catch (error) {
+      this.logger.error('Failed to save incidents:', error);
+      throw error;
+    }
+  }
+
+  // Wire up the event sources that should raise incidents
+  attach({ statusMonitor, processManager } = {}) {
+    if (statusMonitor) {
+      statusMonitor.on('alert', (alert) => {
+        if (alert.level !== 'critical') return;
+
+        this.openIncident({
+          key: `alert:${alert.type}`,
+          title: alert.message,
+          severity: 'critical',
+          source: 'status-monitor',
+          details: alert,
+        }).catch((error) =>
+          this.logger.error('Failed to open incident from alert:', error)
+        );
+      });
+    }
+
+    if (processManager) {
+      processManager.on('process:exit', (processId, code, signal) => {
+        // Clean exits and operator stops (SIGTERM/SIGKILL) are not crashes
+        if (code === 0 || signal === 'SIGTERM' || signal === 'SIGKILL') {
+          return;
+        }
+
+        const processInfo = processManager.processes?.get(processId);
+        this.openIncident({
+          key: `crash:${processId}`,
+          title: `Process ${processId} crashed (code ${code}, signal ${signal})`,
+          severity: 'major',
+          source: 'process-manager',
+          projectId: processInfo?.projectId || null,
+          details: { processId, code, signal },
+        }).catch((error) =>
+          this.logger.error('Failed to open incident from crash:', error)
+        );
+      });
+    }
+  }
+
+  async openIncident(data) {
+    if (!data.title) {
+      throw new Error('Incident title is required');
+    }
+
+    const severity = data.severity || 'major';
+    if (!SEVERITIES.includes(severity)) {
+      throw new Error(`Invalid severity: ${severity}`);
+    }
+
+    // Repeated events fold into the open incident for the same key
+    if (data.key) {
+      const existing = Array.from(this.incidents.values()).find(
+        (i) => i.key === data.key && i.status !== 'resolved'
+      );
+
+      if (existing) {
+        existing.occurrences++;
+        existing.lastOccurredAt = new Date().toISOString();
+        await this.saveIncidents();
+        return existing;
+      }
+    }
+
+    const now = new Date().toISOString();
+    const incident = {
+      id: crypto.randomUUID(),
+      key: data.key || null,
+      title: data.title,
+      publicTitle: data.publicTitle || null,
+      severity,
+      source: data.source || 'manual',
+      status: 'open',
+      projectId: data.projectId || null,
+      details: data.details || {},
+      occurrences: 1,
+      escalationLevel: 0,
+      createdAt: now,
+      lastOccurredAt: now,
+      acknowledgedAt: null,
+      acknowledgedBy: null,
+      resolvedAt: null,
+      resolvedBy: null,
+      resolution: null,
+      timeline: [{ at: now, action: 'opened', by: data.source || 'manual' }],
+    };
+
+    this.incidents.set(incident.id, incident);
+    await this.saveIncidents();
+
+    this.emit('incident:opened', incident);
+    this.logger.warn(`Incident opened: ${incident.title}`, {
+      incidentId: incident.id,
+      severity,
+    });
+
+    return incident;
+  }
+
+  async acknowledge(incidentId, userId, note = '') {
+    const incident = this.getIncident(incidentId);
+
+    if (incident.status !== 'open') {
+      throw new Error(`Incident is already ${incident.status}`);
+    }
+
+    incident.status = 'acknowledged';
+    incident.acknowledgedAt = new Date().toISOString();
+    incident.acknowledgedBy = userId;
+    incident.timeline.push({
+      at: incident.acknowledgedAt,
+      action: 'acknowledged',
+      by: userId,
+      note,
+    });
+
+    await this.saveIncidents();
+    this.emit('incident:acknowledged', incident);
+
+    return incident;
+  }
+
+  async resolve(incidentId, userId, resolution = '') {
+    const incident = this.getIncident(incidentId);
+
+    if (incident.status === 'resolved') {
+      throw new Error('Incident is already resolved');
+    }
+
+    incident.status = 'resolved';
+    incident.resolvedAt = new Date().toISOString();
+    incident.resolvedBy = userId;
+    incident.resolution = resolution;
+    incident.timeline.push({
+      at: incident.resolvedAt,
+      action: 'resolved',
+      by: userId,
+      note: resolution,
+    });
+
+    await this.saveIncidents();
+    this.emit('incident:resolved', incident);
+
+    return incident;
+  }
+
+  async setPublicTitle(incidentId, publicTitle, userId) {
+    const incident = this.getIncident(incidentId);
+    if (publicTitle != null && typeof publicTitle !== 'string') {
+      throw new Error('Public title must be a string');
+    }
+
+    incident.publicTitle = publicTitle?.trim() || null;
+    incident.timeline.push({
+      at: new Date().toISOString(),
+      action: 'public-title',
+      by: userId,
+      note: incident.publicTitle || '',
+    });
+
+    await this.saveIncidents();
+    return incident;
+  }
+
+  // The fields safe to show on the unauthenticated status page
+  toPublic(incident) {
+    return {
+      title: incident.publicTitle || PUBLIC_TITLES[incident.severity],
+      severity: incident.severity,
+      status: incident.status,
+      createdAt: incident.createdAt,
+    };
+  }
+
+  async checkEscalations() {
+    const now = Date.now();
+    let changed = false;
+
+    for (const incident of this.incidents.values()) {
+      if (
+        incident.status !== 'open' ||
+        incident.escalationLevel >= this.config.maxEscalationLevel
+      ) {
+        continue;
+      }
+
+      const lastEscalation = incident.timeline
+        .filter((entry) => ['opened', 'escalated'].includes(entry.action))
+        .pop();
+      const waited = now - new Date(lastEscalation.at).getTime();
+
+      if (waited >= this.config.escalationWindow) {
+        incident.escalationLevel++;
+        incident.timeline.push({
+          at: new Date().toISOString(),
+          action: 'escalated',
+          by: 'system',
+          note: `Unacknowledged for ${Math.round(waited / 60000)} minutes`,
+        });
+        changed = true;
+
+        this.emit('incident:escalated', incident);
+        this.logger.warn(`Incident escalated: ${incident.title}`, {
+          incidentId: incident.id,
+          level: incident.escalationLevel,
+        });
+      }
+    }
+
+    if (changed) {
+      await this.saveIncidents();
+    }
+  }
+
+  getIncident(incidentId) {
+    const incident = this.incidents.get(incidentId);
+    if (!incident) {
+      throw new Error(`Incident not found: ${incidentId}`);
+    }
+    return incident;
+  }
+
+  async listIncidents(options = {}) {
+    let incidents = Array.from(this.incidents.values());
+
+    if (options.status) {
+      incidents = incidents.filter((i) => i.status === options.status);
+    } else if (!options.includeResolved) {
+      incidents = incidents.filter((i) => i.status !== 'resolved');
+    }
+
+    if (options.severity) {
+      incidents = incidents.filter((i) => i.severity === options.severity);
+    }
+
+    if (options.projectId) {
+      incidents = incidents.filter((i) => i.projectId === options.projectId);
+    }
+
+    return incidents.sort(
+      (a, b) => new Date(b.createdAt) - new Date(a.createdAt)
+    );
+  }
+
+  async stop() {
+    try {
+      if (this.escalationTimer) {
+        clearInterval(this.escalationTimer);
+      }
+      await this.saveIncidents();
+      this.logger.info('IncidentManager stopped successfully');
+    } catch (error) {
+      this.logger.error('Error stopping IncidentManager:', error);
+      throw error;
+    }
+  }
+}
+
+export { IncidentManager, SEVERITIES, PUBLIC_TITLES };
@@ -0,0 +1,137 @@
+/**
+ * Tests for Incident Manager
+ */
+
+import { IncidentManager } from './incident-manager.js';
+import { EventEmitter } from 'events';
+import { promises as fs } from 'fs';
+import os from 'os';
+import path from 'path';
+
+describe('IncidentManager', () => {
+  let dir;
+  let incidentManager;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'incidents-'));
+    incidentManager = new IncidentManager({
+      incidentsFile: path.join(dir, 'incidents.json'),
+      escalationWindow: 1000,
+      maxEscalationLevel: 2,
+    });
+  });
+
+  afterEach(async () => {
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  // Moves the incident's history back so the escalation window has passed
+  const age = (incident, ms) => {
+    for (const entry of incident.timeline) {
+      entry.at = new Date(new Date(entry.at).getTime() - ms).toISOString();
+    }
+  };
+
+  describe('openIncident', () => {
+    it('should open an incident', async () => {
+      const incident = await incidentManager.openIncident({
+        title: 'Database unreachable',
+        severity: 'critical',
+      });
+
+      expect(incident.status).toBe('open');
+      expect(incident.timeline[0].action).toBe('opened');
+      expect(incidentManager.getIncident(incident.id)).toBe(incident);
+    });
+
+    it('should fold repeated events into the open incident', async () => {
+      const first = await incidentManager.openIncident({
+        key: 'alert:cpu',
+        title: 'High CPU usage',
+      });
+      const second = await incidentManager.openIncident({
+        key: 'alert:cpu',
+        title: 'High CPU usage',
+      });
+
+      expect(second.id).toBe(first.id);
+      expect(second.occurrences).toBe(2);
+    });
+
+    it('should open incidents for critical alerts only', async () => {
+      const statusMonitor = new EventEmitter();
+      incidentManager.attach({ statusMonitor });
+
+      statusMonitor.emit('alert', {
+        type: 'cpu',
+        level: 'warning',
+        message: 'High CPU usage: 85%',
+      });
+      statusMonitor.emit('alert', {
+        type: 'disk',
+        level: 'critical',
+        message: 'High disk usage: 98%',
+      });
+      await new Promise((resolve) => setImmediate(resolve));
+
+      const incidents = await incidentManager.listIncidents();
+      expect(incidents).toHaveLength(1);
+      expect(incidents[0].key).toBe('alert:disk');
+    });
+  });
+
+  describe('acknowledge', () => {
+    it('should acknowledge an open incident', async () => {
+      const incident = await incidentManager.openIncident({ title: 'Outage' });
+
+      await incidentManager.acknowledge(incident.id, 'user-1', 'Looking');
+
+      expect(incident.status).toBe('acknowledged');
+      expect(incident.acknowledgedBy).toBe('user-1');
+      await expect(
+        incidentManager.acknowledge(incident.id, 'user-2')
+      ).rejects.toThrow('Incident is already acknowledged');
+    });
+  });
+
+  describe('checkEscalations', () => {
+    it('should escalate unacknowledged incidents up to the limit', async () => {
+      const incident = await incidentManager.openIncident({ title: 'Outage' });
+
+      for (let i = 0; i < 3; i++) {
+        age(incident, 2000);
+        await incidentManager.checkEscalations();
+      }
+
+      expect(incident.escalationLevel).toBe(2);
+      expect(
+        incident.timeline.filter((entry) => entry.action === 'escalated')
+      ).toHaveLength(2);
+    });
+
+    it('should not escalate acknowledged incidents', async () => {
+      const incident = await incidentManager.openIncident({ title: 'Outage' });
+      await incidentManager.acknowledge(incident.id, 'user-1');
+
+      age(incident, 2000);
+      await incidentManager.checkEscalations();
+
+      expect(incident.escalationLevel).toBe(0);
+    });
+  });
+
+  describe('resolve', () => {
+    it('should resolve and drop it from the open list', async () => {
+      const incident = await incidentManager.openIncident({ title: 'Outage' });
+
+      await incidentManager.resolve(incident.id, 'user-1', 'Restarted');
+
+      expect(incident.status).toBe('resolved');
+      expect(incident.resolution).toBe('Restarted');
+      expect(await incidentManager.listIncidents()).toEqual([]);
+      await expect(
+        incidentManager.resolve(incident.id, 'user-1')
+      ).rejects.toThrow('Incident is already resolved');
+    });
+  });
+});
@@ -8,6 +8,57 @@ import { promises as fs } from 'fs';
 import path from 'path';
 import util from 'util';
 
+const REDACTED = '[REDACTED]';
+
+// Process-wide policy shared by every Logger instance
+const policy = {
+  redactFields: new Set(
+    (
+      process.env.LOG_REDACT_FIELDS ||
+      'password,token,accessToken,refreshToken,secret,authorization,cookie,email'
+    )
+      .split(',')
+      .map((field) => field.trim().toLowerCase())
+      .filter(Boolean)
+  ),
+  // Route prefix -> fraction of requests logged, e.g. "/api/status=0.1"
+  sampling: parseSampling(process.env.LOG_SAMPLING || ''),
+  // Request id -> expiry; captured requests bypass sampling and level
+  captures: new Map(),
+};
+
+function parseSampling(spec) {
+  const rates = {};
+  for (const entry of spec.split(',')) {
+    const [route, rate] = entry.split('=');
+    if (route && rate !== undefined) {
+      rates[route.trim()] = parseFloat(rate);
+    }
+  }
+  return rates;
+}
+
+function redact(value, depth = 0) {
+  if (depth > 5 || value === null || typeof value !== 'object') {
+    return value;
+  }
+  if (Array.isArray(value)) {
+    return value.map((item) => redact(item, depth + 1));
+  }
+  // Leave Errors and other class instances to util.inspect / JSON
+  if (Object.getPrototypeOf(value) !== Object.prototype) {
+    return value;
+  }
+
+  const result = {};
+  for (const [key, item] of Object.entries(value)) {
+    result[key] = policy.redactFields.has(key.toLowerCase())
+      ? REDACTED
+      : redact(item, depth + 1);
+  }
+  return result;
+}
+
 class Logger {
   constructor(name, config = {}) {
     this.name = name;
@@ -69,6 +120,55 @@ class Logger {
     }
   }
 
+  static configurePolicy(config = {}) {
+    if (config.redactFields) {
+      policy.redactFields = new Set(
+        config.redactFields.map((field) => field.toLowerCase())
+      );
+    }
+    if (config.sampling) {
+      policy.sampling = { ...config.sampling };
+    }
+  }
+
+  static getPolicy() {
+    return {
+      redactFields: Array.from(policy.redactFields),
+      sampling: { ...policy.sampling },
+      captures: Array.from(policy.captures, ([requestId, expiresAt]) => ({
+        requestId,
+        expiresAt: new Date(expiresAt).toISOString(),
+      })),
+    };
+  }
+
+  // Log everything (all levels, no sampling) for one request id for a while
+  static captureRequest(requestId, ttl = 10 * 60 * 1000) {
+    policy.captures.set(requestId, Date.now() + ttl);
+  }
+
+  static isCaptured(requestId) {
+    if (!requestId) return false;
+
+    const expiresAt = policy.captures.get(requestId);
+    if (expiresAt && expiresAt < Date.now()) {
+      policy.captures.delete(requestId);
+      return false;
+    }
+    return Boolean(expiresAt);
+  }
+
+  // Longest matching route prefix decides the sample rate
+  static shouldSample(route, requestId) {
+    if (Logger.isCaptured(requestId)) return true;
+
+    const prefix = Object.keys(policy.sampling)
+      .filter((candidate) => route.startsWith(candidate))
+      .sort((a, b) => b.length - a.length)[0];
+
+    return prefix === undefined || Math.random() < policy.sampling[prefix];
+  }
+
   shouldLog(level) {
     const targetLevel = this.levels[this.config.level];
     const messageLevel = this.levels[level];
@@ -153,13 +253,20 @@ class Logger {
   }
 
   log(level, message, meta = {}) {
-    if (!this.shouldLog(level)) return;
+    if (!this.shouldLog(level) && !Logger.isCaptured(meta?.requestId)) {
+      return;
+    }
 
-    const formattedMessage = this.formatMessage(level, message, meta);
+    const safeMeta = redact(meta);
+    const formattedMessage = this.formatMessage(level, message, safeMeta);
 
     // Console output
     if (this.config.console) {
-      const consoleMessage = this.formatConsoleMessage(level, message, meta);
+      const consoleMessage = this.formatConsoleMessage(
+        level,
+        message,
+        safeMeta
+      );
       console.log(consoleMessage);
     }
 
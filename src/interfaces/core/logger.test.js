@@ -0,0 +1,106 @@
+/**
+ * Tests for Logger
+ */
+
+import { Logger } from './logger.js';
+import { jest } from '@jest/globals';
+
+describe('Logger', () => {
+  let savedPolicy;
+  let consoleLog;
+
+  beforeEach(() => {
+    savedPolicy = Logger.getPolicy();
+    consoleLog = jest.spyOn(console, 'log').mockImplementation(() => {});
+  });
+
+  afterEach(() => {
+    consoleLog.mockRestore();
+    Logger.configurePolicy(savedPolicy);
+  });
+
+  const lastLine = () => consoleLog.mock.calls.at(-1)[0];
+
+  describe('redaction', () => {
+    it('should redact sensitive fields at any depth', () => {
+      const logger = new Logger('Test');
+
+      logger.info('Login', {
+        user: { Email: 'ada@example.com', name: 'ada' },
+        headers: [{ authorization: 'Bearer abc123' }],
+      });
+
+      const line = lastLine();
+      expect(line).not.toContain('ada@example.com');
+      expect(line).not.toContain('abc123');
+      expect(line).toContain('[REDACTED]');
+      expect(line).toContain('ada');
+    });
+
+    it('should follow the configured field list', () => {
+      const logger = new Logger('Test');
+      Logger.configurePolicy({ redactFields: ['SSN'] });
+
+      logger.info('Profile', { ssn: '123-45-6789', password: 'hunter2' });
+
+      expect(lastLine()).not.toContain('123-45-6789');
+      expect(lastLine()).toContain('hunter2');
+    });
+
+    it('should leave Error instances intact', () => {
+      const logger = new Logger('Test');
+
+      logger.error('Failed', { error: new Error('disk full') });
+
+      expect(lastLine()).toContain('disk full');
+    });
+  });
+
+  describe('shouldSample', () => {
+    it('should use the longest matching route prefix', () => {
+      Logger.configurePolicy({
+        sampling: { '/api': 1, '/api/status': 0 },
+      });
+
+      expect(Logger.shouldSample('/api/projects')).toBe(true);
+      expect(Logger.shouldSample('/api/status/health')).toBe(false);
+      expect(Logger.shouldSample('/metrics')).toBe(true);
+    });
+
+    it('should apply fractional rates', () => {
+      Logger.configurePolicy({ sampling: { '/api/status': 0.1 } });
+      const random = jest.spyOn(Math, 'random');
+
+      random.mockReturnValue(0.05);
+      expect(Logger.shouldSample('/api/status')).toBe(true);
+      random.mockReturnValue(0.5);
+      expect(Logger.shouldSample('/api/status')).toBe(false);
+
+      random.mockRestore();
+    });
+  });
+
+  describe('captureRequest', () => {
+    it('should bypass sampling and level for a captured request', () => {
+      Logger.configurePolicy({ sampling: { '/api': 0 } });
+      const logger = new Logger('Test', { level: 'error' });
+
+      Logger.captureRequest('req-capture-1');
+      logger.debug('Handled', { requestId: 'req-capture-1' });
+      logger.debug('Handled', { requestId: 'req-other' });
+
+      expect(Logger.shouldSample('/api/x', 'req-capture-1')).toBe(true);
+      expect(Logger.shouldSample('/api/x', 'req-other')).toBe(false);
+      expect(consoleLog).toHaveBeenCalledTimes(1);
+    });
+
+    it('should expire captures after their ttl', () => {
+      Logger.captureRequest('req-capture-2', -1);
+
+      expect(Logger.isCaptured('req-capture-2')).toBe(false);
+      expect(
+        Logger.getPolicy().captures.map((capture) => capture.requestId)
+      ).not.toContain('req-capture-2');
+    });
+  });
+});
@@ -13,17 +13,33 @@ class MetricsCollector extends EventEmitter {
     super();
     this.config = {
       metricsFile: config.metricsFile || './data/metrics.json',
+      availabilityFile:
+        config.availabilityFile || './data/availability.json',
+      availabilityDays: config.availabilityDays || 90,
       retentionPeriod: config.retentionPeriod || 7 * 24 * 60 * 60 * 1000, // 7 days
+      // High-frequency records are kept in memory and written this often
+      flushInterval: config.flushInterval || 60 * 1000,
       ...config,
     };
 
     this.logger = new Logger('MetricsCollector');
     this.metrics = [];
+    this.availability = {};
+    this.dirty = new Set(); // files with unsaved changes
+    this.flushTimer = null;
   }
 
   async initialize() {
     try {
       await this.loadMetrics();
+      await this.loadAvailability();
+
+      this.flushTimer = setInterval(
+        () => this.flush(),
+        this.config.flushInterval
+      );
+      this.flushTimer.unref?.();
+
       this.logger.info('MetricsCollector initialized successfully');
     } catch (error) {
       this.logger.error('Failed to initialize MetricsCollector:', error);
@@ -62,6 +78,101 @@ class MetricsCollector extends EventEmitter {
     }
   }
 
+  async flush() {
+    const dirty = Array.from(this.dirty);
+    this.dirty.clear();
+
+    try {
+      if (dirty.includes('metrics')) await this.saveMetrics();
+      if (dirty.includes('availability')) await this.saveAvailability();
+    } catch (error) {
+      dirty.forEach((name) => this.dirty.add(name));
+    }
+  }
+
+  async loadAvailability() {
+    try {
+      const availabilityData = await fs.readFile(
+        this.config.availabilityFile,
+        'utf8'
+      );
+      this.availability = JSON.parse(availabilityData);
+    } catch (error) {
+      if (error.code !== 'ENOENT') {
+        this.logger.error('Failed to load availability history:', error);
+        throw error;
+      }
+    }
+  }
+
+  async saveAvailability() {
+    try {
+      await fs.mkdir(path.dirname(this.config.availabilityFile), {
+        recursive: true,
+      });
+      await fs.writeFile(
+        this.config.availabilityFile,
+        JSON.stringify(this.availability, null, 2)
+      );
+    } catch (error) {
+      this.logger.error('Failed to save availability history:', error);
+      throw error;
+    }
+  }
+
+  // Record one health probe per component into daily up/total buckets;
+  // written out on the next flush
+  async recordAvailability(components, timestamp = new Date()) {
+    const day = timestamp.toISOString().slice(0, 10);
+
+    for (const [component, healthy] of Object.entries(components)) {
+      const history = (this.availability[component] ||= {});
+      const bucket = (history[day] ||= { up: 0, total: 0 });
+
+      bucket.total++;
+      if (healthy) bucket.up++;
+    }
+
+    this.dirty.add('availability');
+  }
+
+  getAvailability(days = this.config.availabilityDays) {
+    const result = {};
+    const today = new Date();
+
+    for (const [component, history] of Object.entries(this.availability)) {
+      const series = [];
+      let up = 0;
+      let total = 0;
+
+      for (let i = days - 1; i >= 0; i--) {
+        const date = new Date(today.getTime() - i * 24 * 60 * 60 * 1000)
+          .toISOString()
+          .slice(0, 10);
+        const bucket = history[date];
+
+        if (bucket) {
+          up += bucket.up;
+          total += bucket.total;
+        }
+
+        series.push({
+          date,
+          uptime: bucket
+            ? Math.round((bucket.up / bucket.total) * 10000) / 100
+            : null,
+        });
+      }
+
+      result[component] = {
+        uptime: total > 0 ? Math.round((up / total) * 10000) / 100 : null,
+        history: series,
+      };
+    }
+
+    return result;
+  }
+
   async recordSystemMetrics(systemStatus) {
     try {
       const metric = {
@@ -86,6 +197,29 @@ class MetricsCollector extends EventEmitter {
     }
   }
 
+  async recordProbeResult(result) {
+    try {
+      const metric = {
+        timestamp: result.timestamp,
+        type: 'probe',
+        data: {
+          name: result.name,
+          up: result.up,
+          latencyMs: result.latencyMs,
+          status: result.status,
+          error: result.error,
+        },
+      };
+
+      this.metrics.push(metric);
+      this.dirty.add('metrics');
+
+      this.emit('metric:recorded', metric);
+    } catch (error) {
+      this.logger.error('Failed to record probe result:', error);
+    }
+  }
+
   async getMetrics(timeRange = '1h', metricTypes = []) {
     try {
       const now = Date.now();
@@ -133,6 +267,18 @@ class MetricsCollector extends EventEmitter {
         await this.saveMetrics();
         this.logger.info(`Cleaned up ${removedCount} old metrics`);
       }
+
+      const oldestDay = new Date(
+        now - this.config.availabilityDays * 24 * 60 * 60 * 1000
+      )
+        .toISOString()
+        .slice(0, 10);
+      for (const history of Object.values(this.availability)) {
+        for (const day of Object.keys(history)) {
+          if (day < oldestDay) delete history[day];
+        }
+      }
+      await this.saveAvailability();
     } catch (error) {
       this.logger.error('Failed to cleanup metrics:', error);
     }
@@ -140,7 +286,10 @@ class MetricsCollector extends EventEmitter {
 
   async stop() {
     try {
+      clearInterval(this.flushTimer);
+      this.dirty.clear();
       await this.saveMetrics();
+      await this.saveAvailability();
       this.logger.info('MetricsCollector stopped successfully');
     } catch (error) {
       this.logger.error('Error stopping MetricsCollector:', error);
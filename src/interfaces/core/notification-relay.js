@@ -0,0 +1,263 @@
+/**
+ * Notification Relay
+ * Subscribes to the API server's socket stream and raises OS desktop
+ * notifications, with per-event-type filters and quiet hours
+ */
+
+import { EventEmitter } from 'events';
+import { execFile } from 'child_process';
+import { io } from 'socket.io-client';
+import { Logger } from './logger.js';
+import { MESSAGE_TYPES } from '../api/socket-protocol.js';
+
+const DEFAULT_EVENT_TYPES = [
+  'alert:firing',
+  'announcement:created',
+  'incident:opened',
+  'incident:escalated',
+  'auth:new-device',
+  'auth:token-expiring',
+  'watch:activity',
+];
+
+// "22:00-07:00" -> minutes since midnight; ranges may wrap past midnight
+function parseQuietHours(value) {
+  if (!value) return null;
+
+  const match = /^(\d{1,2}):(\d{2})-(\d{1,2}):(\d{2})$/.exec(value.trim());
+  if (!match) {
+    throw new Error(`Invalid quiet hours: ${value} (expected HH:MM-HH:MM)`);
+  }
+
+  const [, startH, startM, endH, endM] = match.map(Number);
+  if (startH > 23 || endH > 23 || startM > 59 || endM > 59) {
+    throw new Error(`Invalid quiet hours: ${value}`);
+  }
+
+  return { start: startH * 60 + startM, end: endH * 60 + endM };
+}
+
+function isQuietTime(quietHours, now = new Date()) {
+  if (!quietHours) return false;
+
+  const minutes = now.getHours() * 60 + now.getMinutes();
+  const { start, end } = quietHours;
+
+  return start <= end
+    ? minutes >= start && minutes < end
+    : minutes >= start || minutes < end;
+}
+
+// Title and body shown for each supported event type
+function formatNotification(type, data = {}) {
+  switch (type) {
+    case 'alert:firing':
+    case 'alert:resolved':
+      return {
+        title: `Alert ${data.state}: ${data.name}`,
+        body: data.message || '',
+        severity: data.severity,
+      };
+    case 'announcement:created':
+    case 'announcement:updated':
+      return {
+        title: data.title,
+        body: data.message || '',
+        severity: data.severity,
+      };
+    case 'auth:new-device': {
+      const place = data.location?.country
+        ? [data.location.city, data.location.country].filter(Boolean).join(', ')
+        : 'an unknown location';
+      return {
+        title: 'New sign-in to your account',
+        body: `From ${place} (${data.ipAddress || 'unknown IP'}). Not you? Revoke: ${data.revoke?.method} ${data.revoke?.path}`,
+        severity: 'warning',
+      };
+    }
+    case 'auth:token-expiring':
+      return {
+        title: `Access token "${data.name}" expires in ${data.daysLeft} day${data.daysLeft === 1 ? '' : 's'}`,
+        body: `${data.prefix}... expires ${data.expiresAt}; create a replacement`,
+        severity: 'warning',
+      };
+    case 'auth:token-expired':
+      return {
+        title: `Access token "${data.name}" has expired`,
+        body: `${data.prefix}... no longer works`,
+      };
+    case 'project:started':
+    case 'project:stopped':
+      return {
+        title: `Project ${type.split(':')[1]}`,
+        body: data.projectId || '',
+      };
+    case 'watch:activity':
+      return {
+        title: data.summary,
+        body: `${data.itemType} ${data.itemName || data.itemId}`,
+      };
+    default:
+      if (type.startsWith('incident:')) {
+        return {
+          title: `Incident ${type.split(':')[1]}: ${data.title || data.id}`,
+          body: data.description || '',
+          severity: data.severity,
+        };
+      }
+      return { title: type, body: JSON.stringify(data) };
+  }
+}
+
+// Text is passed as arguments or environment, never interpolated into shell
+function desktopNotify({ title, body }, platform = process.platform) {
+  let command;
+  let args;
+  let env = process.env;
+
+  switch (platform) {
+    case 'darwin':
+      command = 'osascript';
+      args = [
+        '-e',
+        'on run argv',
+        '-e',
+        'display notification (item 2 of argv) with title (item 1 of argv)',
+        '-e',
+        'end run',
+        title,
+        body,
+      ];
+      break;
+    case 'win32':
+      command = 'powershell.exe';
+      args = [
+        '-NoProfile',
+        '-Command',
+        [
+          'Add-Type -AssemblyName System.Windows.Forms',
+          '$n = New-Object System.Windows.Forms.NotifyIcon',
+          '$n.Icon = [System.Drawing.SystemIcons]::Information',
+          '$n.Visible = $true',
+          '$n.ShowBalloonTip(5000, $env:KASKMAN_TITLE, $env:KASKMAN_BODY, 0)',
+          'Start-Sleep -Seconds 6',
+          '$n.Dispose()',
+        ].join('; '),
+      ];
+      env = { ...process.env, KASKMAN_TITLE: title, KASKMAN_BODY: body };
+      break;
+    default:
+      command = 'notify-send';
+      args = ['--app-name=KaskMan', title, body];
+  }
+
+  return new Promise((resolve, reject) => {
+    execFile(command, args, { env }, (error) => {
+      if (error) {
+        reject(new Error(`${command} failed: ${error.message}`));
+      } else {
+        resolve();
+      }
+    });
+  });
+}
+
+class NotificationRelay extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      url: config.url || process.env.KASKMAN_URL || 'http://localhost:8080',
+      token: config.token || process.env.KASKMAN_TOKEN,
+      eventTypes: config.eventTypes || DEFAULT_EVENT_TYPES,
+      notify: config.notify || desktopNotify,
+      ...config,
+      quietHours: parseQuietHours(config.quietHours),
+    };
+
+    this.logger = new Logger('NotificationRelay');
+    this.socket = null;
+  }
+
+  validate() {
+    if (!this.config.token) {
+      throw new Error('An API token is required (--token or KASKMAN_TOKEN)');
+    }
+
+    const unknown = this.config.eventTypes.filter(
+      (type) => !MESSAGE_TYPES.includes(type)
+    );
+    if (unknown.length > 0) {
+      throw new Error(`Unknown event types: ${unknown.join(', ')}`);
+    }
+  }
+
+  start() {
+    this.validate();
+
+    this.socket = io(this.config.url, {
+      auth: { token: this.config.token },
+      reconnection: true,
+    });
+
+    // The server filters by type once the hello is acknowledged
+    this.socket.on('connect', () => {
+      this.socket.emit('hello', {
+        version: 2,
+        messageTypes: this.config.eventTypes,
+      });
+    });
+
+    this.socket.on('hello:ack', (ack) => {
+      this.socket.emit('subscribe:system');
+      this.emit('connected', ack);
+    });
+
+    this.socket.on('hello:error', (error) => {
+      this.emit('error', new Error(error.message));
+    });
+
+    this.socket.on('connect_error', (error) => {
+      this.emit('error', error);
+    });
+
+    this.socket.on('disconnect', (reason) => {
+      this.emit('disconnected', reason);
+    });
+
+    for (const type of this.config.eventTypes) {
+      this.socket.on(type, (envelope) => this.handle(type, envelope.data));
+    }
+  }
+
+  async handle(type, data) {
+    const notification = { type, ...formatNotification(type, data) };
+
+    if (isQuietTime(this.config.quietHours)) {
+      notification.suppressed = true;
+      this.emit('notification', notification);
+      return;
+    }
+
+    try {
+      await this.config.notify(notification);
+      this.emit('notification', notification);
+    } catch (error) {
+      this.logger.error('Failed to raise desktop notification:', error);
+      this.emit('error', error);
+    }
+  }
+
+  stop() {
+    this.socket?.disconnect();
+    this.socket = null;
+  }
+}
+
+export {
+  NotificationRelay,
+  DEFAULT_EVENT_TYPES,
+  parseQuietHours,
+  isQuietTime,
+  formatNotification,
+  desktopNotify,
+};
@@ -0,0 +1,157 @@
+/**
+ * Tests for Notification Relay
+ */
+
+import {
+  NotificationRelay,
+  parseQuietHours,
+  isQuietTime,
+  formatNotification,
+} from './notification-relay.js';
+import { jest } from '@jest/globals';
+
+describe('notification relay', () => {
+  const at = (hours, minutes) => new Date(2026, 0, 1, hours, minutes);
+
+  describe('parseQuietHours', () => {
+    it('should parse HH:MM ranges into minutes', () => {
+      expect(parseQuietHours('22:00-07:30')).toEqual({
+        start: 1320,
+        end: 450,
+      });
+      expect(parseQuietHours(undefined)).toBeNull();
+    });
+
+    it('should reject malformed ranges', () => {
+      expect(() => parseQuietHours('10pm-7am')).toThrow(
+        'Invalid quiet hours: 10pm-7am (expected HH:MM-HH:MM)'
+      );
+      expect(() => parseQuietHours('24:00-07:00')).toThrow(
+        'Invalid quiet hours: 24:00-07:00'
+      );
+    });
+  });
+
+  describe('isQuietTime', () => {
+    it('should handle ranges that wrap past midnight', () => {
+      const night = parseQuietHours('22:00-07:00');
+
+      expect(isQuietTime(night, at(23, 30))).toBe(true);
+      expect(isQuietTime(night, at(6, 59))).toBe(true);
+      expect(isQuietTime(night, at(7, 0))).toBe(false);
+      expect(isQuietTime(night, at(12, 0))).toBe(false);
+    });
+
+    it('should handle same-day ranges', () => {
+      const lunch = parseQuietHours('12:00-13:00');
+
+      expect(isQuietTime(lunch, at(12, 30))).toBe(true);
+      expect(isQuietTime(lunch, at(13, 0))).toBe(false);
+      expect(isQuietTime(null, at(12, 30))).toBe(false);
+    });
+  });
+
+  describe('formatNotification', () => {
+    it('should describe new-device sign-ins with the revoke route', () => {
+      const notification = formatNotification('auth:new-device', {
+        ipAddress: '203.0.113.7',
+        location: { city: 'Oslo', country: 'NO' },
+        revoke: { method: 'DELETE', path: '/api/auth/sessions/s1' },
+      });
+
+      expect(notification.severity).toBe('warning');
+      expect(notification.body).toBe(
+        'From Oslo, NO (203.0.113.7). Not you? Revoke: DELETE /api/auth/sessions/s1'
+      );
+    });
+
+    it('should pluralize token expiry days', () => {
+      const title = (daysLeft) =>
+        formatNotification('auth:token-expiring', { name: 'ci', daysLeft })
+          .title;
+
+      expect(title(1)).toBe('Access token "ci" expires in 1 day');
+      expect(title(3)).toBe('Access token "ci" expires in 3 days');
+    });
+
+    it('should cover incidents and unknown types', () => {
+      expect(
+        formatNotification('incident:escalated', { id: 'i1', severity: 'sev1' })
+      ).toMatchObject({ title: 'Incident escalated: i1', severity: 'sev1' });
+      expect(formatNotification('custom:event', { a: 1 })).toEqual({
+        title: 'custom:event',
+        body: '{"a":1}',
+      });
+    });
+  });
+
+  describe('NotificationRelay', () => {
+    it('should require a token and known event types', () => {
+      expect(() =>
+        new NotificationRelay({ token: '', eventTypes: [] }).validate()
+      ).toThrow('An API token is required');
+      expect(() =>
+        new NotificationRelay({
+          token: 'kask_x',
+          eventTypes: ['alert:firing', 'bogus'],
+        }).validate()
+      ).toThrow('Unknown event types: bogus');
+    });
+
+    it('should raise desktop notifications for events', async () => {
+      const notify = jest.fn().mockResolvedValue();
+      const relay = new NotificationRelay({ token: 'kask_x', notify });
+      const emitted = jest.fn();
+      relay.on('notification', emitted);
+
+      await relay.handle('alert:firing', {
+        state: 'firing',
+        name: 'High CPU',
+        severity: 'critical',
+      });
+
+      expect(notify).toHaveBeenCalledWith(
+        expect.objectContaining({ title: 'Alert firing: High CPU' })
+      );
+      expect(emitted).toHaveBeenCalledTimes(1);
+    });
+
+    it('should suppress notifications during quiet hours', async () => {
+      const now = new Date();
+      const minutes = now.getHours() * 60 + now.getMinutes();
+      const clock = (m) => {
+        const wrapped = (m + 1440) % 1440;
+        const pad = (n) => String(n).padStart(2, '0');
+        return `${pad(Math.floor(wrapped / 60))}:${pad(wrapped % 60)}`;
+      };
+      const notify = jest.fn().mockResolvedValue();
+      const relay = new NotificationRelay({
+        token: 'kask_x',
+        notify,
+        quietHours: `${clock(minutes - 5)}-${clock(minutes + 5)}`,
+      });
+      const emitted = jest.fn();
+      relay.on('notification', emitted);
+
+      await relay.handle('watch:activity', { summary: 'x', itemType: 'job' });
+
+      expect(notify).not.toHaveBeenCalled();
+      expect(emitted).toHaveBeenCalledWith(
+        expect.objectContaining({ suppressed: true })
+      );
+    });
+
+    it('should emit errors when the notifier fails', async () => {
+      const relay = new NotificationRelay({
+        token: 'kask_x',
+        notify: jest.fn().mockRejectedValue(new Error('notify-send failed')),
+      });
+      const errors = jest.fn();
+      relay.on('error', errors);
+
+      await relay.handle('project:started', { projectId: 'p1' });
+
+      expect(errors).toHaveBeenCalledTimes(1);
+    });
+  });
+});
@@ -0,0 +1,232 @@
+/**
+ * OIDC
+ * OpenID Connect single sign-on: the authorization code flow with PKCE,
+ * ID token verification against the issuer's keys, and mapping of claims
+ * to platform roles
+ */
+
+import crypto from 'crypto';
+import jwt from 'jsonwebtoken';
+import { Logger } from './logger.js';
+
+function base64url(buffer) {
+  return buffer.toString('base64url');
+}
+
+// "engineering=user,platform-admins=admin" -> { engineering: 'user', ... }
+function parseRoleMapping(value) {
+  if (!value) return {};
+  if (typeof value === 'object') return value;
+
+  return Object.fromEntries(
+    value
+      .split(',')
+      .map((pair) => pair.split('=').map((part) => part.trim()))
+      .filter(([claim, role]) => claim && role)
+  );
+}
+
+class OIDCProvider {
+  constructor(config = {}) {
+    this.config = {
+      issuer: config.issuer || process.env.OIDC_ISSUER || null,
+      clientId: config.clientId || process.env.OIDC_CLIENT_ID || null,
+      clientSecret:
+        config.clientSecret || process.env.OIDC_CLIENT_SECRET || null,
+      redirectUri: config.redirectUri || process.env.OIDC_REDIRECT_URI || null,
+      scopes: config.scopes || 'openid profile email',
+      // Claim holding the user's groups or roles, and how its values map to
+      // platform roles; the first mapped value in mapping order wins
+      roleClaim: config.roleClaim || process.env.OIDC_ROLE_CLAIM || 'groups',
+      roleMapping: config.roleMapping || process.env.OIDC_ROLE_MAPPING,
+      defaultRole: config.defaultRole || 'user',
+      stateTtl: config.stateTtl || 10 * 60 * 1000, // login attempt lifetime
+      clockTolerance: config.clockTolerance ?? 60, // seconds
+      ...config,
+    };
+    this.config.issuer = this.config.issuer?.replace(/\/$/, '') || null;
+    this.config.roleMapping = parseRoleMapping(this.config.roleMapping);
+
+    this.logger = new Logger('OIDC');
+    this.metadata = null;
+    this.keys = new Map(); // kid -> public key
+    this.pending = new Map(); // state -> { nonce, codeVerifier, ... }
+  }
+
+  get enabled() {
+    return Boolean(this.config.issuer && this.config.clientId);
+  }
+
+  async discover() {
+    if (this.metadata) return this.metadata;
+
+    const response = await fetch(
+      `${this.config.issuer}/.well-known/openid-configuration`
+    );
+    if (!response.ok) {
+      throw new Error(`OIDC discovery failed: HTTP ${response.status}`);
+    }
+
+    const metadata = await response.json();
+    if (metadata.issuer?.replace(/\/$/, '') !== this.config.issuer) {
+      throw new Error(`OIDC issuer mismatch: ${metadata.issuer}`);
+    }
+
+    this.metadata = metadata;
+    return metadata;
+  }
+
+  // Keys are refetched when a token names one we have not seen, which is
+  // how issuers roll their signing keys
+  async getKey(kid) {
+    if (!this.keys.has(kid)) {
+      const { jwks_uri: jwksUri } = await this.discover();
+      const response = await fetch(jwksUri);
+      if (!response.ok) {
+        throw new Error(`Failed to fetch OIDC keys: HTTP ${response.status}`);
+      }
+
+      const { keys = [] } = await response.json();
+      this.keys.clear();
+      for (const jwk of keys) {
+        if (jwk.use && jwk.use !== 'sig') continue;
+        this.keys.set(
+          jwk.kid,
+          crypto.createPublicKey({ key: jwk, format: 'jwk' })
+        );
+      }
+      this.logger.info(`Loaded ${this.keys.size} OIDC signing keys`);
+    }
+
+    const key = this.keys.get(kid);
+    if (!key) {
+      throw new Error('ID token signed with an unknown key');
+    }
+    return key;
+  }
+
+  // Starts a login: returns the issuer URL to send the browser to
+  async createAuthorizationRequest({ returnTo = null } = {}) {
+    if (!this.enabled) {
+      throw new Error('Single sign-on is not configured');
+    }
+
+    const metadata = await this.discover();
+    this.prunePending();
+
+    const state = base64url(crypto.randomBytes(24));
+    const nonce = base64url(crypto.randomBytes(24));
+    const codeVerifier = base64url(crypto.randomBytes(32));
+    this.pending.set(state, {
+      nonce,
+      codeVerifier,
+      returnTo,
+      expiresAt: Date.now() + this.config.stateTtl,
+    });
+
+    const params = new URLSearchParams({
+      response_type: 'code',
+      client_id: this.config.clientId,
+      redirect_uri: this.config.redirectUri,
+      scope: this.config.scopes,
+      state,
+      nonce,
+      code_challenge: base64url(
+        crypto.createHash('sha256').update(codeVerifier).digest()
+      ),
+      code_challenge_method: 'S256',
+    });
+
+    return { url: `${metadata.authorization_endpoint}?${params}`, state };
+  }
+
+  prunePending() {
+    const now = Date.now();
+    for (const [state, attempt] of this.pending) {
+      if (attempt.expiresAt <= now) this.pending.delete(state);
+    }
+  }
+
+  /**
+   * Finish a login from the issuer's redirect. Each state is single use.
+   * Returns the verified ID token claims and the caller's returnTo.
+   */
+  async handleCallback({ code, state, error }) {
+    if (error) {
+      throw new Error(
+        `Sign-in was rejected by the identity provider: ${error}`
+      );
+    }
+
+    const attempt = this.pending.get(state);
+    this.pending.delete(state);
+    if (!attempt || attempt.expiresAt <= Date.now()) {
+      throw new Error('Invalid or expired sign-in attempt');
+    }
+    if (!code) {
+      throw new Error('Authorization code is required');
+    }
+
+    const metadata = await this.discover();
+    const response = await fetch(metadata.token_endpoint, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/x-www-form-urlencoded' },
+      body: new URLSearchParams({
+        grant_type: 'authorization_code',
+        code,
+        redirect_uri: this.config.redirectUri,
+        client_id: this.config.clientId,
+        ...(this.config.clientSecret && {
+          client_secret: this.config.clientSecret,
+        }),
+        code_verifier: attempt.codeVerifier,
+      }),
+    });
+    const tokens = await response.json().catch(() => ({}));
+    if (!response.ok || !tokens.id_token) {
+      const reason =
+        tokens.error_description || tokens.error || `HTTP ${response.status}`;
+      throw new Error(`Token exchange failed: ${reason}`);
+    }
+
+    const claims = await this.verifyIdToken(tokens.id_token, attempt.nonce);
+    return { claims, returnTo: attempt.returnTo };
+  }
+
+  async verifyIdToken(idToken, nonce) {
+    const decoded = jwt.decode(idToken, { complete: true });
+    if (!decoded) {
+      throw new Error('Malformed ID token');
+    }
+
+    const claims = jwt.verify(idToken, await this.getKey(decoded.header.kid), {
+      algorithms: ['RS256', 'RS384', 'RS512', 'ES256', 'ES384', 'ES512'],
+      issuer: [this.config.issuer, `${this.config.issuer}/`],
+      audience: this.config.clientId,
+      clockTolerance: this.config.clockTolerance,
+    });
+    if (claims.nonce !== nonce) {
+      throw new Error('ID token nonce mismatch');
+    }
+
+    return claims;
+  }
+
+  mapRole(claims) {
+    const values = [].concat(claims[this.config.roleClaim] ?? []).map(String);
+    for (const [claim, role] of Object.entries(this.config.roleMapping)) {
+      if (values.includes(claim)) return role;
+    }
+    return this.config.defaultRole;
+  }
+
+  getPublicConfig() {
+    return {
+      enabled: this.enabled,
+      issuer: this.config.issuer,
+      roleClaim: this.config.roleClaim,
+    };
+  }
+}
+
+export { OIDCProvider, parseRoleMapping };
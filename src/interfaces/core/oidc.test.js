@@ -0,0 +1,266 @@
+/**
+ * Tests for OIDC
+ */
+
+import { OIDCProvider, parseRoleMapping } from './oidc.js';
+import { AuthManager } from './auth-manager.js';
+import { jest } from '@jest/globals';
+import crypto from 'crypto';
+import { promises as fs } from 'fs';
+import jwt from 'jsonwebtoken';
+import os from 'os';
+import path from 'path';
+
+const ISSUER = 'https://id.example.com';
+const CLIENT_ID = 'kaskman';
+
+describe('OIDCProvider', () => {
+  const { privateKey, publicKey } = crypto.generateKeyPairSync('rsa', {
+    modulusLength: 2048,
+  });
+  let provider;
+
+  const sign = (claims = {}, { key = privateKey, kid = 'key-1' } = {}) =>
+    jwt.sign(
+      {
+        iss: ISSUER,
+        aud: CLIENT_ID,
+        sub: 'user-1',
+        nonce: 'nonce-1',
+        exp: Math.floor(Date.now() / 1000) + 300,
+        ...claims,
+      },
+      key,
+      { algorithm: 'RS256', keyid: kid }
+    );
+
+  beforeEach(() => {
+    provider = new OIDCProvider({
+      issuer: `${ISSUER}/`,
+      clientId: CLIENT_ID,
+      roleMapping: 'platform-admins=admin,engineering=user',
+    });
+    // Preload the signing key so verification does not hit the network
+    provider.keys.set('key-1', publicKey);
+  });
+
+  describe('verifyIdToken', () => {
+    it('should return the claims of a valid token', async () => {
+      const claims = await provider.verifyIdToken(sign(), 'nonce-1');
+
+      expect(claims.sub).toBe('user-1');
+    });
+
+    it('should accept the issuer with a trailing slash', async () => {
+      const token = sign({ iss: `${ISSUER}/` });
+
+      await expect(
+        provider.verifyIdToken(token, 'nonce-1')
+      ).resolves.toBeDefined();
+    });
+
+    it('should reject a nonce from another login', async () => {
+      await expect(provider.verifyIdToken(sign(), 'nonce-2')).rejects.toThrow(
+        'ID token nonce mismatch'
+      );
+      await expect(
+        provider.verifyIdToken(sign({ nonce: undefined }), 'nonce-1')
+      ).rejects.toThrow('ID token nonce mismatch');
+    });
+
+    it('should reject tokens from another issuer', async () => {
+      const token = sign({ iss: 'https://evil.example.com' });
+
+      await expect(provider.verifyIdToken(token, 'nonce-1')).rejects.toThrow(
+        'jwt issuer invalid'
+      );
+    });
+
+    it('should reject tokens for another client', async () => {
+      const token = sign({ aud: 'another-app' });
+
+      await expect(provider.verifyIdToken(token, 'nonce-1')).rejects.toThrow(
+        'jwt audience invalid'
+      );
+    });
+
+    it('should reject expired tokens', async () => {
+      const token = sign({ exp: Math.floor(Date.now() / 1000) - 3600 });
+
+      await expect(provider.verifyIdToken(token, 'nonce-1')).rejects.toThrow(
+        'jwt expired'
+      );
+    });
+
+    it('should reject tokens signed with another key', async () => {
+      const other = crypto.generateKeyPairSync('rsa', { modulusLength: 2048 });
+      const token = sign({}, { key: other.privateKey });
+
+      await expect(provider.verifyIdToken(token, 'nonce-1')).rejects.toThrow(
+        'invalid signature'
+      );
+    });
+
+    it('should refetch the keys for an unknown kid', async () => {
+      provider.metadata = { jwks_uri: `${ISSUER}/jwks` };
+      const jwk = publicKey.export({ format: 'jwk' });
+      const fetch = jest.spyOn(globalThis, 'fetch').mockResolvedValue({
+        ok: true,
+        json: async () => ({
+          keys: [{ ...jwk, kid: 'key-2', use: 'sig' }],
+        }),
+      });
+
+      try {
+        const token = sign({}, { kid: 'key-2' });
+        await expect(
+          provider.verifyIdToken(token, 'nonce-1')
+        ).resolves.toBeDefined();
+        await expect(
+          provider.verifyIdToken(sign({}, { kid: 'key-3' }), 'nonce-1')
+        ).rejects.toThrow('ID token signed with an unknown key');
+      } finally {
+        fetch.mockRestore();
+      }
+    });
+
+    it('should reject malformed tokens', async () => {
+      await expect(
+        provider.verifyIdToken('not-a-token', 'nonce-1')
+      ).rejects.toThrow('Malformed ID token');
+    });
+  });
+
+  describe('mapRole', () => {
+    it('should use the first mapping the claims match', () => {
+      expect(
+        provider.mapRole({ groups: ['engineering', 'platform-admins'] })
+      ).toBe('admin');
+      expect(provider.mapRole({ groups: 'engineering' })).toBe('user');
+      expect(provider.mapRole({})).toBe('user');
+    });
+  });
+
+  describe('parseRoleMapping', () => {
+    it('should parse claim=role pairs and skip blanks', () => {
+      expect(parseRoleMapping(' ops = admin ,, bad=,docs=read ')).toEqual({
+        ops: 'admin',
+        docs: 'read',
+      });
+    });
+  });
+});
+
+describe('AuthManager single sign-on', () => {
+  let dir;
+  let authManager;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'auth-sso-'));
+    authManager = new AuthManager({
+      usersFile: path.join(dir, 'users.json'),
+      sessionsFile: path.join(dir, 'sessions.json'),
+      bcryptRounds: 4,
+      mfaRequiredRoles: ['admin'],
+      activity: { activityFile: path.join(dir, 'activity.json') },
+      personalTokens: { tokensFile: path.join(dir, 'tokens.json') },
+      oidc: { issuer: ISSUER, clientId: CLIENT_ID },
+    });
+  });
+
+  afterEach(async () => {
+    await authManager.personalTokens.stop();
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  const createLocalUser = (username, role) =>
+    authManager.createUser({
+      username,
+      email: `${username}@example.com`,
+      password: 'correct-horse-battery',
+      role,
+    });
+
+  const claimsFor = (email, extra = {}) => ({
+    sub: `sub-${email}`,
+    email,
+    email_verified: true,
+    ...extra,
+  });
+
+  const ssoLogin = (claims) => {
+    jest
+      .spyOn(authManager.oidc, 'handleCallback')
+      .mockResolvedValue({ claims, returnTo: null });
+    return authManager.completeSsoLogin({ code: 'code', state: 'state' });
+  };
+
+  describe('provisionSsoUser', () => {
+    it('should link a local account with the same verified email', async () => {
+      const local = await createLocalUser('dev', 'user');
+
+      const user = await authManager.provisionSsoUser(
+        claimsFor('dev@example.com')
+      );
+
+      expect(user.id).toBe(local.id);
+      expect(user.sso.subject).toBe(`${ISSUER}|sub-dev@example.com`);
+    });
+
+    it('should not link by an unverified email', async () => {
+      const local = await createLocalUser('dev', 'user');
+
+      await expect(
+        authManager.provisionSsoUser(
+          claimsFor('dev@example.com', { email_verified: false })
+        )
+      ).rejects.toThrow('Email already exists');
+      expect(authManager.users.get(local.id).sso).toBeNull();
+    });
+
+    it('should never link an admin account by email', async () => {
+      const admin = await createLocalUser('root', 'admin');
+
+      await expect(
+        authManager.provisionSsoUser(claimsFor('root@example.com'))
+      ).rejects.toThrow('This email belongs to an admin account');
+      expect(authManager.users.get(admin.id).sso).toBeNull();
+    });
+  });
+
+  describe('completeSsoLogin', () => {
+    it('should challenge roles that require MFA', async () => {
+      const admin = await authManager.provisionSsoUser(
+        claimsFor('sso-ops@example.com')
+      );
+      await authManager.updateUser(admin.id, { role: 'admin' });
+
+      const result = await ssoLogin(claimsFor('sso-ops@example.com'));
+
+      expect(result.mfaSetupRequired).toBe(true);
+      expect(result.token).toBeUndefined();
+    });
+
+    it('should trust a second factor the provider asserted', async () => {
+      const admin = await authManager.provisionSsoUser(
+        claimsFor('sso-ops@example.com')
+      );
+      await authManager.updateUser(admin.id, { role: 'admin' });
+
+      const result = await ssoLogin(
+        claimsFor('sso-ops@example.com', { amr: ['pwd', 'mfa'] })
+      );
+
+      expect(result.token).toBeDefined();
+    });
+
+    it('should sign in users without MFA requirements', async () => {
+      await createLocalUser('dev', 'user');
+
+      const result = await ssoLogin(claimsFor('dev@example.com'));
+
+      expect(result.user.username).toBe('dev');
+      expect(result.token).toBeDefined();
+    });
+  });
+});
@@ -0,0 +1,329 @@
+/**
+ * Onboarding Manager
+ * First-run checklist for new users and the default dashboard views each
+ * role starts with, so a new account does not land on an empty dashboard
+ */
+
+import { EventEmitter } from 'events';
+import { promises as fs } from 'fs';
+import path from 'path';
+import { Logger } from './logger.js';
+
+// Completed by the server when it sees the action happen; any item can also
+// be completed or dismissed through the API
+const CHECKLIST_ITEMS = [
+  {
+    id: 'create-project',
+    title: 'Create your first project',
+    description: 'Start a project from a template',
+  },
+  {
+    id: 'invite-teammate',
+    title: 'Invite a teammate',
+    description: 'Add another user to the workspace',
+  },
+  {
+    id: 'connect-agent',
+    title: 'Connect an agent',
+    description: 'Authenticate a script or agent with a personal access token',
+  },
+];
+
+// What a saved view can show on the dashboard
+const VIEW_RESOURCES = ['projects', 'incidents', 'alerts', 'status'];
+
+const DEFAULT_VIEWS = {
+  admin: [
+    { id: 'system-status', name: 'System status', resource: 'status' },
+    {
+      id: 'open-incidents',
+      name: 'Open incidents',
+      resource: 'incidents',
+      filters: { status: 'open' },
+    },
+    {
+      id: 'all-projects',
+      name: 'All projects',
+      resource: 'projects',
+      sort: '-updatedAt',
+    },
+  ],
+  user: [
+    {
+      id: 'running-projects',
+      name: 'Running projects',
+      resource: 'projects',
+      filters: { status: 'running' },
+    },
+    {
+      id: 'recent-projects',
+      name: 'Recently updated',
+      resource: 'projects',
+      sort: '-updatedAt',
+    },
+    { id: 'firing-alerts', name: 'Firing alerts', resource: 'alerts' },
+  ],
+};
+
+class OnboardingManager extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      onboardingFile: config.onboardingFile || './data/onboarding.json',
+      // Roles without their own defaults get these
+      fallbackRole: config.fallbackRole || 'user',
+      maxViews: config.maxViews || 20,
+      ...config,
+    };
+
+    this.logger = new Logger('OnboardingManager');
+
+    // role -> views; userId -> { items, views }
+    this.defaultViews = structuredClone(DEFAULT_VIEWS);
+    this.users = new Map();
+  }
+
+  async initialize() {
+    try {
+      await this.load();
+      this.logger.info('OnboardingManager initialized successfully');
+    } catch (error) {
+      this.logger.error('Failed to initialize OnboardingManager:', error);
+      throw error;
+    }
+  }
+
+  async load() {
+    try {
+      const data = JSON.parse(
+        await fs.readFile(this.config.onboardingFile, 'utf8')
+      );
+
+      Object.assign(this.defaultViews, data.defaultViews);
+      for (const [userId, state] of Object.entries(data.users || {})) {
+        this.users.set(userId, state);
+      }
+    } catch (error) {
+      if (error.code !== 'ENOENT') {
+        this.logger.error('Failed to load onboarding state:', error);
+        throw error;
+      }
+    }
+  }
+
+  async save() {
+    try {
+      await fs.mkdir(path.dirname(this.config.onboardingFile), {
+        recursive: true,
+      });
+      await fs.writeFile(
+        this.config.onboardingFile,
+        JSON.stringify(
+          {
+            defaultViews: this.defaultViews,
+            users: Object.fromEntries(this.users),
+          },
+          null,
+          2
+        )
+      );
+    } catch (error) {
+      this.logger.error('Failed to save onboarding state:', error);
+      throw error;
+    }
+  }
+
+  getItem(itemId) {
+    const item = CHECKLIST_ITEMS.find((candidate) => candidate.id === itemId);
+    if (!item) {
+      throw new Error(`Unknown checklist item: ${itemId}`);
+    }
+    return item;
+  }
+
+  // Views stay null until the user saves their own, so they keep following
+  // the role defaults
+  getState(userId) {
+    if (!this.users.has(userId)) {
+      this.users.set(userId, { items: {}, views: null });
+    }
+    return this.users.get(userId);
+  }
+
+  getProgress(userId) {
+    const { items } = this.getState(userId);
+
+    const checklist = CHECKLIST_ITEMS.map((item) => ({
+      ...item,
+      status: items[item.id]?.status || 'pending',
+      updatedAt: items[item.id]?.at || null,
+    }));
+    const completed = checklist.filter(
+      (item) => item.status === 'completed'
+    ).length;
+
+    return {
+      items: checklist,
+      completed,
+      total: checklist.length,
+      percent: Math.round((completed / checklist.length) * 100),
+      // Nothing left to show once every item is completed or dismissed
+      done: checklist.every((item) => item.status !== 'pending'),
+    };
+  }
+
+  // Idempotent; only the first completion is recorded and announced
+  async complete(userId, itemId, source = 'api') {
+    const item = this.getItem(itemId);
+    const state = this.getState(userId);
+    if (state.items[itemId]?.status === 'completed') {
+      return this.getProgress(userId);
+    }
+
+    state.items[itemId] = {
+      status: 'completed',
+      at: new Date().toISOString(),
+      source,
+    };
+    await this.save();
+
+    const progress = this.getProgress(userId);
+    this.emit('onboarding:item-completed', { userId, item, source, progress });
+    if (progress.completed === progress.total) {
+      this.emit('onboarding:completed', { userId, progress });
+    }
+    this.logger.info(`Onboarding item completed: ${itemId} (${userId})`);
+
+    return progress;
+  }
+
+  async dismiss(userId, itemId) {
+    this.getItem(itemId);
+    const state = this.getState(userId);
+    if (state.items[itemId]?.status === 'completed') {
+      throw new Error('Completed items cannot be dismissed');
+    }
+
+    state.items[itemId] = { status: 'dismissed', at: new Date().toISOString() };
+    await this.save();
+
+    return this.getProgress(userId);
+  }
+
+  async restore(userId, itemId) {
+    this.getItem(itemId);
+    const state = this.getState(userId);
+    if (state.items[itemId]?.status === 'dismissed') {
+      delete state.items[itemId];
+      await this.save();
+    }
+
+    return this.getProgress(userId);
+  }
+
+  validateViews(views) {
+    if (!Array.isArray(views)) {
+      throw new Error('Views must be an array');
+    }
+    if (views.length > this.config.maxViews) {
+      throw new Error(`No more than ${this.config.maxViews} views are allowed`);
+    }
+
+    const ids = new Set();
+    return views.map((view, index) => {
+      if (!view?.name || typeof view.name !== 'string') {
+        throw new Error(`View ${index + 1} needs a name`);
+      }
+      if (!VIEW_RESOURCES.includes(view.resource)) {
+        throw new Error(
+          `Invalid view resource: ${view.resource} (use ${VIEW_RESOURCES.join(', ')})`
+        );
+      }
+
+      const id =
+        view.id ||
+        view.name
+          .toLowerCase()
+          .replace(/[^a-z0-9]+/g, '-')
+          .replace(/^-|-$/g, '');
+      if (ids.has(id)) {
+        throw new Error(`Duplicate view id: ${id}`);
+      }
+      ids.add(id);
+
+      return {
+        id,
+        name: view.name.trim(),
+        resource: view.resource,
+        ...(view.filters && { filters: view.filters }),
+        ...(view.sort && { sort: view.sort }),
+      };
+    });
+  }
+
+  getDefaultViews(role) {
+    return structuredClone(
+      this.defaultViews[role] ||
+        this.defaultViews[this.config.fallbackRole] ||
+        []
+    );
+  }
+
+  listDefaultViews() {
+    return structuredClone(this.defaultViews);
+  }
+
+  async setDefaultViews(role, views) {
+    if (!role || typeof role !== 'string') {
+      throw new Error('Role is required');
+    }
+
+    this.defaultViews[role] = this.validateViews(views);
+    await this.save();
+
+    this.emit('views:defaults-updated', {
+      role,
+      views: this.defaultViews[role],
+    });
+    return this.getDefaultViews(role);
+  }
+
+  // The user's saved views, or their role's defaults
+  getViews(user) {
+    const { views } = this.getState(user.id);
+    return {
+      views: views ? structuredClone(views) : this.getDefaultViews(user.role),
+      customized: views !== null,
+    };
+  }
+
+  async saveViews(user, views) {
+    this.getState(user.id).views = this.validateViews(views);
+    await this.save();
+    return this.getViews(user);
+  }
+
+  async resetViews(user) {
+    this.getState(user.id).views = null;
+    await this.save();
+    return this.getViews(user);
+  }
+
+  async removeUser(userId) {
+    if (this.users.delete(userId)) {
+      await this.save();
+    }
+  }
+
+  async stop() {
+    try {
+      await this.save();
+      this.logger.info('OnboardingManager stopped successfully');
+    } catch (error) {
+      this.logger.error('Error stopping OnboardingManager:', error);
+      throw error;
+    }
+  }
+}
+
+export { OnboardingManager, CHECKLIST_ITEMS, VIEW_RESOURCES, DEFAULT_VIEWS };
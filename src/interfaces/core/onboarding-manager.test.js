@@ -0,0 +1,157 @@
+/**
+ * Tests for Onboarding Manager
+ */
+
+import { OnboardingManager, DEFAULT_VIEWS } from './onboarding-manager.js';
+import { jest } from '@jest/globals';
+import { promises as fs } from 'fs';
+import os from 'os';
+import path from 'path';
+
+describe('OnboardingManager', () => {
+  let dir;
+  let onboardingManager;
+
+  const user = { id: 'user-1', role: 'user' };
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'onboarding-'));
+    onboardingManager = new OnboardingManager({
+      onboardingFile: path.join(dir, 'onboarding.json'),
+    });
+  });
+
+  afterEach(async () => {
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  describe('checklist', () => {
+    it('should start with every item pending', () => {
+      const progress = onboardingManager.getProgress(user.id);
+
+      expect(progress).toMatchObject({
+        completed: 0,
+        total: 3,
+        percent: 0,
+        done: false,
+      });
+      expect(progress.items.every((item) => item.status === 'pending')).toBe(
+        true
+      );
+    });
+
+    it('should announce items and the finished checklist once', async () => {
+      const itemCompleted = jest.fn();
+      const completed = jest.fn();
+      onboardingManager.on('onboarding:item-completed', itemCompleted);
+      onboardingManager.on('onboarding:completed', completed);
+
+      await onboardingManager.complete(user.id, 'create-project', 'server');
+      await onboardingManager.complete(user.id, 'create-project');
+      await onboardingManager.complete(user.id, 'invite-teammate');
+      const progress = await onboardingManager.complete(
+        user.id,
+        'connect-agent'
+      );
+
+      expect(progress).toMatchObject({
+        completed: 3,
+        percent: 100,
+        done: true,
+      });
+      expect(itemCompleted).toHaveBeenCalledTimes(3);
+      expect(completed).toHaveBeenCalledTimes(1);
+      expect(
+        onboardingManager.getState(user.id).items['create-project'].source
+      ).toBe('server');
+    });
+
+    it('should dismiss and restore pending items', async () => {
+      await onboardingManager.dismiss(user.id, 'invite-teammate');
+      await onboardingManager.complete(user.id, 'create-project');
+
+      let progress = await onboardingManager.dismiss(user.id, 'connect-agent');
+      expect(progress).toMatchObject({ completed: 1, done: true });
+
+      progress = await onboardingManager.restore(user.id, 'connect-agent');
+      expect(progress.done).toBe(false);
+    });
+
+    it('should reject unknown and completed items', async () => {
+      await onboardingManager.complete(user.id, 'create-project');
+
+      await expect(onboardingManager.complete(user.id, 'tour')).rejects.toThrow(
+        'Unknown checklist item: tour'
+      );
+      await expect(
+        onboardingManager.dismiss(user.id, 'create-project')
+      ).rejects.toThrow('Completed items cannot be dismissed');
+    });
+  });
+
+  describe('views', () => {
+    it('should follow role defaults until the user saves views', async () => {
+      expect(onboardingManager.getViews(user)).toEqual({
+        views: DEFAULT_VIEWS.user,
+        customized: false,
+      });
+      expect(
+        onboardingManager.getViews({ id: 'u2', role: 'auditor' }).views
+      ).toEqual(DEFAULT_VIEWS.user);
+
+      const saved = await onboardingManager.saveViews(user, [
+        { name: ' My Alerts! ', resource: 'alerts' },
+      ]);
+      expect(saved).toEqual({
+        views: [{ id: 'my-alerts', name: 'My Alerts!', resource: 'alerts' }],
+        customized: true,
+      });
+
+      const reset = await onboardingManager.resetViews(user);
+      expect(reset.customized).toBe(false);
+    });
+
+    it('should validate views', async () => {
+      const save = (views) => onboardingManager.saveViews(user, views);
+
+      await expect(save({})).rejects.toThrow('Views must be an array');
+      await expect(save([{ resource: 'alerts' }])).rejects.toThrow(
+        'View 1 needs a name'
+      );
+      await expect(save([{ name: 'Logs', resource: 'logs' }])).rejects.toThrow(
+        'Invalid view resource: logs'
+      );
+      await expect(
+        save([
+          { name: 'Alerts', resource: 'alerts' },
+          { name: 'alerts', resource: 'alerts' },
+        ])
+      ).rejects.toThrow('Duplicate view id: alerts');
+    });
+
+    it('should not let callers mutate the role defaults', () => {
+      const { views } = onboardingManager.getViews(user);
+      views.pop();
+
+      expect(onboardingManager.getDefaultViews('user')).toHaveLength(
+        DEFAULT_VIEWS.user.length
+      );
+    });
+
+    it('should persist default views per role', async () => {
+      await onboardingManager.setDefaultViews('auditor', [
+        { name: 'Incidents', resource: 'incidents' },
+      ]);
+
+      const reloaded = new OnboardingManager({
+        onboardingFile: onboardingManager.config.onboardingFile,
+      });
+      await reloaded.initialize();
+
+      expect(reloaded.getViews({ id: 'u2', role: 'auditor' }).views).toEqual([
+        { id: 'incidents', name: 'Incidents', resource: 'incidents' },
+      ]);
+      expect(reloaded.getDefaultViews('admin')).toEqual(DEFAULT_VIEWS.admin);
+    });
+  });
+});
@@ -0,0 +1,320 @@
+/**
+ * Personal Access Tokens
+ * User-minted tokens with chosen scopes and expiry for scripts and CI.
+ * Only a hash of each token is stored; the token itself is shown once.
+ */
+
+import { EventEmitter } from 'events';
+import { promises as fs } from 'fs';
+import path from 'path';
+import crypto from 'crypto';
+import { Logger } from './logger.js';
+
+// Recognisable prefix so leaked tokens are easy to find and to tell apart
+// from session JWTs
+const TOKEN_PREFIX = 'kmp_';
+
+// "admin" keeps the user's admin role; without it a token acts as a
+// regular user even when minted by an admin
+const TOKEN_SCOPES = ['read', 'write', 'admin'];
+
+function isPersonalAccessToken(token) {
+  return typeof token === 'string' && token.startsWith(TOKEN_PREFIX);
+}
+
+function hashToken(token) {
+  return crypto.createHash('sha256').update(String(token)).digest('hex');
+}
+
+class PersonalAccessTokenStore extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      tokensFile: config.tokensFile || './data/personal-access-tokens.json',
+      defaultExpiryDays: config.defaultExpiryDays || 30,
+      maxExpiryDays: config.maxExpiryDays || 365,
+      maxTokensPerUser: config.maxTokensPerUser || 25,
+      // Days before expiry at which a reminder is sent, once each
+      reminderDays: config.reminderDays || [7, 1],
+      reminderInterval: config.reminderInterval || 60 * 60 * 1000,
+      // Usage counters are updated in memory and written this often
+      usageFlushInterval: config.usageFlushInterval || 60 * 1000,
+      ...config,
+    };
+
+    this.logger = new Logger('PersonalAccessTokens');
+
+    // id -> record; hash -> id for lookups on every request
+    this.tokens = new Map();
+    this.hashIndex = new Map();
+    this.reminderTimer = null;
+    this.flushTimer = null;
+    this.usageDirty = false;
+    this.saving = Promise.resolve();
+  }
+
+  async initialize() {
+    try {
+      await this.load();
+
+      this.reminderTimer = setInterval(
+        () => this.checkExpiry(),
+        this.config.reminderInterval
+      );
+      this.reminderTimer.unref?.();
+      this.flushTimer = setInterval(
+        () => this.flushUsage(),
+        this.config.usageFlushInterval
+      );
+      this.flushTimer.unref?.();
+      await this.checkExpiry();
+
+      this.logger.info('PersonalAccessTokenStore initialized successfully');
+    } catch (error) {
+      this.logger.error(
+        'Failed to initialize PersonalAccessTokenStore:',
+        error
+      );
+      throw error;
+    }
+  }
+
+  async load() {
+    try {
+      const data = await fs.readFile(this.config.tokensFile, 'utf8');
+      for (const record of JSON.parse(data)) {
+        this.tokens.set(record.id, record);
+        this.hashIndex.set(record.tokenHash, record.id);
+      }
+    } catch (error) {
+      if (error.code !== 'ENOENT') {
+        this.logger.error('Failed to load personal access tokens:', error);
+        throw error;
+      }
+    }
+  }
+
+  // Saves are chained so two writes never overlap on the same file
+  save() {
+    this.usageDirty = false;
+    const write = async () => {
+      try {
+        await fs.mkdir(path.dirname(this.config.tokensFile), {
+          recursive: true,
+        });
+        await fs.writeFile(
+          this.config.tokensFile,
+          JSON.stringify(Array.from(this.tokens.values()), null, 2)
+        );
+      } catch (error) {
+        this.logger.error('Failed to save personal access tokens:', error);
+        throw error;
+      }
+    };
+
+    const saved = this.saving.then(write, write);
+    this.saving = saved.catch(() => {});
+    return saved;
+  }
+
+  async flushUsage() {
+    if (!this.usageDirty) return;
+    try {
+      await this.save();
+    } catch (error) {
+      this.usageDirty = true;
+    }
+  }
+
+  isExpired(record, now = Date.now()) {
+    return new Date(record.expiresAt).getTime() <= now;
+  }
+
+  // Never hand the hash out; the prefix is enough to recognise a token
+  publicRecord({ tokenHash: _tokenHash, ...record }) {
+    return record;
+  }
+
+  // grantable: scopes the user holds, from AuthManager
+  async create(userId, { name, scopes, expiresInDays } = {}, grantable = []) {
+    if (!name || typeof name !== 'string') {
+      throw new Error('Token name is required');
+    }
+
+    const requested = scopes?.length ? [...new Set(scopes)] : ['read'];
+    for (const scope of requested) {
+      if (!TOKEN_SCOPES.includes(scope)) {
+        throw new Error(
+          `Invalid scope: ${scope} (use ${TOKEN_SCOPES.join(', ')})`
+        );
+      }
+      if (!grantable.includes(scope)) {
+        throw new Error(`You cannot grant the ${scope} scope`);
+      }
+    }
+
+    const days = Number(expiresInDays ?? this.config.defaultExpiryDays);
+    if (!Number.isFinite(days) || days <= 0) {
+      throw new Error('expiresInDays must be a positive number');
+    }
+    if (days > this.config.maxExpiryDays) {
+      throw new Error(
+        `Tokens cannot live longer than ${this.config.maxExpiryDays} days`
+      );
+    }
+
+    // Expired tokens no longer work, so they do not count toward the limit
+    const active = this.listForUser(userId).filter(
+      (record) => !this.isExpired(record)
+    );
+    if (active.length >= this.config.maxTokensPerUser) {
+      throw new Error(
+        `Token limit of ${this.config.maxTokensPerUser} reached; revoke one`
+      );
+    }
+
+    const token = `${TOKEN_PREFIX}${crypto.randomBytes(24).toString('hex')}`;
+    const record = {
+      id: crypto.randomUUID(),
+      userId,
+      name: name.trim(),
+      scopes: requested,
+      prefix: token.slice(0, TOKEN_PREFIX.length + 6),
+      tokenHash: hashToken(token),
+      createdAt: new Date().toISOString(),
+      expiresAt: new Date(Date.now() + days * 86400000).toISOString(),
+      lastUsedAt: null,
+      lastUsedIp: null,
+      usageCount: 0,
+      remindersSent: [],
+    };
+
+    this.tokens.set(record.id, record);
+    this.hashIndex.set(record.tokenHash, record.id);
+    await this.save();
+
+    this.emit('token:created', this.publicRecord(record));
+    this.logger.info(`Personal access token created: ${record.id} (${userId})`);
+
+    return { token, record: this.publicRecord(record) };
+  }
+
+  listForUser(userId) {
+    return Array.from(this.tokens.values())
+      .filter((record) => record.userId === userId)
+      .sort((a, b) => b.createdAt.localeCompare(a.createdAt))
+      .map((record) => this.publicRecord(record));
+  }
+
+  async revoke(userId, tokenId) {
+    const record = this.tokens.get(tokenId);
+    if (!record || record.userId !== userId) {
+      throw new Error('Token not found');
+    }
+
+    this.tokens.delete(tokenId);
+    this.hashIndex.delete(record.tokenHash);
+    await this.save();
+
+    this.emit('token:revoked', this.publicRecord(record));
+    this.logger.info(`Personal access token revoked: ${tokenId} (${userId})`);
+
+    return { success: true };
+  }
+
+  async revokeAllForUser(userId) {
+    const records = Array.from(this.tokens.values()).filter(
+      (record) => record.userId === userId
+    );
+    for (const record of records) {
+      this.tokens.delete(record.id);
+      this.hashIndex.delete(record.tokenHash);
+    }
+    if (records.length > 0) {
+      await this.save();
+    }
+  }
+
+  // Resolves a presented token to its record and counts the use
+  async verify(token, { ipAddress } = {}) {
+    const id = this.hashIndex.get(hashToken(token));
+    const record = id && this.tokens.get(id);
+    if (!record) {
+      throw new Error('Invalid token');
+    }
+    if (this.isExpired(record)) {
+      throw new Error('Token expired');
+    }
+
+    // Written by the flush timer rather than on every request
+    record.usageCount++;
+    record.lastUsedAt = new Date().toISOString();
+    record.lastUsedIp = ipAddress || null;
+    this.usageDirty = true;
+
+    return this.publicRecord(record);
+  }
+
+  // Emits token:expiring once per reminder threshold, and token:expired once
+  async checkExpiry(now = Date.now()) {
+    let changed = false;
+
+    for (const record of this.tokens.values()) {
+      const remaining = new Date(record.expiresAt).getTime() - now;
+
+      if (remaining <= 0) {
+        if (!record.remindersSent.includes('expired')) {
+          record.remindersSent.push('expired');
+          changed = true;
+          this.emit('token:expired', this.publicRecord(record));
+        }
+        continue;
+      }
+
+      // Only the tightest threshold crossed is sent, so a token created
+      // with a short expiry does not get every reminder at once
+      const due = this.config.reminderDays
+        .filter((days) => remaining <= days * 86400000)
+        .sort((a, b) => a - b)[0];
+      if (due !== undefined && !record.remindersSent.includes(due)) {
+        record.remindersSent.push(
+          ...this.config.reminderDays.filter((days) => days >= due)
+        );
+        record.remindersSent = [...new Set(record.remindersSent)];
+        changed = true;
+        this.emit('token:expiring', {
+          ...this.publicRecord(record),
+          daysLeft: Math.ceil(remaining / 86400000),
+        });
+      }
+    }
+
+    if (changed) {
+      await this.save();
+    }
+  }
+
+  async stop() {
+    try {
+      if (this.reminderTimer) {
+        clearInterval(this.reminderTimer);
+        this.reminderTimer = null;
+      }
+      clearInterval(this.flushTimer);
+      this.flushTimer = null;
+      // Persist usage counters
+      await this.save();
+      this.logger.info('PersonalAccessTokenStore stopped successfully');
+    } catch (error) {
+      this.logger.error('Error stopping PersonalAccessTokenStore:', error);
+      throw error;
+    }
+  }
+}
+
+export {
+  PersonalAccessTokenStore,
+  TOKEN_SCOPES,
+  TOKEN_PREFIX,
+  isPersonalAccessToken,
+};
@@ -0,0 +1,225 @@
+/**
+ * Tests for Personal Access Tokens
+ */
+
+import {
+  PersonalAccessTokenStore,
+  TOKEN_PREFIX,
+  isPersonalAccessToken,
+} from './personal-access-tokens.js';
+import { AuthManager } from './auth-manager.js';
+import { jest } from '@jest/globals';
+import { promises as fs } from 'fs';
+import os from 'os';
+import path from 'path';
+
+const DAY = 86400000;
+
+describe('PersonalAccessTokenStore', () => {
+  let dir;
+  let store;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'tokens-'));
+    store = new PersonalAccessTokenStore({
+      tokensFile: path.join(dir, 'tokens.json'),
+      maxTokensPerUser: 2,
+    });
+  });
+
+  afterEach(async () => {
+    await store.stop();
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  const expire = (record) => {
+    store.tokens.get(record.id).expiresAt = new Date(
+      Date.now() - 1000
+    ).toISOString();
+  };
+
+  describe('create', () => {
+    it('should return the token once and store only its hash', async () => {
+      const { token, record } = await store.create(
+        'user-1',
+        { name: 'ci' },
+        ['read']
+      );
+
+      expect(isPersonalAccessToken(token)).toBe(true);
+      expect(token.startsWith(TOKEN_PREFIX)).toBe(true);
+      expect(record.tokenHash).toBeUndefined();
+      expect(record.scopes).toEqual(['read']);
+
+      const saved = await fs.readFile(store.config.tokensFile, 'utf8');
+      expect(saved).not.toContain(token);
+    });
+
+    it('should refuse scopes outside the grantable list', async () => {
+      await expect(
+        store.create('user-1', { name: 'ci', scopes: ['admin'] }, ['read'])
+      ).rejects.toThrow('You cannot grant the admin scope');
+      await expect(
+        store.create('user-1', { name: 'ci', scopes: ['root'] }, ['read'])
+      ).rejects.toThrow('Invalid scope: root');
+    });
+
+    it('should cap the expiry', async () => {
+      await expect(
+        store.create('user-1', { name: 'ci', expiresInDays: 400 }, ['read'])
+      ).rejects.toThrow('Tokens cannot live longer than 365 days');
+    });
+
+    it('should not count expired tokens toward the limit', async () => {
+      const first = await store.create('user-1', { name: 'a' }, ['read']);
+      await store.create('user-1', { name: 'b' }, ['read']);
+
+      await expect(
+        store.create('user-1', { name: 'c' }, ['read'])
+      ).rejects.toThrow('Token limit of 2 reached');
+
+      expire(first.record);
+      await expect(
+        store.create('user-1', { name: 'c' }, ['read'])
+      ).resolves.toBeDefined();
+    });
+  });
+
+  describe('verify', () => {
+    it('should resolve a token and count its use without saving', async () => {
+      const { token } = await store.create('user-1', { name: 'ci' }, ['read']);
+      const save = jest.spyOn(store, 'save');
+
+      const record = await store.verify(token, { ipAddress: '10.0.0.1' });
+
+      expect(record.userId).toBe('user-1');
+      expect(record.usageCount).toBe(1);
+      expect(record.lastUsedIp).toBe('10.0.0.1');
+      expect(save).not.toHaveBeenCalled();
+
+      await store.flushUsage();
+      expect(save).toHaveBeenCalledTimes(1);
+    });
+
+    it('should reject unknown, revoked and expired tokens', async () => {
+      const { token, record } = await store.create('user-1', { name: 'ci' }, [
+        'read',
+      ]);
+
+      await expect(store.verify(`${TOKEN_PREFIX}nope`)).rejects.toThrow(
+        'Invalid token'
+      );
+
+      expire(record);
+      await expect(store.verify(token)).rejects.toThrow('Token expired');
+
+      await store.revoke('user-1', record.id);
+      await expect(store.verify(token)).rejects.toThrow('Invalid token');
+    });
+  });
+
+  describe('checkExpiry', () => {
+    it('should remind once per threshold and once on expiry', async () => {
+      const { record } = await store.create(
+        'user-1',
+        { name: 'ci', expiresInDays: 10 },
+        ['read']
+      );
+      const expiring = jest.fn();
+      const expired = jest.fn();
+      store.on('token:expiring', expiring);
+      store.on('token:expired', expired);
+
+      const createdAt = new Date(record.createdAt).getTime();
+      await store.checkExpiry(createdAt + 4 * DAY);
+      expect(expiring).toHaveBeenCalledTimes(1);
+      expect(expiring).toHaveBeenCalledWith(
+        expect.objectContaining({ id: record.id, daysLeft: 6 })
+      );
+
+      await store.checkExpiry(createdAt + 5 * DAY);
+      expect(expiring).toHaveBeenCalledTimes(1);
+
+      await store.checkExpiry(createdAt + 9.5 * DAY);
+      expect(expiring).toHaveBeenCalledTimes(2);
+
+      await store.checkExpiry(createdAt + 11 * DAY);
+      await store.checkExpiry(createdAt + 12 * DAY);
+      expect(expired).toHaveBeenCalledTimes(1);
+    });
+
+    it('should send one reminder for short-lived tokens', async () => {
+      const { record } = await store.create(
+        'user-1',
+        { name: 'ci', expiresInDays: 1 },
+        ['read']
+      );
+      const expiring = jest.fn();
+      store.on('token:expiring', expiring);
+
+      await store.checkExpiry(new Date(record.createdAt).getTime());
+      await store.checkExpiry(new Date(record.createdAt).getTime() + 1000);
+
+      expect(expiring).toHaveBeenCalledTimes(1);
+    });
+  });
+});
+
+describe('AuthManager personal tokens', () => {
+  let dir;
+  let authManager;
+  let admin;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'auth-tokens-'));
+    authManager = new AuthManager({
+      usersFile: path.join(dir, 'users.json'),
+      sessionsFile: path.join(dir, 'sessions.json'),
+      bcryptRounds: 4,
+      personalTokens: { tokensFile: path.join(dir, 'tokens.json') },
+    });
+    admin = await authManager.createUser({
+      username: 'admin',
+      password: 'correct-horse-battery',
+      role: 'admin',
+    });
+  });
+
+  afterEach(async () => {
+    await authManager.personalTokens.stop();
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  it('should let a session mint any scope the user holds', async () => {
+    const { record } = await authManager.createPersonalToken(admin.id, {
+      name: 'ops',
+      scopes: ['read', 'write', 'admin'],
+    });
+
+    expect(record.scopes).toEqual(['read', 'write', 'admin']);
+  });
+
+  it('should limit token callers to their own scopes', async () => {
+    const { token } = await authManager.createPersonalToken(admin.id, {
+      name: 'ci',
+      scopes: ['read'],
+    });
+    const { user: caller } = await authManager.verifyPersonalToken(token);
+
+    expect(caller.role).toBe('user');
+    await expect(
+      authManager.createPersonalToken(
+        admin.id,
+        { name: 'escalated', scopes: ['admin'] },
+        caller
+      )
+    ).rejects.toThrow('You cannot grant the admin scope');
+    await expect(
+      authManager.createPersonalToken(
+        admin.id,
+        { name: 'copy', scopes: ['read'] },
+        caller
+      )
+    ).resolves.toBeDefined();
+  });
+});
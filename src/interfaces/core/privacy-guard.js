@@ -0,0 +1,258 @@
+/**
+ * Privacy Guard
+ * Org and project privacy modes that decide whether user content may reach
+ * the learning engine, embeddings or LLM calls, with an audit of which data
+ * classes each subsystem consumed
+ */
+
+import { EventEmitter } from 'events';
+import { promises as fs } from 'fs';
+import path from 'path';
+import { Logger } from './logger.js';
+
+// Ordered from least to most restrictive
+const PRIVACY_MODES = ['standard', 'local-only', 'strict'];
+
+const PRIVACY_SUBSYSTEMS = ['learning', 'embeddings', 'llm'];
+
+const DATA_CLASSES = {
+  USER_CONTENT: 'user-content', // project names, descriptions, task text
+  FEEDBACK: 'feedback', // free-form user feedback
+  USAGE_PATTERNS: 'usage-patterns', // derived activity signals
+  SYSTEM_METRICS: 'system-metrics', // resource usage, counts, timings
+};
+
+// Only these classes are restricted by a privacy mode
+const PROTECTED_CLASSES = [DATA_CLASSES.USER_CONTENT, DATA_CLASSES.FEEDBACK];
+
+// Project fields that carry no user-authored text
+const PROJECT_SAFE_FIELDS = [
+  'id',
+  'template',
+  'private',
+  'status',
+  'createdAt',
+  'updatedAt',
+];
+
+function redactProject(project) {
+  return Object.fromEntries(
+    PROJECT_SAFE_FIELDS.filter((field) => field in project).map((field) => [
+      field,
+      project[field],
+    ])
+  );
+}
+
+class PrivacyGuard extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      stateFile: config.stateFile || './data/privacy.json',
+      defaultMode: config.defaultMode || 'standard',
+      // Providers that run on infrastructure we host
+      localProviders: config.localProviders || ['local', 'ollama'],
+      ...config,
+    };
+
+    this.logger = new Logger('PrivacyGuard');
+    this.orgMode = this.config.defaultMode;
+    this.projectModes = new Map();
+
+    // "subsystem|dataClass" -> { allowed, blocked, lastAllowed, lastBlocked }
+    this.audit = new Map();
+  }
+
+  async initialize() {
+    try {
+      await this.loadState();
+      this.logger.info('PrivacyGuard initialized successfully');
+    } catch (error) {
+      this.logger.error('Failed to initialize PrivacyGuard:', error);
+      throw error;
+    }
+  }
+
+  async loadState() {
+    try {
+      const data = JSON.parse(await fs.readFile(this.config.stateFile, 'utf8'));
+
+      this.orgMode = data.orgMode || this.config.defaultMode;
+      this.projectModes = new Map(Object.entries(data.projectModes || {}));
+      for (const entry of data.audit || []) {
+        this.audit.set(`${entry.subsystem}|${entry.dataClass}`, entry);
+      }
+    } catch (error) {
+      if (error.code !== 'ENOENT') {
+        this.logger.error('Failed to load privacy state:', error);
+        throw error;
+      }
+    }
+  }
+
+  async saveState() {
+    try {
+      await fs.mkdir(path.dirname(this.config.stateFile), { recursive: true });
+      await fs.writeFile(
+        this.config.stateFile,
+        JSON.stringify(
+          {
+            orgMode: this.orgMode,
+            projectModes: Object.fromEntries(this.projectModes),
+            audit: this.getAudit(),
+          },
+          null,
+          2
+        )
+      );
+    } catch (error) {
+      this.logger.error('Failed to save privacy state:', error);
+      throw error;
+    }
+  }
+
+  validateMode(mode) {
+    if (!PRIVACY_MODES.includes(mode)) {
+      throw new Error(
+        `Invalid privacy mode: ${mode} (use ${PRIVACY_MODES.join(', ')})`
+      );
+    }
+  }
+
+  async setOrgMode(mode, changedBy) {
+    this.validateMode(mode);
+
+    this.orgMode = mode;
+    await this.saveState();
+
+    this.emit('privacy:changed', { scope: 'org', mode, changedBy });
+    this.logger.info(`Org privacy mode set to ${mode}`);
+    return this.getSettings();
+  }
+
+  // A null mode clears the override so the project follows the org
+  async setProjectMode(projectId, mode, changedBy) {
+    if (mode === null) {
+      this.projectModes.delete(projectId);
+    } else {
+      this.validateMode(mode);
+      this.projectModes.set(projectId, mode);
+    }
+    await this.saveState();
+
+    this.emit('privacy:changed', {
+      scope: 'project',
+      projectId,
+      mode,
+      changedBy,
+    });
+    this.logger.info(`Project ${projectId} privacy mode set to ${mode}`);
+    return this.getProjectSettings(projectId);
+  }
+
+  // A project can tighten the org mode but never loosen it
+  getEffectiveMode(projectId) {
+    const projectMode = projectId && this.projectModes.get(projectId);
+    if (!projectMode) return this.orgMode;
+
+    return PRIVACY_MODES.indexOf(projectMode) >
+      PRIVACY_MODES.indexOf(this.orgMode)
+      ? projectMode
+      : this.orgMode;
+  }
+
+  /**
+   * Single decision point for every subsystem. The learning engine runs
+   * in-process and counts as local; embeddings and LLM callers pass the
+   * provider they are about to send data to.
+   */
+  authorize(subsystem, dataClass, { projectId, provider } = {}) {
+    if (!PRIVACY_SUBSYSTEMS.includes(subsystem)) {
+      throw new Error(`Unknown privacy subsystem: ${subsystem}`);
+    }
+
+    const mode = this.getEffectiveMode(projectId);
+    const local =
+      subsystem === 'learning' || this.config.localProviders.includes(provider);
+
+    let allowed = true;
+    if (PROTECTED_CLASSES.includes(dataClass)) {
+      allowed = mode === 'standard' || (mode === 'local-only' && local);
+    }
+
+    this.recordAudit(subsystem, dataClass, allowed);
+    if (!allowed) {
+      this.logger.debug(`Blocked ${dataClass} for ${subsystem}`, {
+        mode,
+        projectId,
+        provider,
+      });
+    }
+
+    return allowed;
+  }
+
+  recordAudit(subsystem, dataClass, allowed) {
+    const key = `${subsystem}|${dataClass}`;
+    const entry = this.audit.get(key) || {
+      subsystem,
+      dataClass,
+      allowed: 0,
+      blocked: 0,
+      lastAllowed: null,
+      lastBlocked: null,
+    };
+
+    if (allowed) {
+      entry.allowed++;
+      entry.lastAllowed = new Date().toISOString();
+    } else {
+      entry.blocked++;
+      entry.lastBlocked = new Date().toISOString();
+    }
+    this.audit.set(key, entry);
+  }
+
+  getAudit(subsystem) {
+    return Array.from(this.audit.values()).filter(
+      (entry) => !subsystem || entry.subsystem === subsystem
+    );
+  }
+
+  getSettings() {
+    return {
+      orgMode: this.orgMode,
+      projectModes: Object.fromEntries(this.projectModes),
+      modes: PRIVACY_MODES,
+      localProviders: this.config.localProviders,
+    };
+  }
+
+  getProjectSettings(projectId) {
+    return {
+      projectId,
+      orgMode: this.orgMode,
+      projectMode: this.projectModes.get(projectId) || null,
+      effectiveMode: this.getEffectiveMode(projectId),
+    };
+  }
+
+  async stop() {
+    try {
+      // Persist audit counters accumulated since the last settings change
+      await this.saveState();
+      this.logger.info('PrivacyGuard stopped successfully');
+    } catch (error) {
+      this.logger.error('Error stopping PrivacyGuard:', error);
+      throw error;
+    }
+  }
+}
+
+export {
+  PrivacyGuard,
+  PRIVACY_MODES,
+  PRIVACY_SUBSYSTEMS,
+  DATA_CLASSES,
+  redactProject,
+};
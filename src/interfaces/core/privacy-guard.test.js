@@ -0,0 +1,157 @@
+/**
+ * Tests for Privacy Guard
+ */
+
+import { PrivacyGuard, DATA_CLASSES, redactProject } from './privacy-guard.js';
+import { jest } from '@jest/globals';
+import { promises as fs } from 'fs';
+import os from 'os';
+import path from 'path';
+
+describe('PrivacyGuard', () => {
+  let dir;
+  let privacyGuard;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'privacy-'));
+    privacyGuard = new PrivacyGuard({
+      stateFile: path.join(dir, 'privacy.json'),
+    });
+  });
+
+  afterEach(async () => {
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  const { USER_CONTENT, FEEDBACK, SYSTEM_METRICS } = DATA_CLASSES;
+
+  describe('authorize', () => {
+    it('should allow everything in standard mode', () => {
+      expect(privacyGuard.authorize('llm', USER_CONTENT)).toBe(true);
+      expect(privacyGuard.authorize('embeddings', FEEDBACK)).toBe(true);
+    });
+
+    it('should keep user content local in local-only mode', async () => {
+      await privacyGuard.setOrgMode('local-only');
+
+      expect(privacyGuard.authorize('learning', USER_CONTENT)).toBe(true);
+      expect(
+        privacyGuard.authorize('llm', USER_CONTENT, { provider: 'ollama' })
+      ).toBe(true);
+      expect(
+        privacyGuard.authorize('llm', USER_CONTENT, { provider: 'openai' })
+      ).toBe(false);
+    });
+
+    it('should block user content everywhere in strict mode', async () => {
+      await privacyGuard.setOrgMode('strict');
+
+      expect(privacyGuard.authorize('learning', USER_CONTENT)).toBe(false);
+      expect(
+        privacyGuard.authorize('llm', FEEDBACK, { provider: 'local' })
+      ).toBe(false);
+      expect(privacyGuard.authorize('llm', SYSTEM_METRICS)).toBe(true);
+    });
+
+    it('should reject unknown subsystems', () => {
+      expect(() => privacyGuard.authorize('analytics', USER_CONTENT)).toThrow(
+        'Unknown privacy subsystem: analytics'
+      );
+    });
+
+    it('should audit allowed and blocked requests', async () => {
+      await privacyGuard.setOrgMode('strict');
+
+      privacyGuard.authorize('llm', USER_CONTENT);
+      privacyGuard.authorize('llm', USER_CONTENT);
+      privacyGuard.authorize('llm', SYSTEM_METRICS);
+
+      expect(privacyGuard.getAudit('llm')).toEqual([
+        expect.objectContaining({
+          dataClass: USER_CONTENT,
+          allowed: 0,
+          blocked: 2,
+        }),
+        expect.objectContaining({
+          dataClass: SYSTEM_METRICS,
+          allowed: 1,
+          blocked: 0,
+        }),
+      ]);
+      expect(privacyGuard.getAudit('learning')).toEqual([]);
+    });
+  });
+
+  describe('project modes', () => {
+    it('should let a project tighten but not loosen the org mode', async () => {
+      await privacyGuard.setOrgMode('local-only');
+      await privacyGuard.setProjectMode('p1', 'strict');
+      await privacyGuard.setProjectMode('p2', 'standard');
+
+      expect(privacyGuard.getEffectiveMode('p1')).toBe('strict');
+      expect(privacyGuard.getEffectiveMode('p2')).toBe('local-only');
+      expect(
+        privacyGuard.authorize('learning', USER_CONTENT, { projectId: 'p1' })
+      ).toBe(false);
+    });
+
+    it('should clear an override with a null mode', async () => {
+      await privacyGuard.setProjectMode('p1', 'strict');
+
+      const settings = await privacyGuard.setProjectMode('p1', null);
+
+      expect(settings).toMatchObject({
+        projectMode: null,
+        effectiveMode: 'standard',
+      });
+    });
+
+    it('should reject invalid modes', async () => {
+      await expect(privacyGuard.setOrgMode('private')).rejects.toThrow(
+        'Invalid privacy mode: private'
+      );
+      await expect(privacyGuard.setProjectMode('p1', 'open')).rejects.toThrow(
+        'Invalid privacy mode: open'
+      );
+    });
+  });
+
+  describe('persistence', () => {
+    it('should restore modes and audit counters', async () => {
+      const changed = jest.fn();
+      privacyGuard.on('privacy:changed', changed);
+
+      await privacyGuard.setOrgMode('local-only', 'admin-1');
+      await privacyGuard.setProjectMode('p1', 'strict', 'admin-1');
+      privacyGuard.authorize('llm', USER_CONTENT, { projectId: 'p1' });
+      await privacyGuard.stop();
+
+      const reloaded = new PrivacyGuard({
+        stateFile: privacyGuard.config.stateFile,
+      });
+      await reloaded.initialize();
+
+      expect(reloaded.getProjectSettings('p1')).toMatchObject({
+        orgMode: 'local-only',
+        projectMode: 'strict',
+      });
+      expect(reloaded.getAudit()[0].blocked).toBe(1);
+      expect(changed).toHaveBeenCalledWith(
+        expect.objectContaining({ scope: 'org', changedBy: 'admin-1' })
+      );
+    });
+  });
+
+  describe('redactProject', () => {
+    it('should keep only fields without user-authored text', () => {
+      expect(
+        redactProject({
+          id: 'p1',
+          name: 'Customer portal',
+          description: 'For Acme',
+          status: 'running',
+        })
+      ).toEqual({ id: 'p1', status: 'running' });
+    });
+  });
+});
@@ -6,30 +6,58 @@
 import { EventEmitter } from 'events';
 import { promises as fs } from 'fs';
 import path from 'path';
+import crypto from 'crypto';
 import { v4 as uuidv4 } from 'uuid';
 import { Logger } from './logger.js';
 import { ConfigManager } from './config-manager.js';
 import { FileManager } from './file-manager.js';
 import { ProcessManager } from './process-manager.js';
 
+// Standardized ownership fields kept under project.metadata
+const OWNERSHIP_FIELDS = [
+  'owner',
+  'cost_center',
+  'environment',
+  'compliance_tier',
+];
+
+// Never copied into a clone; dependencies are reinstalled
+const CLONE_SKIP = ['node_modules'];
+
+// Copied only when a clone asks for history
+const HISTORY_ENTRIES = ['.git'];
+
 class ProjectManager extends EventEmitter {
   constructor(config = {}) {
     super();
+    // Spread first so ownershipValues below is merged, not replaced
     this.config = {
+      ...config,
       projectsDir: config.projectsDir || './projects',
       templatesDir: config.templatesDir || './templates',
       defaultTemplate: config.defaultTemplate || 'default',
-      ...config,
+      // Allowed values per ownership field; null accepts any non-empty string
+      ownershipValues: {
+        owner: null,
+        cost_center: null,
+        environment: ['development', 'staging', 'production'],
+        compliance_tier: ['none', 'internal', 'confidential', 'regulated'],
+        ...config.ownershipValues,
+      },
+      // Files copied between clone progress events
+      cloneProgressInterval: config.cloneProgressInterval || 50,
+      cloneJobHistory: config.cloneJobHistory || 20,
     };
 
     this.logger = new Logger('ProjectManager');
-    this.configManager = new ConfigManager();
+    this.configManager = config.configManager || new ConfigManager();
     this.fileManager = new FileManager();
     this.processManager = new ProcessManager();
 
     this.projects = new Map();
     this.runningProjects = new Map();
     this.templates = new Map();
+    this.cloneJobs = new Map();
 
     // Add default template immediately for testing
     this.addDefaultTemplate();
@@ -219,6 +247,8 @@ class ProjectManager extends EventEmitter {
         throw new Error('Project name is required');
       }
 
+      this.validateOwnership(config.metadata);
+
       const projectId = uuidv4();
       const projectName = config.name.replace(/[^a-zA-Z0-9-_]/g, '-');
       const projectPath = path.join(this.config.projectsDir, projectName);
@@ -272,6 +302,31 @@ class ProjectManager extends EventEmitter {
     }
   }
 
+  validateOwnership(metadata) {
+    for (const field of OWNERSHIP_FIELDS) {
+      const value = metadata?.[field];
+      if (value === undefined || value === null) continue;
+
+      if (typeof value !== 'string' || value.trim() === '') {
+        throw new Error(`Invalid ${field}: must be a non-empty string`);
+      }
+
+      const allowed = this.config.ownershipValues[field];
+      if (allowed && !allowed.includes(value)) {
+        throw new Error(
+          `Invalid ${field}: ${value} (expected ${allowed.join(', ')})`
+        );
+      }
+    }
+  }
+
+  getOwnership(project) {
+    const metadata = project.metadata || {};
+    return Object.fromEntries(
+      OWNERSHIP_FIELDS.map((field) => [field, metadata[field] || null])
+    );
+  }
+
   async createProjectFromTemplate(projectPath, template, config) {
     const replacements = {
       '{{projectName}}': config.name,
@@ -320,6 +375,187 @@ class ProjectManager extends EventEmitter {
     }
   }
 
+  /**
+   * Clone a project as a background job and return the job right away.
+   * Tags and metadata are copied unless excluded. Files are copied by
+   * default; without them the clone starts from the source's template. Git
+   * history comes along only with includeHistory.
+   */
+  async cloneProject(projectId, options = {}) {
+    const source = await this.getProject(projectId);
+
+    if (!options.name || options.name.trim() === '') {
+      throw new Error('A name for the clone is required');
+    }
+    const projectName = options.name.replace(/[^a-zA-Z0-9-_]/g, '-');
+    const projectPath = path.join(this.config.projectsDir, projectName);
+
+    const pending = Array.from(this.cloneJobs.values()).some(
+      (job) => job.status === 'running' && job.targetPath === projectPath
+    );
+    if (pending || (await this.fileManager.exists(projectPath))) {
+      throw new Error('Project name already exists');
+    }
+
+    const job = {
+      id: uuidv4(),
+      sourceId: source.id,
+      name: options.name,
+      targetPath: projectPath,
+      include: {
+        files: options.includeFiles !== false,
+        history: options.includeHistory === true,
+        tags: options.includeTags !== false,
+        metadata: options.includeMetadata !== false,
+      },
+      status: 'running',
+      totalFiles: 0,
+      copiedFiles: 0,
+      projectId: null,
+      error: null,
+      createdBy: options.createdBy || null,
+      startedAt: new Date().toISOString(),
+      finishedAt: null,
+    };
+    this.cloneJobs.set(job.id, job);
+    this.trimCloneJobs();
+
+    this.runClone(job, source)
+      .then(
+        (project) => {
+          job.status = 'completed';
+          job.projectId = project.id;
+        },
+        async (error) => {
+          job.status = 'failed';
+          job.error = error.message;
+          this.logger.error(`Failed to clone project ${source.name}:`, error);
+          await fs
+            .rm(projectPath, { recursive: true, force: true })
+            .catch(() => {});
+        }
+      )
+      .then(() => {
+        job.finishedAt = new Date().toISOString();
+        this.emit(`project:clone-${job.status}`, this.publicCloneJob(job));
+      });
+
+    return this.publicCloneJob(job);
+  }
+
+  async runClone(job, source) {
+    if (job.include.files) {
+      const { directories, files } = await this.listCloneEntries(
+        source.path,
+        job.include.history
+      );
+      job.totalFiles = files.length;
+
+      for (const directory of directories) {
+        await this.fileManager.ensureDirectory(
+          path.join(job.targetPath, directory)
+        );
+      }
+      for (const file of files) {
+        await this.fileManager.copyFile(
+          path.join(source.path, file),
+          path.join(job.targetPath, file)
+        );
+        job.copiedFiles++;
+        if (job.copiedFiles % this.config.cloneProgressInterval === 0) {
+          this.emit('project:clone-progress', this.publicCloneJob(job));
+        }
+      }
+    } else {
+      const template =
+        this.templates.get(source.template) ||
+        this.templates.get(this.config.defaultTemplate);
+      await fs.mkdir(job.targetPath, { recursive: true });
+      await this.createProjectFromTemplate(job.targetPath, template, {
+        name: job.name,
+        description: source.description,
+      });
+    }
+
+    const project = {
+      id: uuidv4(),
+      name: job.name,
+      description: source.description,
+      template: source.template,
+      private: source.private,
+      path: job.targetPath,
+      configPath: path.join(job.targetPath, 'project.json'),
+      status: 'created',
+      createdAt: new Date().toISOString(),
+      updatedAt: new Date().toISOString(),
+      tags: job.include.tags ? [...source.tags] : [],
+      metadata: job.include.metadata ? structuredClone(source.metadata) : {},
+      clonedFrom: source.id,
+    };
+
+    await fs.writeFile(project.configPath, JSON.stringify(project, null, 2));
+    this.projects.set(project.id, project);
+
+    this.emit('project:created', project);
+    this.logger.info(
+      `Project cloned: ${source.name} -> ${project.name} (${job.copiedFiles} files)`
+    );
+
+    return project;
+  }
+
+  // Relative directories and regular files to copy; symlinks are skipped
+  async listCloneEntries(root, includeHistory, prefix = '') {
+    const result = { directories: [], files: [] };
+    const entries = await fs.readdir(path.join(root, prefix), {
+      withFileTypes: true,
+    });
+
+    for (const entry of entries) {
+      if (CLONE_SKIP.includes(entry.name)) continue;
+      if (HISTORY_ENTRIES.includes(entry.name) && !includeHistory) continue;
+      // The clone gets its own project.json
+      if (prefix === '' && entry.name === 'project.json') continue;
+
+      const relative = path.join(prefix, entry.name);
+      if (entry.isDirectory()) {
+        const nested = await this.listCloneEntries(
+          root,
+          includeHistory,
+          relative
+        );
+        result.directories.push(relative, ...nested.directories);
+        result.files.push(...nested.files);
+      } else if (entry.isFile()) {
+        result.files.push(relative);
+      }
+    }
+
+    return result;
+  }
+
+  getCloneJob(jobId) {
+    const job = this.cloneJobs.get(jobId);
+    if (!job) {
+      throw new Error(`Clone job not found: ${jobId}`);
+    }
+    return this.publicCloneJob(job);
+  }
+
+  // The target path stays server-side
+  publicCloneJob({ targetPath: _targetPath, ...job }) {
+    return { ...job, include: { ...job.include } };
+  }
+
+  trimCloneJobs() {
+    const finished = Array.from(this.cloneJobs.values()).filter(
+      (job) => job.status !== 'running'
+    );
+    finished
+      .slice(0, Math.max(0, this.cloneJobs.size - this.config.cloneJobHistory))
+      .forEach((job) => this.cloneJobs.delete(job.id));
+  }
+
   async listProjects(options = {}) {
     try {
       const projects = Array.from(this.projects.values());
@@ -344,6 +580,15 @@ class ProjectManager extends EventEmitter {
         );
       }
 
+      // Exact match on ownership fields
+      for (const field of OWNERSHIP_FIELDS) {
+        if (options[field]) {
+          filteredProjects = filteredProjects.filter(
+            (p) => p.metadata?.[field] === options[field]
+          );
+        }
+      }
+
       return filteredProjects.sort(
         (a, b) => new Date(b.updatedAt) - new Date(a.updatedAt)
       );
@@ -380,10 +625,18 @@ class ProjectManager extends EventEmitter {
     try {
       const project = await this.getProject(projectId);
 
-      // Update project configuration
+      if (updates.metadata) {
+        this.validateOwnership(updates.metadata);
+      }
+
+      // Update project configuration; metadata fields merge, so setting
+      // one ownership field keeps the others
       const updatedProject = {
         ...project,
         ...updates,
+        ...(updates.metadata && {
+          metadata: { ...project.metadata, ...updates.metadata },
+        }),
         updatedAt: new Date().toISOString(),
       };
 
@@ -396,7 +649,7 @@ class ProjectManager extends EventEmitter {
       // Update in memory
       this.projects.set(project.id, updatedProject);
 
-      this.emit('project:updated', updatedProject);
+      this.emit('project:updated', updatedProject, Object.keys(updates));
       this.logger.info(`Project updated: ${project.name} (${project.id})`);
 
       return updatedProject;
@@ -649,6 +902,52 @@ class ProjectManager extends EventEmitter {
     };
   }
 
+  // Embeddable widget access; the token is returned once and only its
+  // hash is kept on the project. Written without project:updated, which
+  // would send the hash to socket and watcher feeds.
+  async createWidgetToken(projectId) {
+    const project = await this.getProject(projectId);
+    const token = crypto.randomBytes(24).toString('hex');
+
+    await this.saveWidgetToken(project, {
+      widgetTokenHash: this.hashWidgetToken(token),
+      widgetTokenCreatedAt: new Date().toISOString(),
+    });
+
+    return token;
+  }
+
+  async revokeWidgetToken(projectId) {
+    const project = await this.getProject(projectId);
+    await this.saveWidgetToken(project, {
+      widgetTokenHash: null,
+      widgetTokenCreatedAt: null,
+    });
+  }
+
+  async saveWidgetToken(project, fields) {
+    const updatedProject = { ...project, ...fields };
+
+    await fs.writeFile(
+      project.configPath,
+      JSON.stringify(updatedProject, null, 2)
+    );
+    this.projects.set(project.id, updatedProject);
+  }
+
+  hashWidgetToken(token) {
+    return crypto.createHash('sha256').update(String(token)).digest('hex');
+  }
+
+  verifyWidgetToken(project, token) {
+    if (!token || !project.widgetTokenHash) return false;
+
+    return crypto.timingSafeEqual(
+      Buffer.from(this.hashWidgetToken(token), 'hex'),
+      Buffer.from(project.widgetTokenHash, 'hex')
+    );
+  }
+
   async stop() {
     try {
       // Stop all running projects
@@ -668,4 +967,4 @@ class ProjectManager extends EventEmitter {
   }
 }
 
-export { ProjectManager };
+export { ProjectManager, OWNERSHIP_FIELDS };
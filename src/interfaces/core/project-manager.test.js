@@ -5,6 +5,7 @@
 import { ProjectManager } from './project-manager.js';
 import { jest } from '@jest/globals';
 import { promises as fs } from 'fs';
+import os from 'os';
 import path from 'path';
 
 describe('ProjectManager', () => {
@@ -47,6 +48,30 @@ describe('ProjectManager', () => {
     });
   });
 
+  describe('ownership', () => {
+    it('should keep default allowed values alongside configured ones', () => {
+      const manager = new ProjectManager({
+        ownershipValues: { owner: ['team-a'] },
+      });
+
+      expect(manager.config.ownershipValues.owner).toEqual(['team-a']);
+      expect(manager.config.ownershipValues.environment).toContain(
+        'production'
+      );
+    });
+
+    it('should accept missing metadata', () => {
+      expect(() => projectManager.validateOwnership(null)).not.toThrow();
+      expect(() => projectManager.validateOwnership(undefined)).not.toThrow();
+    });
+
+    it('should reject values outside the allowed list', () => {
+      expect(() =>
+        projectManager.validateOwnership({ environment: 'qa' })
+      ).toThrow('Invalid environment');
+    });
+  });
+
   describe('createProject', () => {
     it('should create a new project with valid config', async () => {
       const config = {
@@ -175,4 +200,129 @@ describe('ProjectManager', () => {
       ).rejects.toThrow('Project not found');
     });
   });
+
+  describe('cloneProject', () => {
+    let dir;
+    let manager;
+    let source;
+
+    beforeEach(async () => {
+      dir = await fs.mkdtemp(path.join(os.tmpdir(), 'clone-'));
+      manager = new ProjectManager({ projectsDir: dir });
+      source = await manager.createProject({
+        name: 'source',
+        tags: ['api'],
+        metadata: { owner: 'team-a' },
+      });
+      const dependency = path.join(source.path, 'node_modules', 'dep');
+      await fs.mkdir(dependency, { recursive: true });
+      await fs.writeFile(path.join(dependency, 'index.js'), '');
+      await fs.mkdir(path.join(source.path, '.git'));
+      await fs.writeFile(path.join(source.path, '.git', 'HEAD'), 'ref');
+    });
+
+    afterEach(async () => {
+      await fs.rm(dir, { recursive: true, force: true });
+    });
+
+    // Resolves with the job once it completes or fails
+    const clone = async (options) => {
+      const finished = new Promise((resolve) => {
+        manager.once('project:clone-completed', resolve);
+        manager.once('project:clone-failed', resolve);
+      });
+      const job = await manager.cloneProject(source.id, options);
+      expect(job.status).toBe('running');
+      expect(job.targetPath).toBeUndefined();
+      return finished;
+    };
+
+    it('should copy files, tags and metadata by default', async () => {
+      const job = await clone({ name: 'copy' });
+
+      expect(job.status).toBe('completed');
+      const project = await manager.getProject(job.projectId);
+      expect(project).toMatchObject({
+        tags: ['api'],
+        metadata: { owner: 'team-a' },
+        clonedFrom: source.id,
+      });
+
+      const entries = await fs.readdir(project.path);
+      expect(entries).toContain('README.md');
+      expect(entries).not.toContain('node_modules');
+      expect(entries).not.toContain('.git');
+      const config = JSON.parse(
+        await fs.readFile(path.join(project.path, 'project.json'), 'utf8')
+      );
+      expect(config.id).toBe(project.id);
+    });
+
+    it('should honor the include flags', async () => {
+      const job = await clone({
+        name: 'bare',
+        includeHistory: true,
+        includeTags: false,
+        includeMetadata: false,
+      });
+
+      const project = await manager.getProject(job.projectId);
+      expect(project.tags).toEqual([]);
+      expect(project.metadata).toEqual({});
+      expect(await fs.readdir(project.path)).toContain('.git');
+    });
+
+    it('should start from the template without files', async () => {
+      const job = await clone({ name: 'fresh', includeFiles: false });
+
+      expect(job).toMatchObject({ status: 'completed', copiedFiles: 0 });
+      const project = await manager.getProject(job.projectId);
+      expect(await fs.readdir(project.path)).toContain('README.md');
+    });
+
+    it('should reject missing and taken names', async () => {
+      await expect(manager.cloneProject(source.id, {})).rejects.toThrow(
+        'A name for the clone is required'
+      );
+      await expect(
+        manager.cloneProject(source.id, { name: 'source' })
+      ).rejects.toThrow('Project name already exists');
+    });
+
+    it('should remove the partial clone when copying fails', async () => {
+      jest
+        .spyOn(manager.fileManager, 'copyFile')
+        .mockRejectedValue(new Error('disk full'));
+
+      const job = await clone({ name: 'broken' });
+
+      expect(job).toMatchObject({ status: 'failed', error: 'disk full' });
+      expect(manager.getCloneJob(job.id).finishedAt).not.toBeNull();
+      expect(await manager.fileManager.exists(path.join(dir, 'broken'))).toBe(
+        false
+      );
+    });
+  });
+
+  describe('updateProject', () => {
+    it('should merge metadata fields', async () => {
+      const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'project-'));
+      projectManager.projects.set('p1', {
+        id: 'p1',
+        name: 'p1',
+        configPath: path.join(dir, 'project.json'),
+        metadata: { owner: 'team-a', environment: 'staging' },
+      });
+
+      const project = await projectManager.updateProject('p1', {
+        metadata: { owner: 'team-b' },
+      });
+
+      expect(project.metadata).toEqual({
+        owner: 'team-b',
+        environment: 'staging',
+      });
+      await fs.rm(dir, { recursive: true, force: true });
+    });
+  });
 });
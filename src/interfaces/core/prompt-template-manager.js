@@ -0,0 +1,332 @@
+/**
+ * Prompt Template Manager
+ * Versioned prompt templates with per-environment overrides, weighted A/B
+ * variants, and variable validation at render time
+ */
+
+import { EventEmitter } from 'events';
+import { promises as fs } from 'fs';
+import path from 'path';
+import crypto from 'crypto';
+import { Logger } from './logger.js';
+
+const VARIABLE_PATTERN = /\{\{\s*(\w+)\s*\}\}/g;
+
+// Built-in templates; stored versions take precedence once created
+const DEFAULT_TEMPLATES = {
+  project_analysis: {
+    description: 'Analyze project structure and provide insights',
+    body: `Please analyze this project and provide insights:
+
+Project Information:
+{{project}}
+
+Current Status:
+{{status}}
+
+Please provide:
+1. Project structure analysis
+2. Performance insights
+3. Potential improvements
+4. Resource optimization suggestions
+5. Security considerations`,
+  },
+  system_optimization: {
+    description: 'Provide system optimization recommendations',
+    body: `Please analyze the system and provide optimization recommendations:
+
+System Status:
+{{systemStatus}}
+
+Performance Metrics:
+{{metrics}}
+
+Focus Area: {{focus}}
+
+Please provide:
+1. Performance bottlenecks
+2. Resource optimization opportunities
+3. Scalability recommendations
+4. Security improvements
+5. Maintenance suggestions`,
+  },
+  troubleshooting: {
+    description: 'Help troubleshoot system issues',
+    body: `Please help troubleshoot this issue:
+
+Issue Description: {{issue}}
+
+System Status:
+{{systemStatus}}
+
+Health Check:
+{{health}}
+
+Please provide:
+1. Potential root causes
+2. Diagnostic steps
+3. Resolution strategies
+4. Prevention recommendations`,
+  },
+};
+
+class PromptTemplateError extends Error {
+  constructor(message, missing = []) {
+    super(message);
+    this.name = 'PromptTemplateError';
+    this.missing = missing;
+  }
+}
+
+class PromptTemplateManager extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      templatesFile: config.templatesFile || './data/prompt-templates.json',
+      environment:
+        config.environment || process.env.NODE_ENV || 'development',
+      ...config,
+    };
+
+    this.logger = new Logger('PromptTemplateManager');
+    this.templates = new Map();
+
+    for (const [name, template] of Object.entries(DEFAULT_TEMPLATES)) {
+      this.templates.set(name, this.buildTemplate(name, template, 'system'));
+    }
+  }
+
+  async initialize() {
+    try {
+      await this.loadTemplates();
+      this.logger.info('PromptTemplateManager initialized successfully');
+    } catch (error) {
+      this.logger.error('Failed to initialize PromptTemplateManager:', error);
+      throw error;
+    }
+  }
+
+  async loadTemplates() {
+    try {
+      const data = await fs.readFile(this.config.templatesFile, 'utf8');
+
+      for (const template of JSON.parse(data)) {
+        this.templates.set(template.name, template);
+      }
+
+      this.logger.info(`Loaded ${this.templates.size} prompt templates`);
+    } catch (error) {
+      if (error.code !== 'ENOENT') {
+        this.logger.error('Failed to load prompt templates:', error);
+        throw error;
+      }
+    }
+  }
+
+  async saveTemplates() {
+    try {
+      await fs.mkdir(path.dirname(this.config.templatesFile), {
+        recursive: true,
+      });
+      await fs.writeFile(
+        this.config.templatesFile,
+        JSON.stringify(Array.from(this.templates.values()), null, 2)
+      );
+    } catch (error) {
+      this.logger.error('Failed to save prompt templates:', error);
+      throw error;
+    }
+  }
+
+  buildTemplate(name, { description, body }, createdBy) {
+    return {
+      name,
+      description: description || '',
+      activeVersion: 1,
+      overrides: {},
+      variants: [],
+      versions: [this.buildVersion(1, body, createdBy)],
+    };
+  }
+
+  buildVersion(version, body, createdBy) {
+    if (typeof body !== 'string' || body.trim() === '') {
+      throw new Error('Prompt template body is required');
+    }
+
+    return {
+      version,
+      body,
+      variables: this.extractVariables(body),
+      createdBy: createdBy || null,
+      createdAt: new Date().toISOString(),
+    };
+  }
+
+  extractVariables(body) {
+    const names = Array.from(body.matchAll(VARIABLE_PATTERN), (m) => m[1]);
+    return [...new Set(names)];
+  }
+
+  getTemplate(name) {
+    const template = this.templates.get(name);
+    if (!template) {
+      throw new Error(`Prompt template not found: ${name}`);
+    }
+    return template;
+  }
+
+  getVersion(template, version) {
+    const entry = template.versions.find((v) => v.version === version);
+    if (!entry) {
+      throw new Error(
+        `Prompt template ${template.name} has no version ${version}`
+      );
+    }
+    return entry;
+  }
+
+  listTemplates() {
+    return Array.from(this.templates.values()).map((template) => ({
+      name: template.name,
+      description: template.description,
+      activeVersion: template.activeVersion,
+      versions: template.versions.length,
+      overrides: template.overrides,
+      variants: template.variants,
+    }));
+  }
+
+  // Adds a new version; new templates are created on their first version
+  async createVersion(name, data, createdBy) {
+    let template = this.templates.get(name);
+
+    if (!template) {
+      template = this.buildTemplate(name, data, createdBy);
+    } else {
+      const version = Math.max(...template.versions.map((v) => v.version)) + 1;
+      template.versions.push(this.buildVersion(version, data.body, createdBy));
+      if (data.description !== undefined) {
+        template.description = data.description;
+      }
+      if (data.activate) {
+        template.activeVersion = version;
+      }
+    }
+
+    this.templates.set(name, template);
+    await this.saveTemplates();
+
+    this.emit('template:updated', template);
+    this.logger.info(`Prompt template version saved: ${name}`);
+
+    return template;
+  }
+
+  // Updates which versions are served: active, per-environment, A/B variants
+  async configure(name, { activeVersion, overrides, variants } = {}) {
+    const template = this.getTemplate(name);
+
+    if (activeVersion !== undefined) {
+      this.getVersion(template, activeVersion);
+      template.activeVersion = activeVersion;
+    }
+
+    if (overrides !== undefined) {
+      for (const version of Object.values(overrides)) {
+        this.getVersion(template, version);
+      }
+      template.overrides = overrides;
+    }
+
+    if (variants !== undefined) {
+      for (const variant of variants) {
+        this.getVersion(template, variant.version);
+        if (!variant.id || !(variant.weight > 0)) {
+          throw new Error('Each variant needs an id and a positive weight');
+        }
+      }
+      template.variants = variants;
+    }
+
+    await this.saveTemplates();
+    this.emit('template:updated', template);
+
+    return template;
+  }
+
+  // Variants win over overrides; the subject key keeps assignment sticky
+  resolve(name, options = {}) {
+    const template = this.getTemplate(name);
+
+    if (template.variants.length > 0 && options.subjectKey) {
+      const variant = this.pickVariant(template, options.subjectKey);
+      return {
+        ...this.getVersion(template, variant.version),
+        variant: variant.id,
+      };
+    }
+
+    const environment = options.environment || this.config.environment;
+    const version = template.overrides[environment] || template.activeVersion;
+
+    return { ...this.getVersion(template, version), variant: null };
+  }
+
+  pickVariant(template, subjectKey) {
+    const hash = crypto
+      .createHash('sha256')
+      .update(`${template.name}:${subjectKey}`)
+      .digest();
+    const total = template.variants.reduce((sum, v) => sum + v.weight, 0);
+    let point = (hash.readUInt32BE(0) / 0x100000000) * total;
+
+    for (const variant of template.variants) {
+      point -= variant.weight;
+      if (point < 0) return variant;
+    }
+    return template.variants[template.variants.length - 1];
+  }
+
+  render(name, variables = {}, options = {}) {
+    const resolved = this.resolve(name, options);
+
+    const missing = resolved.variables.filter(
+      (variable) => variables[variable] === undefined
+    );
+    if (missing.length > 0) {
+      throw new PromptTemplateError(
+        `Missing variables for prompt ${name}: ${missing.join(', ')}`,
+        missing
+      );
+    }
+
+    const text = resolved.body.replace(VARIABLE_PATTERN, (_match, key) => {
+      const value = variables[key];
+      return typeof value === 'string' ? value : JSON.stringify(value, null, 2);
+    });
+
+    return {
+      name,
+      version: resolved.version,
+      variant: resolved.variant,
+      text,
+    };
+  }
+
+  async stop() {
+    try {
+      await this.saveTemplates();
+      this.logger.info('PromptTemplateManager stopped successfully');
+    } catch (error) {
+      this.logger.error('Error stopping PromptTemplateManager:', error);
+      throw error;
+    }
+  }
+}
+
+export {
+  PromptTemplateManager,
+  PromptTemplateError,
+  DEFAULT_TEMPLATES,
+};
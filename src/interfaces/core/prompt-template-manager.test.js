@@ -0,0 +1,154 @@
+/**
+ * Tests for Prompt Template Manager
+ */
+
+import {
+  PromptTemplateManager,
+  PromptTemplateError,
+} from './prompt-template-manager.js';
+import { promises as fs } from 'fs';
+import os from 'os';
+import path from 'path';
+
+describe('PromptTemplateManager', () => {
+  let dir;
+  let templateManager;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'prompt-templates-'));
+    templateManager = new PromptTemplateManager({
+      templatesFile: path.join(dir, 'prompt-templates.json'),
+      environment: 'production',
+    });
+  });
+
+  afterEach(async () => {
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  const createGreeting = () =>
+    templateManager.createVersion(
+      'greeting',
+      { description: 'Greet', body: 'Hello {{name}}' },
+      'user-1'
+    );
+
+  describe('render', () => {
+    it('should fill variables and serialize objects', () => {
+      const prompt = templateManager.render('troubleshooting', {
+        issue: 'disk full',
+        systemStatus: { cpu: 10 },
+        health: 'ok',
+      });
+
+      expect(prompt).toMatchObject({ version: 1, variant: null });
+      expect(prompt.text).toContain('Issue Description: disk full');
+      expect(prompt.text).toContain('"cpu": 10');
+    });
+
+    it('should name every missing variable', () => {
+      try {
+        templateManager.render('troubleshooting', { issue: 'x' });
+        throw new Error('expected render to fail');
+      } catch (error) {
+        expect(error).toBeInstanceOf(PromptTemplateError);
+        expect(error.missing).toEqual(['systemStatus', 'health']);
+      }
+    });
+
+    it('should reject unknown templates', () => {
+      expect(() => templateManager.render('nope')).toThrow(
+        'Prompt template not found: nope'
+      );
+    });
+  });
+
+  describe('createVersion', () => {
+    it('should only serve a new version once activated', async () => {
+      await createGreeting();
+      await templateManager.createVersion('greeting', { body: 'Hi {{name}}' });
+
+      expect(templateManager.render('greeting', { name: 'Ada' }).text).toBe(
+        'Hello Ada'
+      );
+
+      await templateManager.createVersion('greeting', {
+        body: 'Hey {{name}}',
+        activate: true,
+      });
+      const prompt = templateManager.render('greeting', { name: 'Ada' });
+      expect(prompt).toMatchObject({ version: 3, text: 'Hey Ada' });
+    });
+
+    it('should reject empty bodies', async () => {
+      await expect(
+        templateManager.createVersion('empty', { body: '  ' })
+      ).rejects.toThrow('Prompt template body is required');
+    });
+
+    it('should persist templates across restarts', async () => {
+      await createGreeting();
+
+      const reloaded = new PromptTemplateManager({
+        templatesFile: templateManager.config.templatesFile,
+      });
+      await reloaded.initialize();
+
+      expect(reloaded.render('greeting', { name: 'Ada' }).text).toBe(
+        'Hello Ada'
+      );
+    });
+  });
+
+  describe('configure', () => {
+    it('should serve per-environment overrides', async () => {
+      await createGreeting();
+      await templateManager.createVersion('greeting', { body: 'Hi {{name}}' });
+      await templateManager.configure('greeting', {
+        overrides: { staging: 2 },
+      });
+
+      const render = (environment) =>
+        templateManager.render('greeting', { name: 'Ada' }, { environment })
+          .text;
+
+      expect(render('staging')).toBe('Hi Ada');
+      expect(render('production')).toBe('Hello Ada');
+    });
+
+    it('should reject versions that do not exist', async () => {
+      await createGreeting();
+
+      await expect(
+        templateManager.configure('greeting', { activeVersion: 7 })
+      ).rejects.toThrow('Prompt template greeting has no version 7');
+      await expect(
+        templateManager.configure('greeting', {
+          variants: [{ id: 'a', version: 1, weight: 0 }],
+        })
+      ).rejects.toThrow('Each variant needs an id and a positive weight');
+    });
+
+    it('should keep variant assignment sticky per subject', async () => {
+      await createGreeting();
+      await templateManager.createVersion('greeting', { body: 'Hi {{name}}' });
+      await templateManager.configure('greeting', {
+        variants: [
+          { id: 'control', version: 1, weight: 1 },
+          { id: 'short', version: 2, weight: 1 },
+        ],
+      });
+
+      const variantFor = (subjectKey) =>
+        templateManager.resolve('greeting', { subjectKey }).variant;
+      const seen = new Set();
+      for (let i = 0; i < 50; i++) {
+        expect(variantFor(`user-${i}`)).toBe(variantFor(`user-${i}`));
+        seen.add(variantFor(`user-${i}`));
+      }
+
+      expect([...seen].sort()).toEqual(['control', 'short']);
+      expect(templateManager.resolve('greeting').variant).toBeNull();
+    });
+  });
+});
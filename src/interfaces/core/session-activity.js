@@ -0,0 +1,117 @@
+/**
+ * Session Activity
+ * Per-user timeline of logins, logouts and revocations with device and
+ * location, used to spot logins from devices or places not seen before
+ */
+
+import { promises as fs } from 'fs';
+import path from 'path';
+import crypto from 'crypto';
+import { Logger } from './logger.js';
+
+// Same browser build and language on the same OS -> same device id
+function fingerprintDevice({ userAgent, acceptLanguage } = {}) {
+  if (!userAgent) return null;
+
+  return crypto
+    .createHash('sha256')
+    .update(`${userAgent}|${acceptLanguage || ''}`)
+    .digest('hex')
+    .slice(0, 16);
+}
+
+// Country/region granularity; city-level changes are too noisy to alert on
+function locationKey(location) {
+  if (!location?.country) return null;
+  return `${location.country}/${location.region || ''}`;
+}
+
+class SessionActivityLog {
+  constructor(config = {}) {
+    this.config = {
+      activityFile: config.activityFile || './data/session-activity.json',
+      maxEntriesPerUser: config.maxEntriesPerUser || 200,
+      ...config,
+    };
+
+    this.logger = new Logger('SessionActivity');
+
+    // userId -> entries, oldest first
+    this.entries = new Map();
+  }
+
+  async load() {
+    try {
+      const data = await fs.readFile(this.config.activityFile, 'utf8');
+      this.entries = new Map(Object.entries(JSON.parse(data)));
+    } catch (error) {
+      if (error.code !== 'ENOENT') {
+        this.logger.error('Failed to load session activity:', error);
+        throw error;
+      }
+    }
+  }
+
+  async save() {
+    try {
+      await fs.mkdir(path.dirname(this.config.activityFile), {
+        recursive: true,
+      });
+      await fs.writeFile(
+        this.config.activityFile,
+        JSON.stringify(Object.fromEntries(this.entries), null, 2)
+      );
+    } catch (error) {
+      this.logger.error('Failed to save session activity:', error);
+      throw error;
+    }
+  }
+
+  // A user's first login has nothing to compare against and is never "new";
+  // neither is a device or location we could not identify
+  compareWithHistory(userId, { deviceId, location }) {
+    const logins = (this.entries.get(userId) || []).filter(
+      (entry) => entry.type === 'login'
+    );
+    if (logins.length === 0) {
+      return { newDevice: false, newLocation: false };
+    }
+
+    const place = locationKey(location);
+    return {
+      newDevice:
+        deviceId !== null &&
+        !logins.some((entry) => entry.deviceId === deviceId),
+      newLocation:
+        place !== null &&
+        !logins.some((entry) => locationKey(entry.location) === place),
+    };
+  }
+
+  async record(userId, data) {
+    const entry = {
+      id: crypto.randomUUID(),
+      timestamp: new Date().toISOString(),
+      ...data,
+    };
+
+    const entries = this.entries.get(userId) || [];
+    entries.push(entry);
+    this.entries.set(userId, entries.slice(-this.config.maxEntriesPerUser));
+    await this.save();
+
+    return entry;
+  }
+
+  getTimeline(userId, { limit = 50 } = {}) {
+    return (this.entries.get(userId) || []).slice(-limit).reverse();
+  }
+
+  async forgetUser(userId) {
+    if (this.entries.delete(userId)) {
+      await this.save();
+    }
+  }
+}
+
+export { SessionActivityLog, fingerprintDevice, locationKey };
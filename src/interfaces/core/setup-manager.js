@@ -0,0 +1,242 @@
+/**
+ * Setup Manager
+ * First-run setup: creates the initial admin with a one-time bootstrap
+ * token, records organization settings, and locks once completed
+ */
+
+import { EventEmitter } from 'events';
+import { promises as fs } from 'fs';
+import path from 'path';
+import crypto from 'crypto';
+import { Logger } from './logger.js';
+
+const SETUP_ERRORS = {
+  LOCKED: 'SETUP_LOCKED',
+  INVALID_TOKEN: 'INVALID_BOOTSTRAP_TOKEN',
+  INVALID_REQUEST: 'INVALID_SETUP_REQUEST',
+};
+
+class SetupError extends Error {
+  constructor(code, message) {
+    super(message);
+    this.name = 'SetupError';
+    this.code = code;
+  }
+}
+
+class SetupManager extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      setupFile: config.setupFile || './data/setup.json',
+      minPasswordLength: config.minPasswordLength || 12,
+      moduleNames: config.moduleNames || [],
+      ...config,
+    };
+
+    this.logger = new Logger('SetupManager');
+    this.state = { completed: false };
+    this.authManager = null;
+
+    // Only the hash is kept; the token itself is printed once at startup
+    this.bootstrapTokenHash = null;
+    this.completing = false;
+  }
+
+  attach({ authManager } = {}) {
+    this.authManager = authManager;
+  }
+
+  async initialize() {
+    try {
+      await this.loadState();
+
+      // Installs that already have users predate first-run setup
+      if (!this.state.completed && this.authManager?.users.size > 0) {
+        this.state = {
+          completed: true,
+          completedAt: new Date().toISOString(),
+          legacy: true,
+        };
+        await this.saveState();
+      }
+
+      if (!this.state.completed) {
+        this.issueBootstrapToken();
+      }
+
+      this.logger.info('SetupManager initialized successfully');
+    } catch (error) {
+      this.logger.error('Failed to initialize SetupManager:', error);
+      throw error;
+    }
+  }
+
+  async loadState() {
+    try {
+      const data = await fs.readFile(this.config.setupFile, 'utf8');
+      this.state = JSON.parse(data);
+    } catch (error) {
+      if (error.code !== 'ENOENT') {
+        this.logger.error('Failed to load setup state:', error);
+        throw error;
+      }
+    }
+  }
+
+  async saveState() {
+    try {
+      await fs.mkdir(path.dirname(this.config.setupFile), { recursive: true });
+      await fs.writeFile(
+        this.config.setupFile,
+        JSON.stringify(this.state, null, 2)
+      );
+    } catch (error) {
+      this.logger.error('Failed to save setup state:', error);
+      throw error;
+    }
+  }
+
+  hashToken(token) {
+    return crypto.createHash('sha256').update(String(token)).digest();
+  }
+
+  // Printed to stdout rather than logged so it does not end up in log files
+  issueBootstrapToken() {
+    const token = crypto.randomBytes(24).toString('hex');
+    this.bootstrapTokenHash = this.hashToken(token);
+
+    console.log(
+      [
+        '',
+        'First-run setup required.',
+        `Bootstrap token: ${token}`,
+        'POST it to /api/setup (X-Setup-Token header) to create the admin.',
+        '',
+      ].join('\n')
+    );
+
+    return token;
+  }
+
+  verifyToken(token) {
+    return (
+      Boolean(token && this.bootstrapTokenHash) &&
+      crypto.timingSafeEqual(this.hashToken(token), this.bootstrapTokenHash)
+    );
+  }
+
+  isComplete() {
+    return this.state.completed === true;
+  }
+
+  getModules() {
+    return this.state.modules || {};
+  }
+
+  getStatus() {
+    return {
+      setupRequired: !this.isComplete(),
+      completedAt: this.state.completedAt || null,
+      orgName: this.state.orgName || null,
+    };
+  }
+
+  // Settings with secrets masked, for admin display
+  getSettings() {
+    const { smtp, ...settings } = this.state;
+    return {
+      ...settings,
+      smtp: smtp ? { ...smtp, password: smtp.password ? '***' : null } : null,
+    };
+  }
+
+  validate({ admin, orgName, modules, smtp, redis }) {
+    const invalid = (message) =>
+      new SetupError(SETUP_ERRORS.INVALID_REQUEST, message);
+
+    if (!admin?.username || !admin?.email || !admin?.password) {
+      throw invalid(
+        'admin.username, admin.email and admin.password are required'
+      );
+    }
+    if (admin.password.length < this.config.minPasswordLength) {
+      throw invalid(
+        `admin.password must be at least ${this.config.minPasswordLength} characters`
+      );
+    }
+    if (typeof orgName !== 'string' || orgName.trim() === '') {
+      throw invalid('orgName is required');
+    }
+
+    if (modules !== undefined) {
+      for (const [name, enabled] of Object.entries(modules)) {
+        if (!this.config.moduleNames.includes(name)) {
+          throw invalid(`Unknown module: ${name}`);
+        }
+        if (typeof enabled !== 'boolean') {
+          throw invalid(`modules.${name} must be true or false`);
+        }
+      }
+    }
+
+    if (smtp !== undefined) {
+      if (!smtp.host) {
+        throw invalid('smtp.host is required');
+      }
+      if (smtp.port !== undefined && !Number.isInteger(smtp.port)) {
+        throw invalid('smtp.port must be an integer');
+      }
+    }
+
+    if (redis !== undefined && !/^rediss?:\/\//.test(redis.url || '')) {
+      throw invalid('redis.url must be a redis:// or rediss:// URL');
+    }
+  }
+
+  async complete(data, token) {
+    if (this.isComplete() || this.completing) {
+      throw new SetupError(SETUP_ERRORS.LOCKED, 'Setup is already complete');
+    }
+    if (!this.verifyToken(token)) {
+      throw new SetupError(
+        SETUP_ERRORS.INVALID_TOKEN,
+        'Invalid bootstrap token'
+      );
+    }
+
+    this.validate(data);
+
+    this.completing = true;
+    try {
+      const admin = await this.authManager.createUser({
+        username: data.admin.username,
+        email: data.admin.email,
+        password: data.admin.password,
+        role: 'admin',
+        permissions: ['*'],
+      });
+
+      this.state = {
+        completed: true,
+        completedAt: new Date().toISOString(),
+        orgName: data.orgName.trim(),
+        modules: data.modules || {},
+        smtp: data.smtp || null,
+        redis: data.redis || null,
+        adminId: admin.id,
+      };
+      await this.saveState();
+
+      this.bootstrapTokenHash = null;
+      this.emit('setup:completed', this.getStatus());
+      this.logger.info(`First-run setup completed by ${admin.username}`);
+
+      return { admin, settings: this.getSettings() };
+    } finally {
+      this.completing = false;
+    }
+  }
+}
+
+export { SetupManager, SetupError, SETUP_ERRORS };
@@ -0,0 +1,149 @@
+/**
+ * Tests for Setup Manager
+ */
+
+import { SetupManager, SETUP_ERRORS } from './setup-manager.js';
+import { jest } from '@jest/globals';
+import { promises as fs } from 'fs';
+import os from 'os';
+import path from 'path';
+
+describe('SetupManager', () => {
+  let dir;
+  let setupManager;
+  let authManager;
+  let consoleLog;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'setup-'));
+    consoleLog = jest.spyOn(console, 'log').mockImplementation(() => {});
+    authManager = {
+      users: new Map(),
+      createUser: jest.fn(async (data) => ({ id: 'user-1', ...data })),
+    };
+    setupManager = new SetupManager({
+      setupFile: path.join(dir, 'setup.json'),
+      moduleNames: ['rnd'],
+    });
+    setupManager.attach({ authManager });
+  });
+
+  afterEach(async () => {
+    consoleLog.mockRestore();
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  const request = (overrides = {}) => ({
+    admin: {
+      username: 'admin',
+      email: 'admin@example.com',
+      password: 'correct horse battery',
+    },
+    orgName: ' Acme ',
+    modules: { rnd: true },
+    smtp: { host: 'smtp.example.com', port: 587, password: 'smtp-secret' },
+    ...overrides,
+  });
+
+  const codeOf = async (promise) => {
+    try {
+      await promise;
+    } catch (error) {
+      return error.code;
+    }
+  };
+
+  describe('initialize', () => {
+    it('should print a bootstrap token on a fresh install', async () => {
+      await setupManager.initialize();
+
+      const output = consoleLog.mock.calls.map(([line]) => line).join('\n');
+      const token = /Bootstrap token: (\w+)/.exec(output)[1];
+
+      expect(setupManager.getStatus().setupRequired).toBe(true);
+      expect(setupManager.verifyToken(token)).toBe(true);
+      expect(setupManager.verifyToken('wrong')).toBe(false);
+    });
+
+    it('should mark installs with existing users as complete', async () => {
+      authManager.users.set('user-1', { id: 'user-1' });
+
+      await setupManager.initialize();
+
+      expect(setupManager.isComplete()).toBe(true);
+      expect(setupManager.state.legacy).toBe(true);
+      expect(setupManager.verifyToken('anything')).toBe(false);
+    });
+  });
+
+  describe('complete', () => {
+    let token;
+
+    beforeEach(() => {
+      token = setupManager.issueBootstrapToken();
+    });
+
+    it('should create the admin and lock setup', async () => {
+      const { admin, settings } = await setupManager.complete(
+        request(),
+        token
+      );
+
+      expect(authManager.createUser).toHaveBeenCalledWith(
+        expect.objectContaining({ role: 'admin', permissions: ['*'] })
+      );
+      expect(admin.id).toBe('user-1');
+      expect(settings.orgName).toBe('Acme');
+      expect(settings.smtp.password).toBe('***');
+      expect(setupManager.getModules()).toEqual({ rnd: true });
+
+      expect(await codeOf(setupManager.complete(request(), token))).toBe(
+        SETUP_ERRORS.LOCKED
+      );
+    });
+
+    it('should persist completion across restarts', async () => {
+      await setupManager.complete(request(), token);
+
+      const reloaded = new SetupManager({
+        setupFile: setupManager.config.setupFile,
+      });
+      await reloaded.initialize();
+
+      expect(reloaded.getStatus()).toMatchObject({
+        setupRequired: false,
+        orgName: 'Acme',
+      });
+    });
+
+    it('should reject an invalid bootstrap token', async () => {
+      expect(await codeOf(setupManager.complete(request(), 'nope'))).toBe(
+        SETUP_ERRORS.INVALID_TOKEN
+      );
+      expect(authManager.createUser).not.toHaveBeenCalled();
+    });
+
+    it('should validate the request before creating anything', async () => {
+      const cases = [
+        [{ admin: { username: 'a' } }, 'admin.password are required'],
+        [
+          { admin: { ...request().admin, password: 'short' } },
+          'at least 12 characters',
+        ],
+        [{ orgName: '  ' }, 'orgName is required'],
+        [{ modules: { billing: true } }, 'Unknown module: billing'],
+        [{ modules: { rnd: 'yes' } }, 'modules.rnd must be true or false'],
+        [{ smtp: { host: 'x', port: '25' } }, 'smtp.port must be an integer'],
+        [{ redis: { url: 'http://cache' } }, 'redis.url must be a redis://'],
+      ];
+
+      for (const [overrides, message] of cases) {
+        await expect(
+          setupManager.complete(request(overrides), token)
+        ).rejects.toThrow(message);
+      }
+      expect(authManager.createUser).not.toHaveBeenCalled();
+      expect(setupManager.isComplete()).toBe(false);
+    });
+  });
+});
@@ -0,0 +1,200 @@
+/**
+ * Startup Manager
+ * Starts components in dependency order with bounded exponential retries,
+ * deferring non-critical components that cannot start yet
+ */
+
+import { EventEmitter } from 'events';
+import { Logger } from './logger.js';
+
+class StartupManager extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      maxRetries: config.maxRetries ?? 4,
+      initialDelay: config.initialDelay || 500,
+      maxDelay: config.maxDelay || 30000,
+      ...config,
+    };
+
+    this.logger = new Logger('StartupManager');
+    this.components = new Map();
+    this.started = [];
+    this.retryTimers = new Map();
+  }
+
+  register(name, { start, stop, dependsOn = [], critical = true }) {
+    this.components.set(name, {
+      name,
+      start,
+      stop,
+      dependsOn,
+      critical,
+      status: 'pending',
+      attempts: 0,
+      error: null,
+    });
+  }
+
+  // Topological order; unknown dependencies and cycles are configuration bugs
+  resolveOrder() {
+    const order = [];
+    const visiting = new Set();
+    const visited = new Set();
+
+    const visit = (name, chain) => {
+      if (visited.has(name)) return;
+      if (visiting.has(name)) {
+        const cycle = [...chain, name].join(' -> ');
+        throw new Error(`Startup dependency cycle: ${cycle}`);
+      }
+
+      const component = this.components.get(name);
+      if (!component) {
+        throw new Error(`Unknown startup dependency: ${name}`);
+      }
+
+      visiting.add(name);
+      for (const dependency of component.dependsOn) {
+        visit(dependency, [...chain, name]);
+      }
+      visiting.delete(name);
+      visited.add(name);
+      order.push(component);
+    };
+
+    for (const name of this.components.keys()) {
+      visit(name, []);
+    }
+
+    return order;
+  }
+
+  delayFor(attempt) {
+    return Math.min(
+      this.config.initialDelay * 2 ** (attempt - 1),
+      this.config.maxDelay
+    );
+  }
+
+  async attempt(component) {
+    component.attempts++;
+    try {
+      await component.start();
+      component.status = 'started';
+      component.error = null;
+      this.started.push(component);
+      this.emit('component:started', component.name);
+      return true;
+    } catch (error) {
+      component.error = error.message;
+      return false;
+    }
+  }
+
+  async startWithRetries(component) {
+    for (let retry = 0; retry <= this.config.maxRetries; retry++) {
+      if (retry > 0) {
+        const delay = this.delayFor(retry);
+        this.logger.warn(
+          `Retrying ${component.name} in ${delay}ms (attempt ${retry + 1})`,
+          { error: component.error }
+        );
+        await new Promise((resolve) => setTimeout(resolve, delay));
+      }
+
+      if (await this.attempt(component)) return true;
+    }
+    return false;
+  }
+
+  async startAll() {
+    for (const component of this.resolveOrder()) {
+      const blockedBy = component.dependsOn.filter(
+        (name) => this.components.get(name).status !== 'started'
+      );
+
+      if (blockedBy.length === 0 && (await this.startWithRetries(component))) {
+        this.logger.info(`Started ${component.name}`);
+        continue;
+      }
+
+      if (component.critical) {
+        component.status = 'failed';
+        throw new Error(
+          `Critical component ${component.name} failed to start: ${
+            component.error || `waiting on ${blockedBy.join(', ')}`
+          }`
+        );
+      }
+
+      component.status = 'deferred';
+      this.logger.warn(`Deferred ${component.name}; will keep retrying`, {
+        error: component.error,
+        blockedBy,
+      });
+      this.emit('component:deferred', component.name);
+      this.scheduleRetry(component);
+    }
+
+    return this.getStatus();
+  }
+
+  // Deferred components retry in the background at the capped delay
+  scheduleRetry(component) {
+    const timer = setTimeout(async () => {
+      this.retryTimers.delete(component.name);
+
+      const ready = component.dependsOn.every(
+        (name) => this.components.get(name).status === 'started'
+      );
+      if (ready && (await this.attempt(component))) {
+        this.logger.info(`Deferred component ${component.name} started`);
+        return;
+      }
+
+      this.scheduleRetry(component);
+    }, this.config.maxDelay);
+
+    this.retryTimers.set(component.name, timer);
+  }
+
+  async stopAll() {
+    for (const timer of this.retryTimers.values()) {
+      clearTimeout(timer);
+    }
+    this.retryTimers.clear();
+
+    for (const component of [...this.started].reverse()) {
+      try {
+        await component.stop?.();
+        component.status = 'stopped';
+      } catch (error) {
+        this.logger.error(`Failed to stop ${component.name}:`, error);
+      }
+    }
+    this.started = [];
+  }
+
+  getStatus() {
+    return Object.fromEntries(
+      Array.from(this.components.values(), (component) => [
+        component.name,
+        {
+          status: component.status,
+          critical: component.critical,
+          attempts: component.attempts,
+          error: component.error,
+        },
+      ])
+    );
+  }
+
+  isReady() {
+    return Array.from(this.components.values()).every(
+      (component) => !component.critical || component.status === 'started'
+    );
+  }
+}
+
+export { StartupManager };
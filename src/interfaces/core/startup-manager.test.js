@@ -0,0 +1,135 @@
+/**
+ * Tests for Startup Manager
+ */
+
+import { StartupManager } from './startup-manager.js';
+import { jest } from '@jest/globals';
+
+describe('StartupManager', () => {
+  let startupManager;
+  let events;
+
+  beforeEach(() => {
+    startupManager = new StartupManager({
+      maxRetries: 2,
+      initialDelay: 1,
+      maxDelay: 5,
+    });
+    events = [];
+  });
+
+  afterEach(async () => {
+    await startupManager.stopAll();
+  });
+
+  const register = (name, options = {}) =>
+    startupManager.register(name, {
+      start: async () => events.push(`start:${name}`),
+      stop: async () => events.push(`stop:${name}`),
+      ...options,
+    });
+
+  // Fails the first `failures` attempts, then starts
+  const flaky = (name, failures) => {
+    let calls = 0;
+    return jest.fn(async () => {
+      if (++calls <= failures) throw new Error(`${name} unavailable`);
+      events.push(`start:${name}`);
+    });
+  };
+
+  describe('resolveOrder', () => {
+    it('should start dependencies first', async () => {
+      register('api', { dependsOn: ['db', 'cache'] });
+      register('cache', { dependsOn: ['db'] });
+      register('db');
+
+      await startupManager.startAll();
+
+      expect(events).toEqual(['start:db', 'start:cache', 'start:api']);
+      expect(startupManager.isReady()).toBe(true);
+    });
+
+    it('should reject cycles and unknown dependencies', () => {
+      register('a', { dependsOn: ['b'] });
+      register('b', { dependsOn: ['a'] });
+      expect(() => startupManager.resolveOrder()).toThrow(
+        'Startup dependency cycle: a -> b -> a'
+      );
+
+      startupManager.components.clear();
+      register('a', { dependsOn: ['ghost'] });
+      expect(() => startupManager.resolveOrder()).toThrow(
+        'Unknown startup dependency: ghost'
+      );
+    });
+  });
+
+  describe('startAll', () => {
+    it('should retry with capped exponential backoff', async () => {
+      const start = flaky('db', 2);
+      register('db', { start });
+
+      await startupManager.startAll();
+
+      expect(start).toHaveBeenCalledTimes(3);
+      expect(startupManager.getStatus().db).toMatchObject({
+        status: 'started',
+        attempts: 3,
+        error: null,
+      });
+      expect([1, 2, 3, 4].map((n) => startupManager.delayFor(n))).toEqual([
+        1, 2, 4, 5,
+      ]);
+    });
+
+    it('should fail when a critical component never starts', async () => {
+      register('db', { start: flaky('db', 10) });
+      register('api', { dependsOn: ['db'] });
+
+      await expect(startupManager.startAll()).rejects.toThrow(
+        'Critical component db failed to start: db unavailable'
+      );
+      expect(events).toEqual([]);
+    });
+
+    it('should defer non-critical components and keep retrying', async () => {
+      const deferred = jest.fn();
+      startupManager.on('component:deferred', deferred);
+      register('db');
+      register('search', { start: flaky('search', 4), critical: false });
+
+      const status = await startupManager.startAll();
+
+      expect(status.search.status).toBe('deferred');
+      expect(deferred).toHaveBeenCalledWith('search');
+      expect(startupManager.isReady()).toBe(true);
+
+      await new Promise((resolve) => setTimeout(resolve, 50));
+      expect(startupManager.getStatus().search.status).toBe('started');
+    });
+
+    it('should defer components blocked by a deferred dependency', async () => {
+      register('search', { start: flaky('search', 10), critical: false });
+      register('indexer', { dependsOn: ['search'], critical: false });
+
+      const status = await startupManager.startAll();
+
+      expect(status.indexer).toMatchObject({ status: 'deferred', attempts: 0 });
+    });
+  });
+
+  describe('stopAll', () => {
+    it('should stop started components in reverse order', async () => {
+      register('db');
+      register('api', { dependsOn: ['db'] });
+      await startupManager.startAll();
+      events = [];
+
+      await startupManager.stopAll();
+
+      expect(events).toEqual(['stop:api', 'stop:db']);
+      expect(startupManager.getStatus().db.status).toBe('stopped');
+    });
+  });
+});
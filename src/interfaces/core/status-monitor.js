@@ -8,6 +8,9 @@ import os from 'os';
 import { Logger } from './logger.js';
 import { ProcessManager } from './process-manager.js';
 import { MetricsCollector } from './metrics-collector.js';
+import { AlertRuleEngine } from './alert-rule-engine.js';
+import { SyntheticProbeRunner } from './synthetic-probes.js';
+import { fanOut } from './fan-out.js';
 
 class StatusMonitor extends EventEmitter {
   constructor(config = {}) {
@@ -21,24 +24,63 @@ class StatusMonitor extends EventEmitter {
         disk: config.alertThresholds?.disk || 90,
         ...config.alertThresholds,
       },
+      // Past these the alert is raised as critical, which opens an incident
+      criticalThresholds: {
+        cpu: config.criticalThresholds?.cpu || 95,
+        memory: config.criticalThresholds?.memory || 95,
+        disk: config.criticalThresholds?.disk || 97,
+        ...config.criticalThresholds,
+      },
+      alertRulesEnabled: config.alertRulesEnabled !== false,
+      // Independent status queries run in parallel, each with a deadline
+      queryConcurrency: config.queryConcurrency || 4,
+      queryTimeout: config.queryTimeout || 5000,
+      // Queries run inside another fan-out get less time than the outer
+      // deadline, so the outer query can still report partial results
+      nestedQueryTimeout: config.nestedQueryTimeout || 3000,
       ...config,
     };
 
     this.logger = new Logger('StatusMonitor');
     this.processManager = new ProcessManager();
     this.metricsCollector = new MetricsCollector();
+    this.alertRules = new AlertRuleEngine(this.config.alertRules);
+    this.syntheticProbes = new SyntheticProbeRunner({
+      probes: this.config.probes,
+    });
 
     this.monitoring = false;
     this.watchers = new Map();
     this.lastSystemStatus = null;
     this.projectStatuses = new Map();
     this.alerts = new Map();
+
+    // Components probed for the status page; name -> () => boolean
+    this.components = new Map([
+      [
+        'system',
+        () =>
+          !!this.lastSystemStatus &&
+          this.lastSystemStatus.cpu < 90 &&
+          this.lastSystemStatus.memory.percentage < 90,
+      ],
+      ['processManager', () => this.processManager.isHealthy()],
+    ]);
   }
 
   async initialize() {
     try {
       await this.processManager.initialize();
       await this.metricsCollector.initialize();
+      if (this.config.alertRulesEnabled) {
+        await this.alertRules.initialize();
+        this.routeRuleAlerts();
+      }
+
+      this.syntheticProbes.on('probe:result', (result) => {
+        this.metricsCollector.recordProbeResult(result);
+      });
+      this.syntheticProbes.start();
 
       // Start monitoring
       this.startMonitoring();
@@ -58,6 +100,8 @@ class StatusMonitor extends EventEmitter {
       this.collectSystemStatus();
       this.collectProjectStatuses();
       this.checkAlerts();
+      this.evaluateAlertRules();
+      this.recordAvailability();
     }, this.config.updateInterval);
 
     this.logger.info('Started system monitoring');
@@ -74,13 +118,41 @@ class StatusMonitor extends EventEmitter {
     this.logger.info('Stopped system monitoring');
   }
 
+  queryOptions({ signal, nested = false } = {}) {
+    return {
+      concurrency: this.config.queryConcurrency,
+      timeout: nested
+        ? this.config.nestedQueryTimeout
+        : this.config.queryTimeout,
+      signal,
+    };
+  }
+
   async collectSystemStatus() {
     try {
+      const { results, errors } = await fanOut(
+        {
+          cpu: () => this.getCPUUsage(),
+          disk: () => this.getDiskUsage(),
+          processes: () => this.getSystemProcesses(),
+        },
+        this.queryOptions()
+      );
+
+      // Failed queries keep their last known value and are marked stale
+      const stale = Object.keys(errors);
+      if (stale.length > 0) {
+        if (!this.lastSystemStatus) throw errors[stale[0]];
+        this.logger.warn(`Stale system status fields: ${stale.join(', ')}`);
+      }
+      const value = (name) =>
+        name in results ? results[name] : this.lastSystemStatus[name];
+
       const systemStatus = {
         timestamp: new Date().toISOString(),
-        cpu: await this.getCPUUsage(),
+        cpu: value('cpu'),
         memory: this.getMemoryUsage(),
-        disk: await this.getDiskUsage(),
+        disk: value('disk'),
         loadAverage: os.loadavg(),
         uptime: os.uptime(),
         platform: os.platform(),
@@ -88,7 +160,8 @@ class StatusMonitor extends EventEmitter {
         nodeVersion: process.version,
         activeProjects: this.processManager.getActiveProjectsCount(),
         networkInterfaces: this.getNetworkInterfaces(),
-        processes: await this.getSystemProcesses(),
+        processes: value('processes'),
+        stale,
       };
 
       this.lastSystemStatus = systemStatus;
@@ -105,21 +178,54 @@ class StatusMonitor extends EventEmitter {
     try {
       const runningProjects = await this.processManager.getRunningProjects();
 
-      for (const [projectId, processInfo] of runningProjects) {
-        const projectStatus = await this.getProjectStatusDetailed(
-          projectId,
-          processInfo
-        );
+      const { results, errors } = await fanOut(
+        Object.fromEntries(
+          Array.from(runningProjects, ([projectId, processInfo]) => [
+            projectId,
+            (signal) =>
+              this.getProjectStatusDetailed(projectId, processInfo, {
+                signal,
+                nested: true,
+              }),
+          ])
+        ),
+        this.queryOptions()
+      );
+
+      for (const [projectId, projectStatus] of Object.entries(results)) {
         this.projectStatuses.set(projectId, projectStatus);
         this.emit('project:status', projectStatus);
       }
+      for (const [projectId, error] of Object.entries(errors)) {
+        this.logger.warn(`Project status for ${projectId} failed:`, {
+          error: error.message,
+        });
+      }
     } catch (error) {
       this.logger.error('Failed to collect project statuses:', error);
     }
   }
 
-  async getProjectStatusDetailed(projectId, processInfo) {
+  // Fields whose query failed are null and listed in `unavailable`
+  async getProjectStatusDetailed(projectId, processInfo, options = {}) {
     try {
+      const queries = {
+        cpu: () => this.getProcessCPUUsage(processInfo.pid),
+        memory: () => this.getProcessMemoryUsage(processInfo.pid),
+        health: (signal) =>
+          this.checkProjectHealth(projectId, processInfo, signal),
+        logs: () => this.getRecentLogs(projectId, 10),
+      };
+      const { results, errors } = await fanOut(
+        queries,
+        this.queryOptions(options)
+      );
+
+      const unavailable = Object.keys(errors);
+      if (unavailable.length === Object.keys(queries).length) {
+        throw errors[unavailable[0]];
+      }
+
       const status = {
         projectId,
         timestamp: new Date().toISOString(),
@@ -128,13 +234,14 @@ class StatusMonitor extends EventEmitter {
           status: processInfo.status,
           startTime: processInfo.startTime,
           uptime: Date.now() - processInfo.startTime,
-          cpu: await this.getProcessCPUUsage(processInfo.pid),
-          memory: await this.getProcessMemoryUsage(processInfo.pid),
+          cpu: results.cpu ?? null,
+          memory: results.memory ?? null,
           port: processInfo.port,
           url: processInfo.url,
         },
-        health: await this.checkProjectHealth(projectId, processInfo),
-        logs: await this.getRecentLogs(projectId, 10),
+        health: results.health ?? null,
+        logs: results.logs ?? null,
+        unavailable,
       };
 
       return status;
@@ -151,7 +258,7 @@ class StatusMonitor extends EventEmitter {
     }
   }
 
-  async checkProjectHealth(projectId, processInfo) {
+  async checkProjectHealth(projectId, processInfo, signal) {
     try {
       const health = {
         healthy: true,
@@ -172,7 +279,10 @@ class StatusMonitor extends EventEmitter {
       }
 
       // Check if port is accessible
-      if (processInfo.port && (await this.isPortAccessible(processInfo.port))) {
+      if (
+        processInfo.port &&
+        (await this.isPortAccessible(processInfo.port, signal))
+      ) {
         health.checks.port = true;
       } else if (processInfo.port) {
         health.healthy = false;
@@ -180,7 +290,10 @@ class StatusMonitor extends EventEmitter {
       }
 
       // Check if application responds
-      if (processInfo.url && (await this.isURLResponding(processInfo.url))) {
+      if (
+        processInfo.url &&
+        (await this.isURLResponding(processInfo.url, signal))
+      ) {
         health.checks.response = true;
       } else if (processInfo.url) {
         health.healthy = false;
@@ -197,12 +310,20 @@ class StatusMonitor extends EventEmitter {
     }
   }
 
-  async isPortAccessible(port) {
+  async isPortAccessible(port, signal) {
     try {
       const net = await import('net');
       return new Promise((resolve) => {
         const socket = new net.Socket();
         socket.setTimeout(1000);
+        signal?.addEventListener(
+          'abort',
+          () => {
+            socket.destroy();
+            resolve(false);
+          },
+          { once: true }
+        );
 
         socket.on('connect', () => {
           socket.destroy();
@@ -225,11 +346,11 @@ class StatusMonitor extends EventEmitter {
     }
   }
 
-  async isURLResponding(url) {
+  async isURLResponding(url, signal) {
     try {
       const response = await fetch(url, {
         method: 'HEAD',
-        timeout: 5000,
+        signal,
       });
       return response.ok;
     } catch (error) {
@@ -361,7 +482,7 @@ class StatusMonitor extends EventEmitter {
     if (this.lastSystemStatus.cpu > this.config.alertThresholds.cpu) {
       alerts.push({
         type: 'cpu',
-        level: 'warning',
+        level: this.alertLevel('cpu', this.lastSystemStatus.cpu),
         message: `High CPU usage: ${this.lastSystemStatus.cpu}%`,
         threshold: this.config.alertThresholds.cpu,
         current: this.lastSystemStatus.cpu,
@@ -376,7 +497,10 @@ class StatusMonitor extends EventEmitter {
     ) {
       alerts.push({
         type: 'memory',
-        level: 'warning',
+        level: this.alertLevel(
+          'memory',
+          this.lastSystemStatus.memory.percentage
+        ),
         message: `High memory usage: ${this.lastSystemStatus.memory.percentage}%`,
         threshold: this.config.alertThresholds.memory,
         current: this.lastSystemStatus.memory.percentage,
@@ -390,7 +514,7 @@ class StatusMonitor extends EventEmitter {
     ) {
       alerts.push({
         type: 'disk',
-        level: 'warning',
+        level: this.alertLevel('disk', this.lastSystemStatus.disk.percentage),
         message: `High disk usage: ${this.lastSystemStatus.disk.percentage}%`,
         threshold: this.config.alertThresholds.disk,
         current: this.lastSystemStatus.disk.percentage,
@@ -408,6 +532,72 @@ class StatusMonitor extends EventEmitter {
     this.cleanupOldAlerts();
   }
 
+  alertLevel(type, current) {
+    return current > this.config.criticalThresholds[type]
+      ? 'critical'
+      : 'warning';
+  }
+
+  // Flat metric snapshot that alert rules are evaluated against
+  async getMetricSnapshot() {
+    const snapshot = {};
+
+    if (this.lastSystemStatus) {
+      snapshot.cpu = this.lastSystemStatus.cpu;
+      snapshot.memory = this.lastSystemStatus.memory.percentage;
+      snapshot.disk = this.lastSystemStatus.disk.percentage;
+      snapshot.loadAverage = this.lastSystemStatus.loadAverage[0];
+      snapshot.activeProjects = this.lastSystemStatus.activeProjects;
+    }
+
+    const components = await this.probeComponents();
+    for (const [name, healthy] of Object.entries(components)) {
+      snapshot[`component.${name}`] = healthy ? 1 : 0;
+    }
+
+    for (const [name, result] of this.syntheticProbes.results) {
+      snapshot[`probe.${name}.up`] = result.up ? 1 : 0;
+      snapshot[`probe.${name}.latency`] = result.latencyMs;
+    }
+
+    return snapshot;
+  }
+
+  async evaluateAlertRules() {
+    if (!this.config.alertRulesEnabled) return;
+
+    try {
+      this.alertRules.evaluate(await this.getMetricSnapshot());
+      await this.alertRules.purgeExpiredSilences();
+    } catch (error) {
+      this.logger.error('Failed to evaluate alert rules:', error);
+    }
+  }
+
+  // Firing rules feed the same 'alert' stream as the built-in thresholds,
+  // so incident tracking picks them up; silenced alerts are not routed
+  routeRuleAlerts() {
+    this.alertRules.on('alert:firing', (alert) => {
+      this.emit('rule:firing', alert);
+      if (alert.silenced) return;
+
+      const routed = {
+        type: `rule:${alert.ruleId}`,
+        level: alert.severity,
+        message: alert.message,
+        threshold: alert.threshold,
+        current: alert.value,
+        timestamp: alert.timestamp,
+      };
+      this.emit('alert', routed);
+      this.alerts.set(`${routed.type}-${routed.timestamp}`, routed);
+    });
+
+    this.alertRules.on('alert:resolved', (alert) => {
+      this.emit('rule:resolved', alert);
+    });
+  }
+
   cleanupOldAlerts() {
     const now = Date.now();
     const maxAge = 24 * 60 * 60 * 1000; // 24 hours
@@ -419,7 +609,62 @@ class StatusMonitor extends EventEmitter {
     }
   }
 
+  registerComponent(name, probe) {
+    this.components.set(name, probe);
+  }
+
+  // A probe that throws or misses its deadline counts as down
+  async probeComponents(options = {}) {
+    const { results } = await fanOut(
+      Object.fromEntries(this.components),
+      this.queryOptions(options)
+    );
+
+    return Object.fromEntries(
+      Array.from(this.components.keys(), (name) => [
+        name,
+        Boolean(results[name]),
+      ])
+    );
+  }
+
+  async recordAvailability() {
+    try {
+      const results = await this.probeComponents();
+      await this.metricsCollector.recordAvailability(results);
+    } catch (error) {
+      this.logger.error('Failed to record availability:', error);
+    }
+  }
+
   // Public API methods
+  async getStatusPage(options = {}) {
+    const current = await this.probeComponents(options);
+    const availability = this.metricsCollector.getAvailability();
+    const components = {};
+
+    for (const [name, healthy] of Object.entries(current)) {
+      components[name] = {
+        status: healthy ? 'operational' : 'down',
+        uptime: availability[name]?.uptime ?? null,
+        history: availability[name]?.history || [],
+      };
+    }
+
+    const down = Object.values(current).filter((healthy) => !healthy).length;
+
+    return {
+      timestamp: new Date().toISOString(),
+      status:
+        down === 0
+          ? 'operational'
+          : down === Object.keys(current).length
+            ? 'major_outage'
+            : 'degraded',
+      components,
+    };
+  }
+
   async getSystemStatus(detailed = false) {
     if (detailed) {
       await this.collectSystemStatus();
@@ -455,6 +700,15 @@ class StatusMonitor extends EventEmitter {
     return status;
   }
 
+  // Readiness from cached state only, so frequent /readyz polling does not
+  // re-collect system stats; critical probes must be up
+  isReady() {
+    const probesUp = Array.from(this.syntheticProbes.results.values()).every(
+      (result) => result.up || !result.critical
+    );
+    return this.processManager.isHealthy() && probesUp;
+  }
+
   async getHealthCheck(includeServices = true) {
     const health = {
       timestamp: new Date().toISOString(),
@@ -484,6 +738,19 @@ class StatusMonitor extends EventEmitter {
         details: `Active projects: ${this.processManager.getActiveProjectsCount()}`,
       };
 
+      // Synthetic probes; only critical ones affect overall health
+      for (const [name, result] of this.syntheticProbes.results) {
+        health.services[`probe:${name}`] = {
+          healthy: result.up || !result.critical,
+          status: result.up ? 'healthy' : 'unhealthy',
+          details: result.up
+            ? `Latency: ${result.latencyMs}ms`
+            : `Error: ${result.error}`,
+          critical: result.critical,
+          checkedAt: result.timestamp,
+        };
+      }
+
       // Check overall health
       health.overall.healthy = Object.values(health.services).every(
         (service) => service.healthy
@@ -627,6 +894,7 @@ class StatusMonitor extends EventEmitter {
   async stop() {
     try {
       this.stopMonitoring();
+      this.syntheticProbes.stop();
 
       // Stop all watchers
       for (const watcher of this.watchers.values()) {
@@ -0,0 +1,162 @@
+/**
+ * Synthetic Probes
+ * Scheduled HTTP and TCP checks against external dependencies
+ */
+
+import { EventEmitter } from 'events';
+import net from 'net';
+import { Logger } from './logger.js';
+
+const PROBE_TYPES = ['http', 'tcp'];
+
+class SyntheticProbeRunner extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    // Spread first so keys passed as undefined still get their defaults
+    this.config = {
+      ...config,
+      probes: config.probes || [],
+      defaultInterval: config.defaultInterval || 60000, // 1 minute
+      defaultTimeout: config.defaultTimeout || 5000, // 5 seconds
+    };
+
+    this.logger = new Logger('SyntheticProbes');
+    this.probes = new Map();
+    this.results = new Map();
+    this.timers = new Map();
+
+    for (const probe of this.config.probes) {
+      this.addProbe(probe);
+    }
+  }
+
+  addProbe(probe) {
+    if (!probe.name) {
+      throw new Error('Probe name is required');
+    }
+    if (!PROBE_TYPES.includes(probe.type)) {
+      throw new Error(`Invalid probe type: ${probe.type}`);
+    }
+    if (probe.type === 'http' && !probe.url) {
+      throw new Error(`HTTP probe ${probe.name} requires a url`);
+    }
+    if (probe.type === 'tcp' && !(probe.host && probe.port)) {
+      throw new Error(`TCP probe ${probe.name} requires host and port`);
+    }
+
+    this.probes.set(probe.name, {
+      interval: this.config.defaultInterval,
+      timeout: this.config.defaultTimeout,
+      critical: false,
+      ...probe,
+    });
+  }
+
+  start() {
+    for (const probe of this.probes.values()) {
+      this.runProbe(probe);
+      this.timers.set(
+        probe.name,
+        setInterval(() => this.runProbe(probe), probe.interval)
+      );
+    }
+
+    if (this.probes.size > 0) {
+      this.logger.info(`Started ${this.probes.size} synthetic probes`);
+    }
+  }
+
+  stop() {
+    for (const timer of this.timers.values()) {
+      clearInterval(timer);
+    }
+    this.timers.clear();
+  }
+
+  async runProbe(probe) {
+    const startTime = Date.now();
+    let result;
+
+    try {
+      const details =
+        probe.type === 'http'
+          ? await this.checkHTTP(probe)
+          : await this.checkTCP(probe);
+
+      result = {
+        name: probe.name,
+        type: probe.type,
+        up: true,
+        latencyMs: Date.now() - startTime,
+        ...details,
+      };
+    } catch (error) {
+      result = {
+        name: probe.name,
+        type: probe.type,
+        up: false,
+        latencyMs: Date.now() - startTime,
+        error: error.message,
+      };
+    }
+
+    result.critical = probe.critical;
+    result.timestamp = new Date().toISOString();
+
+    const previous = this.results.get(probe.name);
+    if (previous && previous.up !== result.up) {
+      this.logger.warn(
+        `Probe ${probe.name} is now ${result.up ? 'up' : 'down'}`,
+        { error: result.error }
+      );
+    }
+
+    this.results.set(probe.name, result);
+    this.emit('probe:result', result);
+
+    return result;
+  }
+
+  async checkHTTP(probe) {
+    const response = await fetch(probe.url, {
+      method: probe.method || 'GET',
+      headers: probe.headers,
+      signal: AbortSignal.timeout(probe.timeout),
+    });
+
+    const expected = probe.expectStatus
+      ? response.status === probe.expectStatus
+      : response.status < 400;
+    if (!expected) {
+      throw new Error(`Unexpected status ${response.status}`);
+    }
+
+    return { status: response.status };
+  }
+
+  checkTCP(probe) {
+    return new Promise((resolve, reject) => {
+      const socket = net.connect({ host: probe.host, port: probe.port });
+
+      socket.setTimeout(probe.timeout);
+      socket.once('connect', () => {
+        socket.end();
+        resolve({});
+      });
+      socket.once('timeout', () => {
+        socket.destroy();
+        reject(new Error('Connection timed out'));
+      });
+      socket.once('error', (error) => {
+        socket.destroy();
+        reject(error);
+      });
+    });
+  }
+
+  getResults() {
+    return Object.fromEntries(this.results);
+  }
+}
+
+export { SyntheticProbeRunner, PROBE_TYPES };
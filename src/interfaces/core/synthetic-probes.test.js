@@ -0,0 +1,141 @@
+/**
+ * Tests for Synthetic Probes
+ */
+
+import { SyntheticProbeRunner } from './synthetic-probes.js';
+import { jest } from '@jest/globals';
+import http from 'http';
+import net from 'net';
+
+describe('SyntheticProbeRunner', () => {
+  let server;
+  let port;
+  let runner;
+
+  beforeEach(async () => {
+    server = http.createServer((req, res) => {
+      res.statusCode = req.url === '/broken' ? 503 : 204;
+      res.end();
+    });
+    await new Promise((resolve) => server.listen(0, '127.0.0.1', resolve));
+    port = server.address().port;
+    runner = new SyntheticProbeRunner({ defaultTimeout: 1000 });
+  });
+
+  afterEach(async () => {
+    runner.stop();
+    await new Promise((resolve) => server.close(resolve));
+  });
+
+  // A port that was just released, so nothing is listening on it
+  const closedPort = async () => {
+    const probe = net.createServer();
+    await new Promise((resolve) => probe.listen(0, '127.0.0.1', resolve));
+    const { port: free } = probe.address();
+    await new Promise((resolve) => probe.close(resolve));
+    return free;
+  };
+
+  describe('addProbe', () => {
+    it('should validate probe definitions', () => {
+      expect(() => runner.addProbe({ type: 'http' })).toThrow(
+        'Probe name is required'
+      );
+      expect(() => runner.addProbe({ name: 'dns', type: 'udp' })).toThrow(
+        'Invalid probe type: udp'
+      );
+      expect(() => runner.addProbe({ name: 'api', type: 'http' })).toThrow(
+        'HTTP probe api requires a url'
+      );
+      expect(() =>
+        runner.addProbe({ name: 'db', type: 'tcp', host: 'localhost' })
+      ).toThrow('TCP probe db requires host and port');
+    });
+
+    it('should fill in interval and timeout defaults', () => {
+      runner.addProbe({ name: 'api', type: 'http', url: 'http://x' });
+
+      expect(runner.probes.get('api')).toMatchObject({
+        interval: 60000,
+        timeout: 1000,
+        critical: false,
+      });
+    });
+  });
+
+  describe('runProbe', () => {
+    it('should report HTTP status and latency', async () => {
+      runner.addProbe({
+        name: 'api',
+        type: 'http',
+        url: `http://127.0.0.1:${port}/health`,
+        critical: true,
+      });
+
+      const result = await runner.runProbe(runner.probes.get('api'));
+
+      expect(result).toMatchObject({ up: true, status: 204, critical: true });
+      expect(result.latencyMs).toBeGreaterThanOrEqual(0);
+      expect(runner.getResults().api).toBe(result);
+    });
+
+    it('should mark unexpected statuses as down', async () => {
+      runner.addProbe({
+        name: 'api',
+        type: 'http',
+        url: `http://127.0.0.1:${port}/broken`,
+      });
+
+      const result = await runner.runProbe(runner.probes.get('api'));
+
+      expect(result).toMatchObject({
+        up: false,
+        error: 'Unexpected status 503',
+      });
+    });
+
+    it('should honor expectStatus', async () => {
+      runner.addProbe({
+        name: 'api',
+        type: 'http',
+        url: `http://127.0.0.1:${port}/health`,
+        expectStatus: 200,
+      });
+
+      const result = await runner.runProbe(runner.probes.get('api'));
+
+      expect(result.up).toBe(false);
+    });
+
+    it('should check TCP reachability', async () => {
+      runner.addProbe({ name: 'up', type: 'tcp', host: '127.0.0.1', port });
+      runner.addProbe({
+        name: 'down',
+        type: 'tcp',
+        host: '127.0.0.1',
+        port: await closedPort(),
+      });
+
+      expect((await runner.runProbe(runner.probes.get('up'))).up).toBe(true);
+      expect((await runner.runProbe(runner.probes.get('down'))).up).toBe(
+        false
+      );
+    });
+
+    it('should emit every result', async () => {
+      const listener = jest.fn();
+      runner.on('probe:result', listener);
+      runner.addProbe({
+        name: 'api',
+        type: 'http',
+        url: `http://127.0.0.1:${port}/health`,
+      });
+
+      await runner.runProbe(runner.probes.get('api'));
+
+      expect(listener).toHaveBeenCalledWith(
+        expect.objectContaining({ name: 'api', up: true })
+      );
+    });
+  });
+});
@@ -0,0 +1,131 @@
+/**
+ * TOTP
+ * Time-based one-time passwords (RFC 6238) compatible with authenticator
+ * apps, plus the otpauth:// provisioning URI they scan as a QR code
+ */
+
+import crypto from 'crypto';
+
+const BASE32_ALPHABET = 'ABCDEFGHIJKLMNOPQRSTUVWXYZ234567';
+
+function base32Encode(buffer) {
+  let bits = 0;
+  let value = 0;
+  let output = '';
+
+  for (const byte of buffer) {
+    value = (value << 8) | byte;
+    bits += 8;
+    while (bits >= 5) {
+      output += BASE32_ALPHABET[(value >>> (bits - 5)) & 31];
+      bits -= 5;
+    }
+  }
+  if (bits > 0) {
+    output += BASE32_ALPHABET[(value << (5 - bits)) & 31];
+  }
+
+  return output;
+}
+
+// Accepts the lowercase, spaced and padded forms apps display
+function base32Decode(input) {
+  const clean = String(input).toUpperCase().replace(/[\s=-]/g, '');
+  let bits = 0;
+  let value = 0;
+  const bytes = [];
+
+  for (const char of clean) {
+    const index = BASE32_ALPHABET.indexOf(char);
+    if (index === -1) {
+      throw new Error('Invalid base32 secret');
+    }
+    value = (value << 5) | index;
+    bits += 5;
+    if (bits >= 8) {
+      bytes.push((value >>> (bits - 8)) & 255);
+      bits -= 8;
+    }
+  }
+
+  return Buffer.from(bytes);
+}
+
+function generateSecret(length = 20) {
+  return base32Encode(crypto.randomBytes(length));
+}
+
+// HOTP (RFC 4226) for one counter value
+function generateHOTP(secret, counter, digits = 6) {
+  const buffer = Buffer.alloc(8);
+  buffer.writeBigUInt64BE(BigInt(counter));
+
+  const digest = crypto
+    .createHmac('sha1', base32Decode(secret))
+    .update(buffer)
+    .digest();
+  const offset = digest[digest.length - 1] & 15;
+  const code = (digest.readUInt32BE(offset) & 0x7fffffff) % 10 ** digits;
+
+  return String(code).padStart(digits, '0');
+}
+
+function timeCounter(time = Date.now(), step = 30) {
+  return Math.floor(time / 1000 / step);
+}
+
+function generateTOTP(
+  secret,
+  { time = Date.now(), step = 30, digits = 6 } = {}
+) {
+  return generateHOTP(secret, timeCounter(time, step), digits);
+}
+
+/**
+ * Check a code against the current step and `window` steps either side to
+ * allow for clock drift. Returns the matching counter, so callers can
+ * reject a code that was already used, or null.
+ */
+function verifyTOTP(
+  secret,
+  code,
+  { time = Date.now(), step = 30, digits = 6, window = 1 } = {}
+) {
+  const candidate = String(code || '').replace(/\s/g, '');
+  if (!new RegExp(`^\\d{${digits}}$`).test(candidate)) return null;
+
+  const current = timeCounter(time, step);
+  for (let offset = -window; offset <= window; offset++) {
+    const expected = generateHOTP(secret, current + offset, digits);
+    if (
+      crypto.timingSafeEqual(Buffer.from(expected), Buffer.from(candidate))
+    ) {
+      return current + offset;
+    }
+  }
+
+  return null;
+}
+
+function buildOtpauthURI({ secret, account, issuer, digits = 6, step = 30 }) {
+  const label = encodeURIComponent(`${issuer}:${account}`);
+  const params = new URLSearchParams({
+    secret,
+    issuer,
+    algorithm: 'SHA1',
+    digits: String(digits),
+    period: String(step),
+  });
+
+  return `otpauth://totp/${label}?${params}`;
+}
+
+export {
+  base32Encode,
+  base32Decode,
+  generateSecret,
+  generateHOTP,
+  generateTOTP,
+  verifyTOTP,
+  buildOtpauthURI,
+};
@@ -0,0 +1,121 @@
+/**
+ * Tests for TOTP
+ */
+
+import {
+  base32Encode,
+  base32Decode,
+  generateSecret,
+  generateHOTP,
+  generateTOTP,
+  verifyTOTP,
+  buildOtpauthURI,
+} from './totp.js';
+
+// The RFC 4226 / RFC 6238 test key, ASCII "12345678901234567890"
+const RFC_SECRET = base32Encode(Buffer.from('12345678901234567890'));
+
+describe('TOTP', () => {
+  describe('base32', () => {
+    it('should encode the RFC test key', () => {
+      expect(RFC_SECRET).toBe('GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ');
+    });
+
+    it('should decode lowercase, spaced and padded input', () => {
+      const secret = generateSecret();
+      const spaced = secret.toLowerCase().replace(/(.{4})/g, '$1 ');
+
+      expect(base32Decode(`${spaced}====`)).toEqual(base32Decode(secret));
+    });
+
+    it('should reject characters outside the alphabet', () => {
+      expect(() => base32Decode('ABC1')).toThrow('Invalid base32 secret');
+    });
+  });
+
+  describe('generateHOTP', () => {
+    it('should match the RFC 4226 vectors', () => {
+      const expected = [
+        '755224',
+        '287082',
+        '359152',
+        '969429',
+        '338314',
+        '254676',
+        '287922',
+        '162583',
+        '399871',
+        '520489',
+      ];
+
+      expected.forEach((code, counter) => {
+        expect(generateHOTP(RFC_SECRET, counter)).toBe(code);
+      });
+    });
+  });
+
+  describe('generateTOTP', () => {
+    it('should match the RFC 6238 SHA-1 vectors', () => {
+      const vectors = [
+        [59, '94287082'],
+        [1111111109, '07081804'],
+        [1111111111, '14050471'],
+        [1234567890, '89005924'],
+        [2000000000, '69279037'],
+        [20000000000, '65353130'],
+      ];
+
+      for (const [seconds, code] of vectors) {
+        expect(
+          generateTOTP(RFC_SECRET, { time: seconds * 1000, digits: 8 })
+        ).toBe(code);
+      }
+    });
+  });
+
+  describe('verifyTOTP', () => {
+    const time = 1111111111 * 1000;
+
+    it('should return the matching counter', () => {
+      const code = generateTOTP(RFC_SECRET, { time });
+
+      expect(verifyTOTP(RFC_SECRET, code, { time })).toBe(
+        Math.floor(1111111111 / 30)
+      );
+    });
+
+    it('should accept codes one step either side', () => {
+      const previous = generateTOTP(RFC_SECRET, { time: time - 30000 });
+      const next = generateTOTP(RFC_SECRET, { time: time + 30000 });
+
+      expect(verifyTOTP(RFC_SECRET, previous, { time })).not.toBeNull();
+      expect(verifyTOTP(RFC_SECRET, next, { time })).not.toBeNull();
+    });
+
+    it('should reject codes outside the window', () => {
+      const old = generateTOTP(RFC_SECRET, { time: time - 90000 });
+
+      expect(verifyTOTP(RFC_SECRET, old, { time })).toBeNull();
+    });
+
+    it('should reject malformed codes', () => {
+      expect(verifyTOTP(RFC_SECRET, '12345', { time })).toBeNull();
+      expect(verifyTOTP(RFC_SECRET, 'abcdef', { time })).toBeNull();
+      expect(verifyTOTP(RFC_SECRET, null, { time })).toBeNull();
+    });
+  });
+
+  describe('buildOtpauthURI', () => {
+    it('should build a provisioning URI', () => {
+      const uri = buildOtpauthURI({
+        secret: RFC_SECRET,
+        account: 'alice@example.com',
+        issuer: 'R&D Platform',
+      });
+
+      expect(uri).toMatch(/^otpauth:\/\/totp\/R%26D%20Platform%3Aalice/);
+      expect(new URL(uri).searchParams.get('secret')).toBe(RFC_SECRET);
+      expect(new URL(uri).searchParams.get('period')).toBe('30');
+    });
+  });
+});
@@ -0,0 +1,153 @@
+/**
+ * Version Advisor
+ * Reports server/client compatibility and tracks which deprecated
+ * endpoints each caller has recently used
+ */
+
+import { Logger } from './logger.js';
+
+const DEFAULT_MIN_CLIENT_VERSIONS = {
+  cli: '1.0.0',
+  mcp: '1.0.0',
+};
+
+// "1.2.3" -> [1, 2, 3]; pre-release and build suffixes are ignored
+function parseVersion(version) {
+  const match = /^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?/.exec(String(version));
+  if (!match) return null;
+  return match.slice(1).map((part) => Number(part || 0));
+}
+
+function compareVersions(a, b) {
+  const left = parseVersion(a);
+  const right = parseVersion(b);
+  for (let i = 0; i < 3; i++) {
+    if (left[i] !== right[i]) return left[i] - right[i];
+  }
+  return 0;
+}
+
+class VersionAdvisor {
+  constructor(config = {}) {
+    this.config = {
+      serverVersion:
+        config.serverVersion || process.env.npm_package_version || '1.0.0',
+      // [{ method, path, since, removeIn, replacement }]; path is a prefix
+      deprecations: config.deprecations || [],
+      usageWindow: config.usageWindow || 7 * 24 * 60 * 60 * 1000, // 7 days
+      maxCallers: config.maxCallers || 1000,
+      ...config,
+      minClientVersions: {
+        ...DEFAULT_MIN_CLIENT_VERSIONS,
+        ...config.minClientVersions,
+      },
+    };
+
+    this.logger = new Logger('VersionAdvisor');
+
+    // caller key -> Map(deprecation key -> { count, lastSeen })
+    this.usage = new Map();
+  }
+
+  static callerKey(req) {
+    const client = req.get('X-Client-Name') || req.get('User-Agent') || '';
+    return `${req.ip}|${client}`;
+  }
+
+  findDeprecation(method, path) {
+    return this.config.deprecations.find(
+      (deprecation) =>
+        (!deprecation.method || deprecation.method === method) &&
+        path.startsWith(deprecation.path)
+    );
+  }
+
+  // Flags deprecated endpoints in the response and remembers the caller
+  middleware() {
+    return (req, res, next) => {
+      const deprecation = this.findDeprecation(req.method, req.path);
+      if (deprecation) {
+        res.set('Deprecation', 'true');
+        if (deprecation.sunset) {
+          res.set('Sunset', new Date(deprecation.sunset).toUTCString());
+        }
+        this.recordUsage(VersionAdvisor.callerKey(req), deprecation);
+      }
+      next();
+    };
+  }
+
+  recordUsage(callerKey, deprecation) {
+    let calls = this.usage.get(callerKey);
+    if (!calls) {
+      // Oldest caller goes first once the table is full
+      if (this.usage.size >= this.config.maxCallers) {
+        this.usage.delete(this.usage.keys().next().value);
+      }
+      calls = new Map();
+      this.usage.set(callerKey, calls);
+    }
+
+    const key = `${deprecation.method || '*'} ${deprecation.path}`;
+    const entry = calls.get(key) || { count: 0, deprecation };
+    entry.count++;
+    entry.lastSeen = Date.now();
+    calls.set(key, entry);
+  }
+
+  recentUsage(callerKey, now = Date.now()) {
+    const calls = this.usage.get(callerKey);
+    if (!calls) return [];
+
+    return Array.from(calls.values())
+      .filter((entry) => now - entry.lastSeen <= this.config.usageWindow)
+      .map((entry) => ({
+        ...entry.deprecation,
+        count: entry.count,
+        lastSeen: new Date(entry.lastSeen).toISOString(),
+      }));
+  }
+
+  advise({ client, version, callerKey } = {}) {
+    const minimum = this.config.minClientVersions[client] || null;
+    const parsed = version ? parseVersion(version) : null;
+
+    let compatibility = 'unknown';
+    if (minimum && parsed) {
+      compatibility =
+        compareVersions(version, minimum) >= 0 ? 'supported' : 'unsupported';
+    } else if (client && version && !parsed) {
+      compatibility = 'invalid-version';
+    }
+
+    if (compatibility === 'unsupported') {
+      this.logger.warn(`Unsupported client connected: ${client} ${version}`);
+    }
+
+    const deprecatedEndpointsUsed = callerKey
+      ? this.recentUsage(callerKey)
+      : [];
+
+    return {
+      serverVersion: this.config.serverVersion,
+      minimumClientVersions: this.config.minClientVersions,
+      client: client ? { name: client, version, minimum, compatibility } : null,
+      deprecatedEndpointsUsed,
+      deprecations: this.config.deprecations,
+      warnings: [
+        ...(compatibility === 'unsupported'
+          ? [
+              `${client} ${version} is older than the minimum supported ${minimum}; upgrade the client`,
+            ]
+          : []),
+        ...deprecatedEndpointsUsed.map(
+          (entry) =>
+            `Deprecated endpoint in use: ${entry.method || '*'} ${entry.path}` +
+            (entry.replacement ? ` (use ${entry.replacement})` : '')
+        ),
+      ],
+    };
+  }
+}
+
+export { VersionAdvisor, parseVersion, compareVersions };
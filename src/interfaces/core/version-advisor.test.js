@@ -0,0 +1,118 @@
+/**
+ * Tests for Version Advisor
+ */
+
+import {
+  VersionAdvisor,
+  parseVersion,
+  compareVersions,
+} from './version-advisor.js';
+import { jest } from '@jest/globals';
+
+describe('version advisor', () => {
+  describe('parseVersion', () => {
+    it('should ignore prefixes and pre-release suffixes', () => {
+      expect(parseVersion('v1.2.3-beta.1')).toEqual([1, 2, 3]);
+      expect(parseVersion('2')).toEqual([2, 0, 0]);
+      expect(parseVersion('latest')).toBeNull();
+    });
+
+    it('should compare numerically', () => {
+      expect(compareVersions('1.10.0', '1.9.9')).toBeGreaterThan(0);
+      expect(compareVersions('1.0', '1.0.0')).toBe(0);
+      expect(compareVersions('0.9.0', '1.0.0')).toBeLessThan(0);
+    });
+  });
+
+  describe('VersionAdvisor', () => {
+    let advisor;
+
+    beforeEach(() => {
+      advisor = new VersionAdvisor({
+        serverVersion: '2.0.0',
+        minClientVersions: { cli: '1.5.0' },
+        deprecations: [
+          {
+            method: 'GET',
+            path: '/api/v1/projects',
+            replacement: '/api/projects',
+            sunset: '2027-01-01',
+          },
+        ],
+      });
+    });
+
+    // Minimal Express-like request/response pair for the middleware
+    const call = (method, path, headers = {}) => {
+      const req = {
+        method,
+        path,
+        ip: '10.0.0.1',
+        get: (name) => headers[name],
+      };
+      const res = { headers: {}, set: (k, v) => (res.headers[k] = v) };
+      const next = jest.fn();
+      advisor.middleware()(req, res, next);
+      return { req, res, next };
+    };
+
+    it('should classify client compatibility', () => {
+      const compatibility = (client, version) =>
+        advisor.advise({ client, version }).client.compatibility;
+
+      expect(compatibility('cli', '1.5.0')).toBe('supported');
+      expect(compatibility('cli', '1.4.9')).toBe('unsupported');
+      expect(compatibility('cli', 'nightly')).toBe('invalid-version');
+      expect(compatibility('desktop', '1.0.0')).toBe('unknown');
+      expect(advisor.advise({ client: 'mcp' }).client.minimum).toBe('1.0.0');
+    });
+
+    it('should warn unsupported clients to upgrade', () => {
+      const { warnings } = advisor.advise({ client: 'cli', version: '1.0.0' });
+
+      expect(warnings).toEqual([
+        'cli 1.0.0 is older than the minimum supported 1.5.0; upgrade the client',
+      ]);
+    });
+
+    it('should flag deprecated endpoints in the response', () => {
+      const { res, next } = call('GET', '/api/v1/projects/p1');
+
+      expect(res.headers.Deprecation).toBe('true');
+      expect(res.headers.Sunset).toBe('Fri, 01 Jan 2027 00:00:00 GMT');
+      expect(next).toHaveBeenCalledTimes(1);
+
+      expect(call('POST', '/api/v1/projects').res.headers).toEqual({});
+    });
+
+    it('should report deprecated endpoints each caller used', () => {
+      const headers = { 'X-Client-Name': 'cli' };
+      const { req } = call('GET', '/api/v1/projects', headers);
+      call('GET', '/api/v1/projects', headers);
+      call('GET', '/api/v1/projects', { 'X-Client-Name': 'mcp' });
+
+      const callerKey = VersionAdvisor.callerKey(req);
+      const advice = advisor.advise({ callerKey });
+
+      expect(advice.deprecatedEndpointsUsed).toHaveLength(1);
+      expect(advice.deprecatedEndpointsUsed[0].count).toBe(2);
+      expect(advice.warnings).toEqual([
+        'Deprecated endpoint in use: GET /api/v1/projects (use /api/projects)',
+      ]);
+      expect(
+        advisor.recentUsage(callerKey, Date.now() + 8 * 24 * 60 * 60 * 1000)
+      ).toEqual([]);
+    });
+
+    it('should evict the oldest caller when the table is full', () => {
+      advisor.config.maxCallers = 2;
+      const [deprecation] = advisor.config.deprecations;
+
+      for (const caller of ['a', 'b', 'c']) {
+        advisor.recordUsage(caller, deprecation);
+      }
+
+      expect(Array.from(advisor.usage.keys())).toEqual(['b', 'c']);
+    });
+  });
+});
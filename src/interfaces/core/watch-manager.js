@@ -0,0 +1,245 @@
+/**
+ * Watch Manager
+ * Lets users follow items they did not author, fans changes out to the
+ * watchers, and keeps a per-user feed of activity on watched items
+ */
+
+import { EventEmitter } from 'events';
+import { promises as fs } from 'fs';
+import path from 'path';
+import crypto from 'crypto';
+import { Logger } from './logger.js';
+
+// Kinds of item that can be watched
+const WATCHABLE_TYPES = ['project'];
+
+// Why a user is watching: "manual" through the API, the others are added
+// when the user takes part in the item
+const WATCH_REASONS = ['manual', 'created', 'participated'];
+
+class WatchManager extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      watchesFile: config.watchesFile || './data/watches.json',
+      feedLimit: config.feedLimit || 200, // entries kept per user
+      ...config,
+    };
+
+    this.logger = new Logger('WatchManager');
+
+    // "type:id" -> Map(userId -> { reason, since }); userId -> feed entries
+    this.watches = new Map();
+    this.feeds = new Map();
+  }
+
+  async initialize() {
+    try {
+      await this.load();
+      this.logger.info('WatchManager initialized successfully');
+    } catch (error) {
+      this.logger.error('Failed to initialize WatchManager:', error);
+      throw error;
+    }
+  }
+
+  async load() {
+    try {
+      const data = JSON.parse(
+        await fs.readFile(this.config.watchesFile, 'utf8')
+      );
+
+      for (const [key, watchers] of Object.entries(data.watches || {})) {
+        this.watches.set(key, new Map(Object.entries(watchers)));
+      }
+      for (const [userId, feed] of Object.entries(data.feeds || {})) {
+        this.feeds.set(userId, feed);
+      }
+    } catch (error) {
+      if (error.code !== 'ENOENT') {
+        this.logger.error('Failed to load watches:', error);
+        throw error;
+      }
+    }
+  }
+
+  async save() {
+    try {
+      await fs.mkdir(path.dirname(this.config.watchesFile), {
+        recursive: true,
+      });
+      await fs.writeFile(
+        this.config.watchesFile,
+        JSON.stringify(
+          {
+            watches: Object.fromEntries(
+              Array.from(this.watches, ([key, watchers]) => [
+                key,
+                Object.fromEntries(watchers),
+              ])
+            ),
+            feeds: Object.fromEntries(this.feeds),
+          },
+          null,
+          2
+        )
+      );
+    } catch (error) {
+      this.logger.error('Failed to save watches:', error);
+      throw error;
+    }
+  }
+
+  key(itemType, itemId) {
+    if (!WATCHABLE_TYPES.includes(itemType)) {
+      throw new Error(`Cannot watch ${itemType} items`);
+    }
+    return `${itemType}:${itemId}`;
+  }
+
+  // A manual watch replaces an automatic one; an automatic watch never
+  // downgrades a manual one
+  async watch(userId, itemType, itemId, reason = 'manual') {
+    if (!WATCH_REASONS.includes(reason)) {
+      throw new Error(`Invalid watch reason: ${reason}`);
+    }
+
+    const key = this.key(itemType, itemId);
+    if (!this.watches.has(key)) {
+      this.watches.set(key, new Map());
+    }
+
+    const watchers = this.watches.get(key);
+    const existing = watchers.get(userId);
+    if (existing && (existing.reason === 'manual' || reason !== 'manual')) {
+      return { itemType, itemId, ...existing };
+    }
+
+    const watch = { reason, since: new Date().toISOString() };
+    watchers.set(userId, watch);
+    await this.save();
+
+    return { itemType, itemId, ...watch };
+  }
+
+  async unwatch(userId, itemType, itemId) {
+    const key = this.key(itemType, itemId);
+    const watchers = this.watches.get(key);
+    if (!watchers?.delete(userId)) {
+      throw new Error(`Not watching ${key}`);
+    }
+
+    if (watchers.size === 0) {
+      this.watches.delete(key);
+    }
+    await this.save();
+
+    return { success: true };
+  }
+
+  isWatching(userId, itemType, itemId) {
+    return this.watches.get(this.key(itemType, itemId))?.has(userId) || false;
+  }
+
+  getWatchers(itemType, itemId) {
+    return Array.from(
+      this.watches.get(this.key(itemType, itemId))?.keys() || []
+    );
+  }
+
+  listWatched(userId) {
+    const watched = [];
+    for (const [key, watchers] of this.watches) {
+      const watch = watchers.get(userId);
+      if (watch) {
+        const [itemType, itemId] = key.split(':');
+        watched.push({ itemType, itemId, ...watch });
+      }
+    }
+    return watched.sort((a, b) => b.since.localeCompare(a.since));
+  }
+
+  /**
+   * Record a change to an item and notify its watchers. The actor, when
+   * known, is not notified about their own change.
+   */
+  async recordActivity({
+    itemType,
+    itemId,
+    itemName,
+    event,
+    summary,
+    actorId,
+  }) {
+    const recipients = this.getWatchers(itemType, itemId).filter(
+      (userId) => userId !== actorId
+    );
+    if (recipients.length === 0) {
+      return [];
+    }
+
+    const entry = {
+      id: crypto.randomUUID(),
+      itemType,
+      itemId,
+      itemName: itemName || null,
+      event,
+      summary,
+      actorId: actorId || null,
+      at: new Date().toISOString(),
+    };
+
+    for (const userId of recipients) {
+      const feed = this.feeds.get(userId) || [];
+      feed.unshift(entry);
+      this.feeds.set(userId, feed.slice(0, this.config.feedLimit));
+    }
+    await this.save();
+
+    for (const userId of recipients) {
+      this.emit('watch:activity', { userId, entry });
+    }
+
+    return recipients;
+  }
+
+  // Newest first; `before` is an entry timestamp for paging
+  getFeed(userId, { limit = 50, before } = {}) {
+    const feed = this.feeds.get(userId) || [];
+    return feed
+      .filter((entry) => !before || entry.at < before)
+      .slice(0, Math.min(limit, this.config.feedLimit));
+  }
+
+  // Drops the watchers of an item that no longer exists
+  async removeItem(itemType, itemId) {
+    if (this.watches.delete(this.key(itemType, itemId))) {
+      await this.save();
+    }
+  }
+
+  async removeUser(userId) {
+    let changed = this.feeds.delete(userId);
+    for (const [key, watchers] of this.watches) {
+      if (watchers.delete(userId)) {
+        changed = true;
+        if (watchers.size === 0) this.watches.delete(key);
+      }
+    }
+    if (changed) {
+      await this.save();
+    }
+  }
+
+  async stop() {
+    try {
+      await this.save();
+      this.logger.info('WatchManager stopped successfully');
+    } catch (error) {
+      this.logger.error('Error stopping WatchManager:', error);
+      throw error;
+    }
+  }
+}
+
+export { WatchManager, WATCHABLE_TYPES, WATCH_REASONS };
@@ -0,0 +1,140 @@
+/**
+ * Tests for Watch Manager
+ */
+
+import { WatchManager } from './watch-manager.js';
+import { jest } from '@jest/globals';
+import { promises as fs } from 'fs';
+import os from 'os';
+import path from 'path';
+
+describe('WatchManager', () => {
+  let dir;
+  let watchManager;
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'watches-'));
+    watchManager = new WatchManager({
+      watchesFile: path.join(dir, 'watches.json'),
+    });
+  });
+
+  afterEach(async () => {
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  const activity = (overrides = {}) => ({
+    itemType: 'project',
+    itemId: 'p1',
+    itemName: 'Apollo',
+    event: 'project:updated',
+    summary: 'Apollo was updated',
+    ...overrides,
+  });
+
+  describe('watch', () => {
+    it('should let a manual watch replace an automatic one', async () => {
+      await watchManager.watch('user-1', 'project', 'p1', 'participated');
+      const watch = await watchManager.watch('user-1', 'project', 'p1');
+
+      expect(watch.reason).toBe('manual');
+      expect(
+        (await watchManager.watch('user-1', 'project', 'p1', 'created')).reason
+      ).toBe('manual');
+    });
+
+    it('should reject unknown item types and reasons', async () => {
+      await expect(
+        watchManager.watch('user-1', 'incident', 'i1')
+      ).rejects.toThrow('Cannot watch incident items');
+      await expect(
+        watchManager.watch('user-1', 'project', 'p1', 'starred')
+      ).rejects.toThrow('Invalid watch reason: starred');
+    });
+
+    it('should unwatch and forget empty items', async () => {
+      await watchManager.watch('user-1', 'project', 'p1');
+
+      await watchManager.unwatch('user-1', 'project', 'p1');
+
+      expect(watchManager.isWatching('user-1', 'project', 'p1')).toBe(false);
+      expect(watchManager.watches.size).toBe(0);
+      await expect(
+        watchManager.unwatch('user-1', 'project', 'p1')
+      ).rejects.toThrow('Not watching project:p1');
+    });
+  });
+
+  describe('recordActivity', () => {
+    it('should notify every watcher except the actor', async () => {
+      const listener = jest.fn();
+      watchManager.on('watch:activity', listener);
+      await watchManager.watch('user-1', 'project', 'p1');
+      await watchManager.watch('user-2', 'project', 'p1', 'created');
+
+      const recipients = await watchManager.recordActivity(
+        activity({ actorId: 'user-2' })
+      );
+
+      expect(recipients).toEqual(['user-1']);
+      expect(listener).toHaveBeenCalledTimes(1);
+      expect(watchManager.getFeed('user-1')[0]).toMatchObject({
+        summary: 'Apollo was updated',
+        actorId: 'user-2',
+      });
+      expect(watchManager.getFeed('user-2')).toEqual([]);
+    });
+
+    it('should skip items nobody watches', async () => {
+      expect(await watchManager.recordActivity(activity())).toEqual([]);
+    });
+
+    it('should cap and page the feed newest first', async () => {
+      watchManager.config.feedLimit = 3;
+      await watchManager.watch('user-1', 'project', 'p1');
+
+      for (let i = 0; i < 5; i++) {
+        await watchManager.recordActivity(activity({ summary: `change ${i}` }));
+      }
+
+      const feed = watchManager.getFeed('user-1', { limit: 10 });
+      expect(feed.map((entry) => entry.summary)).toEqual([
+        'change 4',
+        'change 3',
+        'change 2',
+      ]);
+      expect(watchManager.getFeed('user-1', { limit: 1 })).toHaveLength(1);
+    });
+  });
+
+  describe('cleanup', () => {
+    it('should drop watchers of deleted items and removed users', async () => {
+      await watchManager.watch('user-1', 'project', 'p1');
+      await watchManager.watch('user-1', 'project', 'p2');
+      await watchManager.watch('user-2', 'project', 'p2');
+      await watchManager.recordActivity(activity({ itemId: 'p2' }));
+
+      await watchManager.removeItem('project', 'p1');
+      await watchManager.removeUser('user-1');
+
+      expect(watchManager.listWatched('user-1')).toEqual([]);
+      expect(watchManager.getFeed('user-1')).toEqual([]);
+      expect(watchManager.getWatchers('project', 'p2')).toEqual(['user-2']);
+    });
+
+    it('should persist watches and feeds', async () => {
+      await watchManager.watch('user-1', 'project', 'p1');
+      await watchManager.recordActivity(activity());
+
+      const reloaded = new WatchManager({
+        watchesFile: watchManager.config.watchesFile,
+      });
+      await reloaded.initialize();
+
+      expect(reloaded.listWatched('user-1')).toEqual([
+        expect.objectContaining({ itemType: 'project', itemId: 'p1' }),
+      ]);
+      expect(reloaded.getFeed('user-1')).toHaveLength(1);
+    });
+  });
+});
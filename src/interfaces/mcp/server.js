@@ -16,6 +16,12 @@ import { StatusMonitor } from '../core/status-monitor.js';
 import { AuthManager } from '../core/auth-manager.js';
 import { Logger } from '../core/logger.js';
 import { AIOrchestrator } from '../core/ai-orchestrator.js';
+import {
+  PrivacyGuard,
+  DATA_CLASSES,
+  redactProject,
+} from '../core/privacy-guard.js';
+import { PromptTemplateManager } from '../core/prompt-template-manager.js';
 
 class MCPServer {
   constructor(config = {}) {
@@ -43,6 +49,10 @@ class MCPServer {
     this.statusMonitor = new StatusMonitor();
     this.authManager = new AuthManager();
     this.aiOrchestrator = new AIOrchestrator();
+    this.aiOrchestrator.projectManager = this.projectManager;
+    this.privacyGuard = new PrivacyGuard();
+    this.aiOrchestrator.privacyGuard = this.privacyGuard;
+    this.promptTemplates = new PromptTemplateManager();
     this.logger = new Logger('MCPServer');
 
     this.setupTools();
@@ -77,6 +87,11 @@ class MCPServer {
                   description: 'Make project private',
                   default: false,
                 },
+                metadata: {
+                  type: 'object',
+                  description:
+                    'Ownership metadata (owner, cost_center, environment, compliance_tier)',
+                },
               },
               required: ['name'],
             },
@@ -96,6 +111,19 @@ class MCPServer {
                   type: 'string',
                   description: 'Filter projects by name or tag',
                 },
+                owner: { type: 'string', description: 'Filter by owner' },
+                cost_center: {
+                  type: 'string',
+                  description: 'Filter by cost center',
+                },
+                environment: {
+                  type: 'string',
+                  description: 'Filter by environment',
+                },
+                compliance_tier: {
+                  type: 'string',
+                  description: 'Filter by compliance tier',
+                },
               },
             },
           },
@@ -836,28 +864,25 @@ class MCPServer {
     const project = await this.projectManager.getProject(args.projectId);
     const status = await this.statusMonitor.getProjectStatus(args.projectId);
 
+    // The rendered prompt goes to the client's model, which we do not host
+    const shareContent = this.privacyGuard.authorize(
+      'llm',
+      DATA_CLASSES.USER_CONTENT,
+      { projectId: project.id, provider: 'mcp-client' }
+    );
+
+    const prompt = this.promptTemplates.render('project_analysis', {
+      project: shareContent ? project : redactProject(project),
+      status,
+    });
+    const label = shareContent ? project.name : project.id;
+
     return {
-      description: `Analyze project ${project.name}`,
+      description: `Analyze project ${label}`,
       messages: [
         {
           role: 'user',
-          content: {
-            type: 'text',
-            text: `Please analyze this project and provide insights:
-
-Project Information:
-${JSON.stringify(project, null, 2)}
-
-Current Status:
-${JSON.stringify(status, null, 2)}
-
-Please provide:
-1. Project structure analysis
-2. Performance insights
-3. Potential improvements
-4. Resource optimization suggestions
-5. Security considerations`,
-          },
+          content: { type: 'text', text: prompt.text },
         },
       ],
     };
@@ -867,30 +892,18 @@ Please provide:
     const systemStatus = await this.statusMonitor.getSystemStatus(true);
     const metrics = await this.statusMonitor.getSystemMetrics();
 
+    const prompt = this.promptTemplates.render('system_optimization', {
+      systemStatus,
+      metrics,
+      focus: args.focus || 'overall',
+    });
+
     return {
       description: 'System optimization recommendations',
       messages: [
         {
           role: 'user',
-          content: {
-            type: 'text',
-            text: `Please analyze the system and provide optimization recommendations:
-
-System Status:
-${JSON.stringify(systemStatus, null, 2)}
-
-Performance Metrics:
-${JSON.stringify(metrics, null, 2)}
-
-Focus Area: ${args.focus || 'overall'}
-
-Please provide:
-1. Performance bottlenecks
-2. Resource optimization opportunities
-3. Scalability recommendations
-4. Security improvements
-5. Maintenance suggestions`,
-          },
+          content: { type: 'text', text: prompt.text },
         },
       ],
     };
@@ -900,29 +913,25 @@ Please provide:
     const systemStatus = await this.statusMonitor.getSystemStatus(true);
     const health = await this.statusMonitor.getHealthCheck(true);
 
+    // The issue is written by the user and goes to the client's model
+    const shareIssue = this.privacyGuard.authorize(
+      'llm',
+      DATA_CLASSES.USER_CONTENT,
+      { provider: 'mcp-client' }
+    );
+
+    const prompt = this.promptTemplates.render('troubleshooting', {
+      issue: shareIssue ? args.issue : '[redacted]',
+      systemStatus,
+      health,
+    });
+
     return {
       description: 'Troubleshooting assistance',
       messages: [
         {
           role: 'user',
-          content: {
-            type: 'text',
-            text: `Please help troubleshoot this issue:
-
-Issue Description: ${args.issue}
-
-System Status:
-${JSON.stringify(systemStatus, null, 2)}
-
-Health Check:
-${JSON.stringify(health, null, 2)}
-
-Please provide:
-1. Potential root causes
-2. Diagnostic steps
-3. Resolution strategies
-4. Prevention recommendations`,
-          },
+          content: { type: 'text', text: prompt.text },
         },
       ],
     };
@@ -932,7 +941,9 @@ Please provide:
     try {
       await this.projectManager.initialize();
       await this.statusMonitor.initialize();
+      await this.privacyGuard.initialize();
       await this.aiOrchestrator.initialize();
+      await this.promptTemplates.initialize();
 
       const transport = new StdioServerTransport();
       await this.server.connect(transport);
@@ -957,6 +968,8 @@ Please provide:
       await this.server.close();
       await this.statusMonitor.stop();
       await this.aiOrchestrator.stop();
+      await this.promptTemplates.stop();
+      await this.privacyGuard.stop();
       this.logger.info('MCP Server stopped successfully');
     } catch (error) {
       this.logger.error('Error stopping MCP server:', error);
@@ -0,0 +1,227 @@
+/**
+ * Index Maintenance - Health, verification and re-indexing for the
+ * learning memory index
+ * The memory bank is the source of truth; the index can drift from it when
+ * an index write fails, so this reports the lag, repairs it, and rebuilds
+ * the index as a background job without blocking learning cycles
+ */
+
+import { EventEmitter } from 'events';
+import { createMemoryIndex } from './MemoryIndex.js';
+
+export const REINDEX_SCOPES = ['full', 'since'];
+
+function sameVector(a, b) {
+  return a.length === b.length && a.every((value, i) => value === b[i]);
+}
+
+// Yield to the event loop between chunks
+const nextTick = () => new Promise((resolve) => setImmediate(resolve));
+
+export class IndexMaintenance extends EventEmitter {
+  constructor(learningAlgorithm, config = {}) {
+    super();
+    this.learning = learningAlgorithm;
+    this.config = {
+      indexConfig: config,
+      chunkSize: config.reindexChunkSize || 500,
+      jobHistory: config.reindexJobHistory || 20,
+    };
+
+    this.jobs = new Map();
+    this.activeJob = null;
+    this.lastVerifiedAt = null;
+    this.lastRebuiltAt = null;
+  }
+
+  /**
+   * Compare the memory bank with the index. Missing entries are pending,
+   * orphaned ones no longer exist in the memory bank, and stale ones were
+   * indexed with different features.
+   */
+  diff({ includeStale = false } = {}) {
+    const source = this.learning.model.memoryBank;
+    const index = this.learning.memoryIndex;
+
+    const missing = [];
+    const stale = [];
+    source.forEach((experience, key) => {
+      const vector = index.get(key);
+      if (!vector) {
+        missing.push(key);
+      } else if (includeStale && !sameVector(vector, experience.features)) {
+        stale.push(key);
+      }
+    });
+
+    const orphaned = Array.from(index.keys()).filter((key) => !source.has(key));
+
+    return { missing, orphaned, stale };
+  }
+
+  /**
+   * Index lag and counts; cheap enough for health checks
+   */
+  getHealth() {
+    const { missing, orphaned } = this.diff();
+
+    return {
+      status: missing.length + orphaned.length > 0 ? 'degraded' : 'healthy',
+      strategy: this.learning.memoryIndex.strategy,
+      sourceCount: this.learning.model.memoryBank.size,
+      indexedCount: this.learning.memoryIndex.size,
+      pending: missing.length,
+      orphaned: orphaned.length,
+      indexFailures: this.learning.indexFailures,
+      lastVerifiedAt: this.lastVerifiedAt,
+      lastRebuiltAt: this.lastRebuiltAt,
+      activeJob: this.activeJob?.id || null,
+    };
+  }
+
+  /**
+   * Verify index against source, optionally repairing the differences
+   */
+  verify({ repair = false, sampleSize = 20 } = {}) {
+    const { missing, orphaned, stale } = this.diff({ includeStale: true });
+    const source = this.learning.model.memoryBank;
+    const index = this.learning.memoryIndex;
+
+    if (repair) {
+      [...missing, ...stale].forEach((key) =>
+        index.add(key, source.get(key).features)
+      );
+      orphaned.forEach((key) => index.remove(key));
+    }
+
+    this.lastVerifiedAt = Date.now();
+    const report = {
+      consistent: missing.length + orphaned.length + stale.length === 0,
+      sourceCount: source.size,
+      indexedCount: index.size,
+      missing: { count: missing.length, sample: missing.slice(0, sampleSize) },
+      orphaned: {
+        count: orphaned.length,
+        sample: orphaned.slice(0, sampleSize),
+      },
+      stale: { count: stale.length, sample: stale.slice(0, sampleSize) },
+      repaired: repair,
+      verifiedAt: this.lastVerifiedAt,
+    };
+
+    this.emit('index:verified', report);
+    return report;
+  }
+
+  /**
+   * Start a background re-index. `full` builds a fresh index beside the
+   * live one and swaps it in; `since` re-adds experiences recorded at or
+   * after the given timestamp into the live index.
+   */
+  startReindex({ scope = 'full', since = null } = {}) {
+    if (!REINDEX_SCOPES.includes(scope)) {
+      throw new Error(
+        `Unknown reindex scope: ${scope} (use ${REINDEX_SCOPES.join(', ')})`
+      );
+    }
+    if (
+      scope === 'since' &&
+      (since === null || !Number.isFinite(Number(since)))
+    ) {
+      throw new Error('A since timestamp is required for scoped reindexing');
+    }
+    if (this.activeJob) {
+      throw new Error(`Reindex job ${this.activeJob.id} is already running`);
+    }
+
+    const job = {
+      id: `reindex_${Date.now()}`,
+      scope,
+      since: scope === 'since' ? Number(since) : null,
+      status: 'running',
+      total: 0,
+      processed: 0,
+      startedAt: Date.now(),
+      finishedAt: null,
+      error: null,
+    };
+
+    this.jobs.set(job.id, job);
+    this.activeJob = job;
+    this.trimJobs();
+
+    this.runJob(job)
+      .then(
+        () => {
+          job.status = 'completed';
+        },
+        (error) => {
+          job.status = 'failed';
+          job.error = error.message;
+          console.error(`Reindex job ${job.id} failed:`, error.message);
+        }
+      )
+      .then(() => {
+        job.finishedAt = Date.now();
+        this.activeJob = null;
+        this.emit(`reindex:${job.status}`, { ...job });
+      });
+
+    return { ...job };
+  }
+
+  async runJob(job) {
+    const source = this.learning.model.memoryBank;
+    const entries = Array.from(source.entries()).filter(
+      ([, experience]) =>
+        job.scope === 'full' || experience.timestamp >= job.since
+    );
+    job.total = entries.length;
+
+    const target =
+      job.scope === 'full'
+        ? createMemoryIndex(this.config.indexConfig)
+        : this.learning.memoryIndex;
+
+    for (let i = 0; i < entries.length; i += this.config.chunkSize) {
+      entries
+        .slice(i, i + this.config.chunkSize)
+        .forEach(([key, experience]) => target.add(key, experience.features));
+      job.processed = Math.min(i + this.config.chunkSize, entries.length);
+      await nextTick();
+    }
+
+    if (job.scope === 'full') {
+      // Catch up with writes and cleanups that happened while building
+      source.forEach((experience, key) => {
+        if (!target.get(key)) target.add(key, experience.features);
+      });
+      Array.from(target.keys())
+        .filter((key) => !source.has(key))
+        .forEach((key) => target.remove(key));
+
+      this.learning.memoryIndex = target;
+      this.lastRebuiltAt = Date.now();
+    }
+  }
+
+  getJob(id) {
+    const job = this.jobs.get(id);
+    return job ? { ...job } : null;
+  }
+
+  listJobs() {
+    return Array.from(this.jobs.values())
+      .map((job) => ({ ...job }))
+      .reverse();
+  }
+
+  trimJobs() {
+    const finished = Array.from(this.jobs.values()).filter(
+      (job) => job !== this.activeJob
+    );
+    finished
+      .slice(0, Math.max(0, this.jobs.size - this.config.jobHistory))
+      .forEach((job) => this.jobs.delete(job.id));
+  }
+}
@@ -3,6 +3,17 @@
  * Implements dormant-to-active learning with pattern recognition and adaptation
  */
 
+import { createMemoryIndex } from './MemoryIndex.js';
+
+// Bump when the exported checkpoint layout changes
+export const CHECKPOINT_VERSION = 2;
+
+// Maps survive JSON as entry arrays; older checkpoints wrote them as {}
+const toMap = (value) =>
+  value instanceof Map
+    ? value
+    : new Map(Array.isArray(value) ? value : Object.entries(value || {}));
+
 export class LearningAlgorithm {
   constructor(config = {}) {
     this.config = {
@@ -10,6 +21,7 @@ export class LearningAlgorithm {
       memoryDecay: config.memoryDecay || 0.95,
       adaptationThreshold: config.adaptationThreshold || 0.8,
       maxMemorySize: config.maxMemorySize || 10000,
+      anomalyNeighbors: config.anomalyNeighbors || 10,
       ...config,
     };
 
@@ -31,6 +43,10 @@ export class LearningAlgorithm {
     };
 
     this.neuralNetwork = this.initializeNeuralNetwork();
+
+    // Kept beside the model rather than in it so it is never persisted
+    this.memoryIndex = createMemoryIndex(this.config);
+    this.indexFailures = 0;
   }
 
   initializeNeuralNetwork() {
@@ -53,7 +69,7 @@ export class LearningAlgorithm {
     return Array.from({ length: size }, () => (Math.random() - 0.5) * 2);
   }
 
-  async processPassiveSignals(signals) {
+  async processPassiveSignals(signals, options = {}) {
     // Convert signals to feature vectors
     const features = this.extractFeatures(signals);
 
@@ -61,7 +77,7 @@ export class LearningAlgorithm {
     this.updateMemoryBank(features, signals.timestamp);
 
     // Perform unsupervised learning
-    await this.unsupervisedLearning(features);
+    await this.unsupervisedLearning(features, options);
 
     // Update learning state
     this.updateLearningState(features);
@@ -139,6 +155,13 @@ export class LearningAlgorithm {
     };
 
     this.model.memoryBank.set(memoryKey, experience);
+    try {
+      this.memoryIndex.add(memoryKey, features);
+    } catch (error) {
+      // The experience is kept; IndexMaintenance reports it as pending
+      this.indexFailures++;
+      console.error('Failed to index memory:', error.message);
+    }
 
     // Memory cleanup if needed
     if (this.model.memoryBank.size > this.config.maxMemorySize) {
@@ -192,21 +215,42 @@ export class LearningAlgorithm {
     return (highValueFeatures + lowValueFeatures) / features.length;
   }
 
+  /**
+   * Nearest memories to a feature vector, closest first
+   */
+  searchMemory(features, k = 10) {
+    return this.memoryIndex.query(features, k).map(({ key, distance }) => ({
+      key,
+      distance,
+      experience: this.model.memoryBank.get(key),
+    }));
+  }
+
+  rebuildMemoryIndex() {
+    this.memoryIndex.clear();
+    this.model.memoryBank.forEach((experience, key) => {
+      this.memoryIndex.add(key, experience.features);
+    });
+  }
+
   euclideanDistance(a, b) {
     return Math.sqrt(
       a.reduce((acc, val, i) => acc + Math.pow(val - b[i], 2), 0)
     );
   }
 
-  async unsupervisedLearning(features) {
+  async unsupervisedLearning(features, options = {}) {
     // K-means clustering for pattern discovery
     await this.performClustering(features);
 
-    // Autoencoder-style learning for feature compression
-    await this.performAutoencoding(features);
+    // The costlier passes are skipped while the throttle is paused
+    if (options.lowPriority !== false) {
+      // Autoencoder-style learning for feature compression
+      await this.performAutoencoding(features);
 
-    // Temporal pattern learning
-    await this.performTemporalLearning(features);
+      // Temporal pattern learning
+      await this.performTemporalLearning(features);
+    }
 
     // Anomaly detection
     await this.performAnomalyDetection(features);
@@ -397,17 +441,17 @@ export class LearningAlgorithm {
       recentAnomalies: [],
     };
 
-    // Compare with normal patterns
-    const normalPatterns = Array.from(this.model.memoryBank.values())
-      .filter((exp) => exp.importance < 0.7)
-      .map((exp) => exp.features);
+    // Compare with the nearest normal patterns; over-fetch since some
+    // neighbors will be too important to count as normal
+    const k = this.config.anomalyNeighbors;
+    const normalNeighbors = this.searchMemory(features, k * 3)
+      .filter((match) => match.experience.importance < 0.7)
+      .slice(0, k);
 
-    if (normalPatterns.length > 0) {
+    if (normalNeighbors.length > 0) {
       const avgDistance =
-        normalPatterns.reduce(
-          (acc, pattern) => acc + this.euclideanDistance(features, pattern),
-          0
-        ) / normalPatterns.length;
+        normalNeighbors.reduce((acc, match) => acc + match.distance, 0) /
+        normalNeighbors.length;
 
       if (avgDistance > anomalies.threshold) {
         anomalies.detectedCount++;
@@ -497,6 +541,7 @@ export class LearningAlgorithm {
     toKeep.forEach(([key, value]) => {
       this.model.memoryBank.set(key, value);
     });
+    this.rebuildMemoryIndex();
   }
 
   async updateModel(suggestions) {
@@ -671,16 +716,61 @@ export class LearningAlgorithm {
     return recommendations;
   }
 
+  /**
+   * Restore a checkpoint written by exportData(). Returns false when there
+   * is nothing to restore, leaving the fresh model in place.
+   */
   loadData(data) {
-    if (data) {
-      this.model = { ...this.model, ...data.model };
-      this.learningState = { ...this.learningState, ...data.learningState };
+    if (!data?.model) {
+      return false;
     }
+
+    const connections = toMap(data.model.neuralConnections);
+    const temporal = connections.get('temporal');
+    if (temporal) {
+      connections.set('temporal', {
+        ...temporal,
+        patterns: toMap(temporal.patterns),
+      });
+    }
+
+    this.model = {
+      weights: toMap(data.model.weights),
+      biases: toMap(data.model.biases),
+      neuralConnections: connections,
+      memoryBank: toMap(data.model.memoryBank),
+      experienceBuffer: data.model.experienceBuffer || [],
+    };
+    this.learningState = { ...this.learningState, ...data.learningState };
+    if (data.neuralNetwork?.inputLayer) {
+      this.neuralNetwork = data.neuralNetwork;
+    }
+
+    this.rebuildMemoryIndex();
+    return true;
   }
 
+  // JSON-safe checkpoint of everything learned: network weights, memory
+  // bank, clusters and their centroids, and temporal patterns
   async exportData() {
+    const connections = Array.from(
+      this.model.neuralConnections.entries(),
+      ([key, value]) =>
+        key === 'temporal'
+          ? [key, { ...value, patterns: Array.from(value.patterns.entries()) }]
+          : [key, value]
+    );
+
     return {
-      model: this.model,
+      version: CHECKPOINT_VERSION,
+      model: {
+        weights: Array.from(this.model.weights.entries()),
+        biases: Array.from(this.model.biases.entries()),
+        neuralConnections: connections,
+        memoryBank: Array.from(this.model.memoryBank.entries()),
+        experienceBuffer: this.model.experienceBuffer,
+      },
+      neuralNetwork: this.neuralNetwork,
       learningState: this.learningState,
       config: this.config,
       timestamp: Date.now(),
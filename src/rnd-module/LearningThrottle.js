@@ -0,0 +1,144 @@
+/**
+ * Learning Throttle - Self-protection for the R&D learning cycles
+ * Backs off learning when cycle errors, cycle latency, or host load spike,
+ * and steps back up with hysteresis once they settle
+ */
+
+import { EventEmitter } from 'events';
+import os from 'os';
+
+export const THROTTLE_LEVELS = ['normal', 'throttled', 'paused'];
+
+// Cycles run every Nth tick and batches shrink by the same factor
+const LEVEL_MULTIPLIERS = [1, 2, 4];
+
+export class LearningThrottle extends EventEmitter {
+  constructor(config = {}) {
+    super();
+    this.config = {
+      windowSize: config.throttleWindowSize || 20, // cycles considered
+      errorRateThreshold: config.throttleErrorRate || 0.2,
+      processingTimeThreshold: config.throttleProcessingTime || 5000, // ms
+      loadThreshold: config.throttleLoad || 0.85, // 1-min load per CPU
+      memoryThreshold: config.throttleMemory || 0.9, // fraction of RAM used
+      recoveryRatio: config.throttleRecoveryRatio || 0.5,
+      recoveryCycles: config.throttleRecoveryCycles || 3,
+    };
+
+    this.level = 0;
+    this.samples = [];
+    this.calmStreak = 0;
+    this.tick = 0;
+    this.lastChange = null;
+  }
+
+  /**
+   * Record the outcome of one learning cycle and re-evaluate the level
+   */
+  record(durationMs, error = null) {
+    this.samples.push({ durationMs, failed: Boolean(error) });
+    if (this.samples.length > this.config.windowSize) {
+      this.samples.shift();
+    }
+
+    return this.evaluate();
+  }
+
+  getMetrics() {
+    const count = this.samples.length;
+    const cpus = os.cpus().length || 1;
+
+    return {
+      errorRate: count
+        ? this.samples.filter((s) => s.failed).length / count
+        : 0,
+      processingTime: count
+        ? this.samples.reduce((acc, s) => acc + s.durationMs, 0) / count
+        : 0,
+      load: os.loadavg()[0] / cpus,
+      memory: 1 - os.freemem() / os.totalmem(),
+    };
+  }
+
+  /**
+   * Ratio of each metric to its threshold; >= 1 means breached
+   */
+  getPressure(metrics) {
+    return {
+      errorRate: metrics.errorRate / this.config.errorRateThreshold,
+      processingTime:
+        metrics.processingTime / this.config.processingTimeThreshold,
+      load: metrics.load / this.config.loadThreshold,
+      memory: metrics.memory / this.config.memoryThreshold,
+    };
+  }
+
+  evaluate(metrics = this.getMetrics()) {
+    const pressure = this.getPressure(metrics);
+    const breached = Object.keys(pressure).filter((key) => pressure[key] >= 1);
+    const calm = Object.values(pressure).every(
+      (value) => value < this.config.recoveryRatio
+    );
+
+    if (breached.length > 0) {
+      this.calmStreak = 0;
+      if (this.level < THROTTLE_LEVELS.length - 1) {
+        const reason = `breached: ${breached.join(', ')}`;
+        this.setLevel(this.level + 1, reason, metrics);
+      }
+    } else if (calm && this.level > 0) {
+      // Step down one level only after a run of calm evaluations
+      this.calmStreak++;
+      if (this.calmStreak >= this.config.recoveryCycles) {
+        this.calmStreak = 0;
+        this.setLevel(this.level - 1, 'recovered', metrics);
+      }
+    } else {
+      this.calmStreak = 0;
+    }
+
+    return this.getState();
+  }
+
+  setLevel(level, reason, metrics) {
+    const from = THROTTLE_LEVELS[this.level];
+    this.level = level;
+    this.lastChange = {
+      from,
+      to: THROTTLE_LEVELS[level],
+      reason,
+      metrics,
+      timestamp: Date.now(),
+    };
+
+    console.log(
+      `🚦 R&D learning throttle ${from} -> ${this.lastChange.to} (${reason})`
+    );
+    this.emit('throttle:changed', this.lastChange);
+  }
+
+  /**
+   * Whether a periodic cycle should run on this tick
+   */
+  shouldRunCycle() {
+    return this.tick++ % LEVEL_MULTIPLIERS[this.level] === 0;
+  }
+
+  scaleBatch(size) {
+    return Math.max(1, Math.floor(size / LEVEL_MULTIPLIERS[this.level]));
+  }
+
+  allowLowPriority() {
+    return THROTTLE_LEVELS[this.level] !== 'paused';
+  }
+
+  getState() {
+    return {
+      level: THROTTLE_LEVELS[this.level],
+      intervalMultiplier: LEVEL_MULTIPLIERS[this.level],
+      lowPriorityPaused: !this.allowLowPriority(),
+      metrics: this.getMetrics(),
+      lastChange: this.lastChange,
+    };
+  }
+}
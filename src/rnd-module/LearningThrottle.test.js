@@ -0,0 +1,98 @@
+/**
+ * Tests for Learning Throttle
+ */
+
+import { LearningThrottle } from './LearningThrottle.js';
+import { jest } from '@jest/globals';
+
+describe('LearningThrottle', () => {
+  let throttle;
+  let consoleLog;
+
+  const calm = { errorRate: 0, processingTime: 100, load: 0.1, memory: 0.1 };
+  const busy = { ...calm, load: 0.95 };
+
+  beforeEach(() => {
+    consoleLog = jest.spyOn(console, 'log').mockImplementation(() => {});
+    throttle = new LearningThrottle({ throttleRecoveryCycles: 2 });
+  });
+
+  afterEach(() => {
+    consoleLog.mockRestore();
+  });
+
+  describe('evaluate', () => {
+    it('should step up one level per breached evaluation', () => {
+      const changes = jest.fn();
+      throttle.on('throttle:changed', changes);
+
+      expect(throttle.evaluate(busy).level).toBe('throttled');
+      expect(throttle.evaluate(busy).level).toBe('paused');
+      expect(throttle.evaluate(busy).level).toBe('paused');
+
+      expect(changes).toHaveBeenCalledTimes(2);
+      expect(throttle.lastChange).toMatchObject({
+        from: 'throttled',
+        to: 'paused',
+        reason: 'breached: load',
+      });
+    });
+
+    it('should recover only after consecutive calm evaluations', () => {
+      throttle.evaluate(busy);
+
+      expect(throttle.evaluate(calm).level).toBe('throttled');
+      // Below the threshold but above the recovery ratio resets the streak
+      throttle.evaluate({ ...calm, load: 0.6 });
+      expect(throttle.evaluate(calm).level).toBe('throttled');
+      expect(throttle.evaluate(calm).level).toBe('normal');
+      expect(throttle.lastChange.reason).toBe('recovered');
+    });
+
+    it('should name every breached metric', () => {
+      throttle.evaluate({ ...busy, errorRate: 0.5, memory: 0.95 });
+
+      expect(throttle.lastChange.reason).toBe(
+        'breached: errorRate, load, memory'
+      );
+    });
+  });
+
+  describe('record', () => {
+    it('should track error rate and latency over the window', () => {
+      throttle = new LearningThrottle({ throttleWindowSize: 4 });
+
+      throttle.record(1000, new Error('boom'));
+      for (let i = 0; i < 4; i++) {
+        throttle.record(200);
+      }
+
+      const metrics = throttle.getMetrics();
+      expect(throttle.samples).toHaveLength(4);
+      expect(metrics.errorRate).toBe(0);
+      expect(metrics.processingTime).toBe(200);
+    });
+  });
+
+  describe('scheduling', () => {
+    it('should stretch intervals and shrink batches by level', () => {
+      const runs = () =>
+        Array.from({ length: 8 }, () => throttle.shouldRunCycle()).filter(
+          Boolean
+        ).length;
+
+      expect(runs()).toBe(8);
+      expect(throttle.scaleBatch(10)).toBe(10);
+
+      throttle.evaluate(busy);
+      expect(runs()).toBe(4);
+      expect(throttle.scaleBatch(10)).toBe(5);
+      expect(throttle.allowLowPriority()).toBe(true);
+
+      throttle.evaluate(busy);
+      expect(runs()).toBe(2);
+      expect(throttle.scaleBatch(3)).toBe(1);
+      expect(throttle.getState().lowPriorityPaused).toBe(true);
+    });
+  });
+});
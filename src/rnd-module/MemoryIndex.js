@@ -0,0 +1,217 @@
+/**
+ * Memory Index - Nearest-neighbor search over memory bank feature vectors
+ * Exact linear scan for small banks, locality-sensitive hashing for large ones
+ */
+
+export const MEMORY_INDEX_STRATEGIES = ['auto', 'exact', 'lsh'];
+
+function euclideanDistance(a, b) {
+  let sum = 0;
+  for (let i = 0; i < a.length; i++) {
+    const diff = a[i] - (b[i] || 0);
+    sum += diff * diff;
+  }
+  return Math.sqrt(sum);
+}
+
+// Standard normal sample (Box-Muller)
+function gaussian() {
+  const u = 1 - Math.random();
+  const v = Math.random();
+  return Math.sqrt(-2 * Math.log(u)) * Math.cos(2 * Math.PI * v);
+}
+
+function nearest(candidates, query, k) {
+  return candidates
+    .map(([key, vector]) => ({
+      key,
+      distance: euclideanDistance(query, vector),
+    }))
+    .sort((a, b) => a.distance - b.distance)
+    .slice(0, k);
+}
+
+/**
+ * Brute-force index; every query scans all vectors
+ */
+export class ExactIndex {
+  constructor() {
+    this.strategy = 'exact';
+    this.vectors = new Map();
+  }
+
+  get size() {
+    return this.vectors.size;
+  }
+
+  add(key, vector) {
+    this.vectors.set(key, vector);
+  }
+
+  keys() {
+    return this.vectors.keys();
+  }
+
+  get(key) {
+    return this.vectors.get(key);
+  }
+
+  remove(key) {
+    this.vectors.delete(key);
+  }
+
+  clear() {
+    this.vectors.clear();
+  }
+
+  query(vector, k = 10) {
+    return nearest(Array.from(this.vectors.entries()), vector, k);
+  }
+}
+
+/**
+ * p-stable LSH for Euclidean distance. Each table hashes a vector with
+ * `hashes` random projections floor((a.v + b) / w); a query only scores
+ * vectors sharing a bucket with it in at least one table.
+ */
+export class LSHIndex {
+  constructor(options = {}) {
+    this.strategy = 'lsh';
+    this.options = {
+      tables: options.tables || 8,
+      hashes: options.hashes || 4,
+      bucketWidth: options.bucketWidth || 1.0,
+    };
+
+    this.vectors = new Map();
+    this.buckets = [];
+    this.projections = null; // created on first add, once dimensions known
+  }
+
+  get size() {
+    return this.vectors.size;
+  }
+
+  initializeProjections(dimensions) {
+    const { tables, hashes, bucketWidth } = this.options;
+
+    this.projections = Array.from({ length: tables }, () =>
+      Array.from({ length: hashes }, () => ({
+        a: Array.from({ length: dimensions }, gaussian),
+        b: Math.random() * bucketWidth,
+      }))
+    );
+    this.buckets = Array.from({ length: tables }, () => new Map());
+  }
+
+  bucketKeys(vector) {
+    const width = this.options.bucketWidth;
+
+    return this.projections.map((table) =>
+      table
+        .map(({ a, b }) => {
+          let dot = b;
+          for (let i = 0; i < a.length; i++) {
+            dot += a[i] * (vector[i] || 0);
+          }
+          return Math.floor(dot / width);
+        })
+        .join(',')
+    );
+  }
+
+  add(key, vector) {
+    if (!this.projections) {
+      this.initializeProjections(vector.length);
+    }
+    if (this.vectors.has(key)) {
+      this.remove(key);
+    }
+
+    const bucketKeys = this.bucketKeys(vector);
+    bucketKeys.forEach((bucketKey, table) => {
+      let bucket = this.buckets[table].get(bucketKey);
+      if (!bucket) {
+        bucket = new Set();
+        this.buckets[table].set(bucketKey, bucket);
+      }
+      bucket.add(key);
+    });
+
+    this.vectors.set(key, { vector, bucketKeys });
+  }
+
+  keys() {
+    return this.vectors.keys();
+  }
+
+  get(key) {
+    return this.vectors.get(key)?.vector;
+  }
+
+  remove(key) {
+    const entry = this.vectors.get(key);
+    if (!entry) return;
+
+    entry.bucketKeys.forEach((bucketKey, table) => {
+      const bucket = this.buckets[table].get(bucketKey);
+      bucket.delete(key);
+      if (bucket.size === 0) {
+        this.buckets[table].delete(bucketKey);
+      }
+    });
+    this.vectors.delete(key);
+  }
+
+  clear() {
+    this.vectors.clear();
+    this.buckets.forEach((table) => table.clear());
+  }
+
+  query(vector, k = 10) {
+    if (!this.projections) return [];
+
+    const candidates = new Set();
+    this.bucketKeys(vector).forEach((bucketKey, table) => {
+      const bucket = this.buckets[table].get(bucketKey);
+      if (bucket) {
+        bucket.forEach((key) => candidates.add(key));
+      }
+    });
+
+    // A vector far from everything lands in empty buckets; those are rare
+    // (they are the anomalies), so pay for a full scan rather than return
+    // nothing
+    const keys = candidates.size > 0 ? candidates : this.vectors.keys();
+
+    return nearest(
+      Array.from(keys, (key) => [key, this.vectors.get(key).vector]),
+      vector,
+      k
+    );
+  }
+}
+
+/**
+ * Pick an index for the configured memory capacity. `auto` uses the exact
+ * scan until maxMemorySize reaches memoryIndexThreshold.
+ */
+export function createMemoryIndex(config = {}) {
+  const strategy = config.memoryIndex || 'auto';
+  if (!MEMORY_INDEX_STRATEGIES.includes(strategy)) {
+    throw new Error(`Unknown memory index strategy: ${strategy}`);
+  }
+
+  const useLSH =
+    strategy === 'lsh' ||
+    (strategy === 'auto' &&
+      (config.maxMemorySize || 0) >= (config.memoryIndexThreshold || 50000));
+
+  return useLSH
+    ? new LSHIndex({
+        tables: config.lshTables,
+        hashes: config.lshHashes,
+        bucketWidth: config.lshBucketWidth,
+      })
+    : new ExactIndex();
+}
@@ -0,0 +1,104 @@
+/**
+ * Tests for Memory Index
+ */
+
+import { ExactIndex, LSHIndex, createMemoryIndex } from './MemoryIndex.js';
+
+describe('MemoryIndex', () => {
+  const points = [
+    ['a', [0, 0, 0]],
+    ['b', [0.1, 0, 0]],
+    ['c', [5, 5, 5]],
+    ['d', [5, 5.2, 5]],
+  ];
+
+  const build = (index) => {
+    points.forEach(([key, vector]) => index.add(key, vector));
+    return index;
+  };
+
+  describe('ExactIndex', () => {
+    it('should return the k nearest keys in order', () => {
+      const index = build(new ExactIndex());
+
+      const results = index.query([0.09, 0, 0], 2);
+
+      expect(results.map((result) => result.key)).toEqual(['b', 'a']);
+      expect(results[0].distance).toBeCloseTo(0.01);
+    });
+
+    it('should forget removed vectors', () => {
+      const index = build(new ExactIndex());
+
+      index.remove('a');
+
+      expect(index.size).toBe(3);
+      expect(index.query([0, 0, 0], 1)[0].key).toBe('b');
+    });
+  });
+
+  describe('LSHIndex', () => {
+    it('should find an exact match first', () => {
+      const index = build(new LSHIndex({ bucketWidth: 4 }));
+
+      for (const [key, vector] of points) {
+        expect(index.query(vector, 1)[0]).toEqual({ key, distance: 0 });
+      }
+    });
+
+    it('should replace a vector re-added under the same key', () => {
+      const index = build(new LSHIndex());
+
+      index.add('a', [9, 9, 9]);
+
+      expect(index.size).toBe(4);
+      expect(index.get('a')).toEqual([9, 9, 9]);
+      expect(index.query([9, 9, 9], 1)[0].key).toBe('a');
+    });
+
+    it('should drop empty buckets on remove', () => {
+      const index = new LSHIndex({ tables: 2 });
+      index.add('a', [1, 2, 3]);
+
+      index.remove('a');
+      index.remove('missing');
+
+      expect(index.size).toBe(0);
+      expect(index.buckets.every((table) => table.size === 0)).toBe(true);
+    });
+
+    it('should fall back to a full scan for isolated queries', () => {
+      const index = build(new LSHIndex({ bucketWidth: 0.01 }));
+
+      const results = index.query([1000, -1000, 1000], 4);
+
+      expect(results).toHaveLength(4);
+    });
+
+    it('should return nothing before the first add', () => {
+      expect(new LSHIndex().query([1, 2, 3])).toEqual([]);
+    });
+  });
+
+  describe('createMemoryIndex', () => {
+    it('should switch to LSH once capacity reaches the threshold', () => {
+      const strategy = (config) => createMemoryIndex(config).strategy;
+
+      expect(strategy({ maxMemorySize: 1000 })).toBe('exact');
+      expect(strategy({ maxMemorySize: 50000 })).toBe('lsh');
+      expect(
+        strategy({ maxMemorySize: 100, memoryIndexThreshold: 100 })
+      ).toBe('lsh');
+      expect(strategy({ memoryIndex: 'exact', maxMemorySize: 1e6 })).toBe(
+        'exact'
+      );
+      expect(strategy({ memoryIndex: 'lsh' })).toBe('lsh');
+    });
+
+    it('should reject unknown strategies', () => {
+      expect(() => createMemoryIndex({ memoryIndex: 'faiss' })).toThrow(
+        'Unknown memory index strategy: faiss'
+      );
+    });
+  });
+});
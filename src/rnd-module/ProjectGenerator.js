@@ -35,6 +35,7 @@ export class ProjectGenerator {
     this.scoringEngine = new ProjectScoringEngine(this.config);
     this.diversityEngine = new DiversityEngine(this.config);
     this.feasibilityAnalyzer = new FeasibilityAnalyzer(this.config);
+    this.taskBreakdownGenerator = new TaskBreakdownGenerator(this.config);
   }
 
   async generate(options = {}) {
@@ -64,10 +65,11 @@ export class ProjectGenerator {
       diversifiedSuggestions
     );
 
-    // Select top suggestions
+    // Select top suggestions and attach an initial task breakdown
     const finalSuggestions = feasibleSuggestions
       .filter((s) => s.score > this.config.minConfidence)
-      .slice(0, limit);
+      .slice(0, limit)
+      .map((s) => ({ ...s, tasks: this.taskBreakdownGenerator.generate(s) }));
 
     console.log(`✅ Generated ${finalSuggestions.length} project suggestions`);
 
@@ -954,6 +956,75 @@ class ResearchProjectGenerator {
   }
 }
 
+// Default phase templates per project type; override via config.taskTemplates
+const DEFAULT_TASK_TEMPLATES = {
+  default: [
+    { phase: 'research', title: 'Research: {{title}}', share: 0.2 },
+    { phase: 'design', title: 'Design solution for {{title}}', share: 0.2 },
+    { phase: 'implement', title: 'Implement {{title}}', share: 0.45 },
+    { phase: 'evaluate', title: 'Evaluate {{title}} results', share: 0.15 },
+  ],
+  research: [
+    { phase: 'research', title: 'Literature review: {{title}}', share: 0.35 },
+    { phase: 'design', title: 'Design experiments for {{title}}', share: 0.2 },
+    { phase: 'implement', title: 'Run {{title}} experiments', share: 0.25 },
+    { phase: 'evaluate', title: 'Report {{title}} findings', share: 0.2 },
+  ],
+  maintenance: [
+    {
+      phase: 'research',
+      title: 'Audit current state for {{title}}',
+      share: 0.25,
+    },
+    { phase: 'implement', title: 'Apply {{title}} updates', share: 0.5 },
+    { phase: 'evaluate', title: 'Verify {{title}} outcome', share: 0.25 },
+  ],
+};
+
+// Agent capability suggested for each phase
+const PHASE_CAPABILITIES = {
+  research: 'researcher',
+  design: 'architect',
+  implement: 'coder',
+  evaluate: 'tester',
+};
+
+class TaskBreakdownGenerator {
+  constructor(config) {
+    this.config = config;
+    this.templates = {
+      ...DEFAULT_TASK_TEMPLATES,
+      ...(config.taskTemplates || {}),
+    };
+  }
+
+  generate(suggestion) {
+    const phases = this.templates[suggestion.type] || this.templates.default;
+    const effort = suggestion.estimatedEffort || 40;
+
+    return phases.map((template, index) => ({
+      id: `${suggestion.id}_task_${index + 1}`,
+      title: template.title.replace(/{{title}}/g, suggestion.title),
+      phase: template.phase,
+      order: index + 1,
+      dependsOn: index > 0 ? [`${suggestion.id}_task_${index}`] : [],
+      estimatedHours: Math.max(1, Math.round(effort * template.share)),
+      suggestedAgent: this.suggestAgent(template.phase, suggestion),
+      status: 'pending_review',
+      autoGenerated: true,
+    }));
+  }
+
+  suggestAgent(phase, suggestion) {
+    // Research outcomes are analysed rather than tested
+    if (phase === 'evaluate' && suggestion.type === 'research') {
+      return 'analyzer';
+    }
+
+    return PHASE_CAPABILITIES[phase] || 'coder';
+  }
+}
+
 class ProjectScoringEngine {
   constructor(config) {
     this.config = config;
@@ -152,6 +152,7 @@ export class ProjectIntegration {
       technologies: suggestion.technologies,
       requirements: suggestion.requirements,
       deliverables: suggestion.deliverables,
+      tasks: suggestion.tasks || [],
       successMetrics: suggestion.success_metrics,
       estimatedEffort: suggestion.estimatedEffort,
       requiredSkills: suggestion.requiredSkills,
@@ -793,6 +794,9 @@ ${projectData.requirements.map((req) => `- [ ] ${req}`).join('\n')}
 ## Deliverables
 ${projectData.deliverables.map((del) => `- [ ] ${del}`).join('\n')}
 
+## Suggested Tasks (auto-generated, pending review)
+${projectData.tasks.map((task) => `- [ ] ${task.title} (${task.suggestedAgent}, ~${task.estimatedHours}h)`).join('\n')}
+
 ## Technical Details
 - **Technologies:** ${projectData.technologies.join(', ')}
 - **Estimated Effort:** ${projectData.estimatedEffort} hours
@@ -8,6 +8,7 @@ import { PatternRecognition } from './PatternRecognition.js';
 import { ProjectGenerator } from './ProjectGenerator.js';
 import { ProjectIntegration } from './ProjectIntegration.js';
 import { RnDDataStore } from './RnDDataStore.js';
+import { LearningThrottle } from './LearningThrottle.js';
 
 export class RnDCoordinator {
   constructor(config = {}) {
@@ -16,6 +17,7 @@ export class RnDCoordinator {
       learningThreshold: config.learningThreshold || 0.7,
       activationTriggers: config.activationTriggers || 5,
       maxProjectSuggestions: config.maxProjectSuggestions || 3,
+      checkpointInterval: config.checkpointInterval || 10 * 60 * 1000,
       ...config,
     };
 
@@ -37,6 +39,19 @@ export class RnDCoordinator {
       dataStore: new RnDDataStore(this.config),
     };
 
+    this.throttle = new LearningThrottle(this.config);
+
+    // Optional PrivacyGuard; set by RnDModule.attachPrivacyGuard()
+    this.privacyGuard = null;
+
+    this.checkpoints = {
+      restoredFrom: null, // timestamp of the checkpoint loaded at startup
+      lastSavedAt: null,
+      lastEpoch: null,
+      failures: 0,
+    };
+    this.checkpointTimer = null;
+
     this.initialize();
   }
 
@@ -48,6 +63,7 @@ export class RnDCoordinator {
 
     // Set up periodic learning cycles
     this.setupLearningCycles();
+    this.setupCheckpointing();
 
     // Initialize pattern recognition
     await this.modules.patternRecognition.initialize();
@@ -60,31 +76,85 @@ export class RnDCoordinator {
 
   async loadPersistentData() {
     try {
+      await this.modules.dataStore.ready;
       const data = await this.modules.dataStore.load();
       if (data) {
-        this.state = { ...this.state, ...data.state };
-        this.modules.learningAlgorithm.loadData(data.learningData);
-        this.modules.patternRecognition.loadPatterns(data.patterns);
+        this.state = {
+          ...this.state,
+          ...data.state,
+          learningData: new Map(
+            Array.isArray(data.state?.learningData)
+              ? data.state.learningData
+              : []
+          ),
+        };
+        this.modules.patternRecognition.loadPatternsFromData(data.patterns);
+
+        const learning = this.modules.learningAlgorithm;
+        if (learning.loadData(data.learningData)) {
+          this.checkpoints.restoredFrom = data.timestamp || null;
+          this.checkpoints.lastEpoch = learning.learningState.epoch;
+          console.log(
+            `📖 Restored learning checkpoint (${learning.model.memoryBank.size} memories, epoch ${learning.learningState.epoch})`
+          );
+        }
       }
     } catch (error) {
       console.log('📊 No existing R&D data found, starting fresh');
     }
   }
 
+  // Saves learned state on a timer so a restart loses at most one interval
+  setupCheckpointing() {
+    if (this.checkpointTimer || !this.config.checkpointInterval) {
+      return;
+    }
+
+    this.checkpointTimer = setInterval(
+      () => this.checkpoint(),
+      this.config.checkpointInterval
+    );
+    this.checkpointTimer.unref?.();
+  }
+
+  /**
+   * Persist learned state if anything was learned since the last
+   * checkpoint, or always with { force: true }
+   */
+  async checkpoint({ force = false } = {}) {
+    const { epoch } = this.modules.learningAlgorithm.learningState;
+    if (!force && epoch === this.checkpoints.lastEpoch) {
+      return { saved: false, epoch };
+    }
+
+    try {
+      await this.persistLearningData();
+      this.checkpoints.lastSavedAt = Date.now();
+      this.checkpoints.lastEpoch = epoch;
+      return { saved: true, epoch, savedAt: this.checkpoints.lastSavedAt };
+    } catch (error) {
+      this.checkpoints.failures++;
+      console.error('Learning checkpoint failed:', error.message);
+      return { saved: false, epoch, error: error.message };
+    }
+  }
+
   setupLearningCycles() {
     // Passive learning cycle (always running)
     setInterval(
       () => {
-        this.passiveLearningCycle();
+        if (this.throttle.shouldRunCycle()) {
+          this.runThrottledCycle(() => this.passiveLearningCycle());
+        }
       },
       5 * 60 * 1000
     ); // Every 5 minutes
 
-    // Active learning cycle (only when active)
+    // Active learning cycle (only when active, and not while paused)
     setInterval(
       () => {
-        if (this.state.mode === 'active') {
-          this.activeLearningCycle();
+        if (this.state.mode === 'active' && this.throttle.allowLowPriority()) {
+          this.runThrottledCycle(() => this.activeLearningCycle());
         }
       },
       30 * 60 * 1000
@@ -99,13 +169,30 @@ export class RnDCoordinator {
     ); // Every hour
   }
 
+  // Times a learning cycle and feeds the outcome to the throttle
+  async runThrottledCycle(cycle) {
+    const start = Date.now();
+    let failure = null;
+
+    try {
+      await cycle();
+    } catch (error) {
+      failure = error;
+      console.error('Learning cycle failed:', error);
+    }
+
+    this.throttle.record(Date.now() - start, failure);
+  }
+
   async passiveLearningCycle() {
     if (this.state.mode === 'dormant') {
       // Collect passive signals
       const signals = await this.collectPassiveSignals();
 
       // Feed to learning algorithm
-      await this.modules.learningAlgorithm.processPassiveSignals(signals);
+      await this.modules.learningAlgorithm.processPassiveSignals(signals, {
+        lowPriority: this.throttle.allowLowPriority(),
+      });
 
       // Update activation score
       this.updateActivationScore(signals);
@@ -131,6 +218,12 @@ export class RnDCoordinator {
   }
 
   async collectPassiveSignals() {
+    if (this.privacyGuard) {
+      // Passive signals are aggregates; recorded for the privacy audit
+      this.privacyGuard.authorize('learning', 'system-metrics');
+      this.privacyGuard.authorize('learning', 'usage-patterns');
+    }
+
     return {
       timestamp: Date.now(),
       systemActivity: await this.getSystemActivity(),
@@ -307,13 +400,16 @@ export class RnDCoordinator {
     return await this.modules.projectGenerator.generate({
       patterns,
       insights: learningInsights,
-      limit: this.config.maxProjectSuggestions,
+      limit: this.throttle.scaleBatch(this.config.maxProjectSuggestions),
     });
   }
 
   async persistLearningData() {
     const data = {
-      state: this.state,
+      state: {
+        ...this.state,
+        learningData: Array.from(this.state.learningData.entries()),
+      },
       learningData: await this.modules.learningAlgorithm.exportData(),
       patterns: await this.modules.patternRecognition.exportPatterns(),
       timestamp: Date.now(),
@@ -331,6 +427,8 @@ export class RnDCoordinator {
       learningData: this.state.learningData.size,
       lastActivity: this.state.lastActivity,
       uptime: Date.now() - this.state.startTime,
+      throttle: this.throttle.getState(),
+      checkpoints: { ...this.checkpoints },
     };
   }
 
@@ -341,6 +439,17 @@ export class RnDCoordinator {
   }
 
   async addUserFeedback(feedback) {
+    // Under a privacy mode feedback is neither stored nor learned from
+    if (
+      this.privacyGuard &&
+      !this.privacyGuard.authorize('learning', 'feedback', {
+        projectId: feedback.projectId,
+      })
+    ) {
+      console.log('🔒 Feedback excluded from learning by privacy mode');
+      return;
+    }
+
     this.state.userInteractions.push({
       timestamp: Date.now(),
       feedback,
@@ -352,6 +461,10 @@ export class RnDCoordinator {
 
   async shutdown() {
     console.log('🔄 Shutting down R&D Module');
+    if (this.checkpointTimer) {
+      clearInterval(this.checkpointTimer);
+      this.checkpointTimer = null;
+    }
     try {
       // Only persist data if the data store is initialized
       if (
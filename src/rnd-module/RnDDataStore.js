@@ -43,7 +43,8 @@ export class RnDDataStore {
       dataSize: 0,
     };
 
-    this.initializeStorage();
+    // Callers that load at startup must wait for this first
+    this.ready = this.initializeStorage();
   }
 
   async initializeStorage() {
@@ -150,9 +151,10 @@ export class RnDDataStore {
   }
 
   async saveComponentData(data) {
+    // Keyed by this.files entry
     const components = {
       patterns: data.patterns || {},
-      learningData: data.learningData || {},
+      learning: data.learningData || {},
       projects: data.projects || {},
       insights: data.insights || {},
     };
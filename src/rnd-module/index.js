@@ -18,6 +18,7 @@ import { PatternRecognition } from './PatternRecognition.js';
 import { ProjectGenerator } from './ProjectGenerator.js';
 import { ProjectIntegration } from './ProjectIntegration.js';
 import { RnDDataStore } from './RnDDataStore.js';
+import { IndexMaintenance } from './IndexMaintenance.js';
 
 // Default configuration
 const DEFAULT_CONFIG = {
@@ -26,12 +27,32 @@ const DEFAULT_CONFIG = {
   memoryDecay: 0.95,
   adaptationThreshold: 0.8,
   maxMemorySize: 10000,
+  anomalyNeighbors: 10,
+
+  // Memory index settings
+  memoryIndex: 'auto', // auto, exact, lsh
+  memoryIndexThreshold: 50000, // auto switches to LSH at this capacity
+  lshTables: 8,
+  lshHashes: 4,
+  lshBucketWidth: 1.0,
+  reindexChunkSize: 500, // experiences indexed per event-loop turn
+  reindexJobHistory: 20,
 
   // R&D coordinator settings
   dormantPeriod: 7 * 24 * 60 * 60 * 1000, // 7 days
   learningThreshold: 0.7,
   activationTriggers: 5,
   maxProjectSuggestions: 3,
+  checkpointInterval: 10 * 60 * 1000, // save learned state every 10 minutes
+
+  // Learning throttle settings
+  throttleWindowSize: 20, // cycles
+  throttleErrorRate: 0.2,
+  throttleProcessingTime: 5000, // ms per cycle
+  throttleLoad: 0.85, // 1-min load average per CPU
+  throttleMemory: 0.9, // fraction of system memory in use
+  throttleRecoveryRatio: 0.5,
+  throttleRecoveryCycles: 3,
 
   // Pattern recognition settings
   minPatternOccurrence: 3,
@@ -72,6 +93,8 @@ export class RnDModule {
   constructor(config = {}) {
     this.config = { ...DEFAULT_CONFIG, ...config };
     this.coordinator = null;
+    this.privacyGuard = null;
+    this.indexMaintenance = null;
     this.initialized = false;
     this.startTime = Date.now();
 
@@ -94,8 +117,14 @@ export class RnDModule {
     try {
       // Initialize coordinator
       this.coordinator = new RnDCoordinator(this.config);
+      this.coordinator.privacyGuard = this.privacyGuard;
       await this.coordinator.initialize();
 
+      this.indexMaintenance = new IndexMaintenance(
+        this.coordinator.modules.learningAlgorithm,
+        this.config
+      );
+
       // Set up monitoring
       this.setupMonitoring();
 
@@ -230,6 +259,16 @@ export class RnDModule {
     }
   }
 
+  /**
+   * Attach a PrivacyGuard so privacy modes can exclude user content
+   */
+  attachPrivacyGuard(privacyGuard) {
+    this.privacyGuard = privacyGuard;
+    if (this.coordinator) {
+      this.coordinator.privacyGuard = privacyGuard;
+    }
+  }
+
   /**
    * Add user feedback to improve learning
    */
@@ -392,6 +431,57 @@ export class RnDModule {
     }
   }
 
+  /**
+   * Save learned state now rather than at the next scheduled checkpoint
+   */
+  async checkpoint() {
+    this.requireInitialized();
+    return this.coordinator.checkpoint({ force: true });
+  }
+
+  /**
+   * Memory index lag: experiences pending indexing and orphaned entries
+   */
+  getIndexHealth() {
+    this.requireInitialized();
+    return this.indexMaintenance.getHealth();
+  }
+
+  /**
+   * Compare the memory index with the memory bank; repair fixes the
+   * differences in place
+   */
+  verifyIndex(options = {}) {
+    this.requireInitialized();
+    return this.indexMaintenance.verify(options);
+  }
+
+  /**
+   * Start a background re-index: { scope: 'full' } or
+   * { scope: 'since', since: timestamp }. Returns the job; poll it with
+   * getReindexJob().
+   */
+  startReindex(options = {}) {
+    this.requireInitialized();
+    return this.indexMaintenance.startReindex(options);
+  }
+
+  getReindexJob(id) {
+    this.requireInitialized();
+    return this.indexMaintenance.getJob(id);
+  }
+
+  listReindexJobs() {
+    this.requireInitialized();
+    return this.indexMaintenance.listJobs();
+  }
+
+  requireInitialized() {
+    if (!this.initialized) {
+      throw new Error('R&D Module not initialized');
+    }
+  }
+
   /**
    * Get system health status
    */
@@ -431,6 +521,9 @@ export class RnDModule {
           this.coordinator.modules.learningAlgorithm.learningState.confidence,
       };
 
+      // Check memory index lag
+      health.components.memoryIndex = this.indexMaintenance.getHealth();
+
       // Check pattern recognition health
       health.components.patternRecognition = {
         status: 'healthy',
@@ -440,6 +533,13 @@ export class RnDModule {
           this.coordinator.modules.patternRecognition.state.activePatterns,
       };
 
+      // Check learning throttle; any backoff counts as degraded
+      const throttle = this.coordinator.throttle.getState();
+      health.components.learningThrottle = {
+        status: throttle.level === 'normal' ? 'healthy' : 'degraded',
+        ...throttle,
+      };
+
       // Overall health assessment
       const unhealthyComponents = Object.values(health.components).filter(
         (c) => c.status === 'unhealthy' || c.status === 'error'
@@ -554,6 +654,7 @@ export {
   ProjectGenerator,
   ProjectIntegration,
   RnDDataStore,
+  IndexMaintenance,
 };
 
 // Export default configuration